@@ -0,0 +1,83 @@
+package controllers
+
+// imports
+import (
+	"net/http"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// api key controller
+type APIKeyController struct {
+	apiKeyUseCase domain.APIKeyUseCase        // api key usecase for key operations
+}
+
+// new api key controller
+func NewAPIKeyController(uc domain.APIKeyUseCase) *APIKeyController {
+	return &APIKeyController{apiKeyUseCase: uc}        // return new api key controller instance
+}
+
+// mints a key for the calling admin, the raw key is only in this response
+func (keyContr *APIKeyController) CreateAPIKey(c *gin.Context) {
+
+	var req domain.APIKeyRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// empty lifetime means the key never expires
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		var err error
+		ttl, err = time.ParseDuration(req.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			badRequest(c, "expires_in must be a positive duration like 720h")
+			return
+		}
+	}
+
+	// create key through usecase layer
+	rawKey, key, err := keyContr.apiKeyUseCase.CreateAPIKey(c.Request.Context(), c.GetString("userID"), req.Role, ttl)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{"api_key": rawKey, "key": key})        // secret is never shown again
+}
+
+// lists the keys of the calling admin, each identified by its id and prefix
+func (keyContr *APIKeyController) ListAPIKeys(c *gin.Context) {
+
+	// get keys through usecase layer
+	keys, err := keyContr.apiKeyUseCase.ListAPIKeys(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, keys)        // success response
+}
+
+// revokes the key in the path, it stops working on the next request
+func (keyContr *APIKeyController) RevokeAPIKey(c *gin.Context) {
+
+	id := c.Param("id")       // get key id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid API key ID format")
+		return
+	}
+
+	// revoke key through usecase layer
+	err = keyContr.apiKeyUseCase.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message":"API key revoked"})    // success response
+}
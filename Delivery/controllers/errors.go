@@ -0,0 +1,123 @@
+package controllers
+
+// imports
+import (
+	"errors"
+	"net/http"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// stable description of an error reported to clients
+type ErrorCode struct {
+	Code     string    `json:"code"`        // stable machine readable code, e.g. TASK_NOT_FOUND
+	Status   int       `json:"status"`      // http status the error is reported with
+	Message  string    `json:"message"`     // default human readable message
+}
+
+// codes for failures that are not tied to a single domain error
+var (
+	codeInvalidRequest    = ErrorCode{"INVALID_REQUEST", http.StatusBadRequest, "request is invalid"}
+	codeValidationFailed  = ErrorCode{"VALIDATION_FAILED", http.StatusBadRequest, "request body failed validation"}
+	codeInternalError     = ErrorCode{"INTERNAL_ERROR", http.StatusInternalServerError, "internal server error"}
+)
+
+// catalog entry linking a domain error to its client facing code
+type catalogEntry struct {
+	err   error
+	code  ErrorCode
+}
+
+// every known domain error and how it is reported - the single place to change error responses
+var errorCatalog = []catalogEntry{
+	{domain.ErrTaskNotFound, ErrorCode{"TASK_NOT_FOUND", http.StatusNotFound, domain.ErrTaskNotFound.Error()}},
+	{domain.ErrInvalidTaskID, ErrorCode{"INVALID_TASK_ID", http.StatusBadRequest, domain.ErrInvalidTaskID.Error()}},
+	{domain.ErrUserExists, ErrorCode{"USER_EXISTS", http.StatusConflict, domain.ErrUserExists.Error()}},
+	{domain.ErrUserNotFound, ErrorCode{"USER_NOT_FOUND", http.StatusNotFound, domain.ErrUserNotFound.Error()}},
+	{domain.ErrInvalidUserID, ErrorCode{"INVALID_USER_ID", http.StatusBadRequest, domain.ErrInvalidUserID.Error()}},
+	{domain.ErrInvalidCredentials, ErrorCode{"INVALID_CREDENTIALS", http.StatusUnauthorized, domain.ErrInvalidCredentials.Error()}},
+	{domain.ErrUnauthorized, ErrorCode{"UNAUTHORIZED", http.StatusUnauthorized, domain.ErrUnauthorized.Error()}},
+	{domain.ErrInvalidDueDate, ErrorCode{"INVALID_DUE_DATE", http.StatusBadRequest, domain.ErrInvalidDueDate.Error()}},
+	{domain.ErrDatabaseBusy, ErrorCode{"DATABASE_BUSY", http.StatusServiceUnavailable, domain.ErrDatabaseBusy.Error()}},
+	{domain.ErrInvalidSortField, ErrorCode{"INVALID_SORT_FIELD", http.StatusBadRequest, domain.ErrInvalidSortField.Error()}},
+	{domain.ErrInvalidLimit, ErrorCode{"INVALID_LIMIT", http.StatusBadRequest, domain.ErrInvalidLimit.Error()}},
+	{domain.ErrInvalidDays, ErrorCode{"INVALID_DAYS", http.StatusBadRequest, domain.ErrInvalidDays.Error()}},
+	{domain.ErrInvalidPriority, ErrorCode{"INVALID_PRIORITY", http.StatusBadRequest, domain.ErrInvalidPriority.Error()}},
+	{domain.ErrInvalidTimezone, ErrorCode{"INVALID_TIMEZONE", http.StatusBadRequest, domain.ErrInvalidTimezone.Error()}},
+	{domain.ErrInvalidPageSize, ErrorCode{"INVALID_PAGE_SIZE", http.StatusBadRequest, domain.ErrInvalidPageSize.Error()}},
+	{domain.ErrLastAdmin, ErrorCode{"LAST_ADMIN", http.StatusConflict, domain.ErrLastAdmin.Error()}},
+	{domain.ErrInvalidPage, ErrorCode{"INVALID_PAGE", http.StatusBadRequest, domain.ErrInvalidPage.Error()}},
+	{domain.ErrDescriptionTooLong, ErrorCode{"DESCRIPTION_TOO_LONG", http.StatusBadRequest, domain.ErrDescriptionTooLong.Error()}},
+	{domain.ErrWeakPassword, ErrorCode{"WEAK_PASSWORD", http.StatusBadRequest, domain.ErrWeakPassword.Error()}},
+	{domain.ErrDueDateNotWorkday, ErrorCode{"DUE_DATE_NOT_WORKDAY", http.StatusBadRequest, domain.ErrDueDateNotWorkday.Error()}},
+	{domain.ErrCreateRateExceeded, ErrorCode{"CREATE_RATE_EXCEEDED", http.StatusTooManyRequests, domain.ErrCreateRateExceeded.Error()}},
+	{domain.ErrCannotDeleteSelf, ErrorCode{"CANNOT_DELETE_SELF", http.StatusForbidden, domain.ErrCannotDeleteSelf.Error()}},
+	{domain.ErrPreconditionFailed, ErrorCode{"PRECONDITION_FAILED", http.StatusPreconditionFailed, domain.ErrPreconditionFailed.Error()}},
+	{domain.ErrPreconditionRequired, ErrorCode{"PRECONDITION_REQUIRED", http.StatusPreconditionRequired, domain.ErrPreconditionRequired.Error()}},
+	{domain.ErrInvalidEmail, ErrorCode{"INVALID_EMAIL", http.StatusBadRequest, domain.ErrInvalidEmail.Error()}},
+	{domain.ErrEmailExists, ErrorCode{"EMAIL_EXISTS", http.StatusConflict, domain.ErrEmailExists.Error()}},
+	{domain.ErrSameOwner, ErrorCode{"SAME_OWNER", http.StatusBadRequest, domain.ErrSameOwner.Error()}},
+	{domain.ErrInvalidAPIKey, ErrorCode{"INVALID_API_KEY", http.StatusUnauthorized, domain.ErrInvalidAPIKey.Error()}},
+	{domain.ErrAPIKeyNotFound, ErrorCode{"API_KEY_NOT_FOUND", http.StatusNotFound, domain.ErrAPIKeyNotFound.Error()}},
+	{domain.ErrAssignForbidden, ErrorCode{"ASSIGN_FORBIDDEN", http.StatusForbidden, domain.ErrAssignForbidden.Error()}},
+	{domain.ErrTaskBlocked, ErrorCode{"TASK_BLOCKED", http.StatusConflict, domain.ErrTaskBlocked.Error()}},
+	{domain.ErrDependencyCycle, ErrorCode{"DEPENDENCY_CYCLE", http.StatusBadRequest, domain.ErrDependencyCycle.Error()}},
+	{domain.ErrDependencyNotFound, ErrorCode{"DEPENDENCY_NOT_FOUND", http.StatusBadRequest, domain.ErrDependencyNotFound.Error()}},
+	{domain.ErrInvalidDueDateRange, ErrorCode{"INVALID_DUE_DATE_RANGE", http.StatusBadRequest, domain.ErrInvalidDueDateRange.Error()}},
+	{domain.ErrTaskInProgress, ErrorCode{"TASK_IN_PROGRESS", http.StatusConflict, domain.ErrTaskInProgress.Error()}},
+	{domain.ErrTooManyTasks, ErrorCode{"TOO_MANY_TASKS", http.StatusBadRequest, domain.ErrTooManyTasks.Error()}},
+	{domain.ErrTaskNotCompleted, ErrorCode{"TASK_NOT_COMPLETED", http.StatusConflict, domain.ErrTaskNotCompleted.Error()}},
+	{domain.ErrTaskCompletedLocked, ErrorCode{"TASK_COMPLETED_LOCKED", http.StatusForbidden, domain.ErrTaskCompletedLocked.Error()}},
+	{domain.ErrProjectNotFound, ErrorCode{"PROJECT_NOT_FOUND", http.StatusNotFound, domain.ErrProjectNotFound.Error()}},
+	{domain.ErrInvalidProjectID, ErrorCode{"INVALID_PROJECT_ID", http.StatusBadRequest, domain.ErrInvalidProjectID.Error()}},
+	{domain.ErrProjectOwnerMismatch, ErrorCode{"PROJECT_OWNER_MISMATCH", http.StatusBadRequest, domain.ErrProjectOwnerMismatch.Error()}},
+	{domain.ErrTaskTooLarge, ErrorCode{"TASK_TOO_LARGE", http.StatusRequestEntityTooLarge, domain.ErrTaskTooLarge.Error()}},
+	{domain.ErrTooManyDependencies, ErrorCode{"TOO_MANY_DEPENDENCIES", http.StatusBadRequest, domain.ErrTooManyDependencies.Error()}},
+	{domain.ErrDependencyTooDeep, ErrorCode{"DEPENDENCY_TOO_DEEP", http.StatusBadRequest, domain.ErrDependencyTooDeep.Error()}},
+	{domain.ErrInvalidStatusTransition, ErrorCode{"INVALID_STATUS_TRANSITION", http.StatusConflict, domain.ErrInvalidStatusTransition.Error()}},
+	{domain.ErrInvalidSearchQuery, ErrorCode{"INVALID_SEARCH_QUERY", http.StatusBadRequest, domain.ErrInvalidSearchQuery.Error()}},
+	{domain.ErrTooManyOwners, ErrorCode{"TOO_MANY_OWNERS", http.StatusBadRequest, domain.ErrTooManyOwners.Error()}},
+}
+
+// finds the catalog code for err, or a generic code based on the handler's fallback status
+func lookupError(err error, fallback int) ErrorCode {
+
+	for _, entry := range errorCatalog {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+
+	// unknown error - usecase validation messages are bad requests, everything else is internal
+	if fallback == http.StatusBadRequest {
+		return codeInvalidRequest
+	}
+	return ErrorCode{codeInternalError.Code, fallback, codeInternalError.Message}
+}
+
+// writes err with its catalog code and status
+func respondError(c *gin.Context, err error, fallback int) {
+	code := lookupError(err, fallback)
+	respond(c, code.Status, gin.H{"error": err.Error(), "code": code.Code})
+}
+
+// writes a 400 response for a request the handler rejected itself
+func badRequest(c *gin.Context, message string) {
+	respond(c, http.StatusBadRequest, gin.H{"error": message, "code": codeInvalidRequest.Code})
+}
+
+// lists every error code clients can receive
+func ErrorCatalog() []ErrorCode {
+
+	codes := make([]ErrorCode, 0, len(errorCatalog)+3)
+	for _, entry := range errorCatalog {
+		codes = append(codes, entry.code)
+	}
+
+	return append(codes, codeInvalidRequest, codeValidationFailed, codeInternalError)
+}
+
+// serves the error catalog for client authors
+func ListErrorCodes(c *gin.Context) {
+	respond(c, http.StatusOK, ErrorCatalog())
+}
@@ -0,0 +1,81 @@
+package controllers
+
+// imports
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for the error catalog
+type ErrorCatalogTestSuite struct {
+	suite.Suite
+}
+
+// tests representative domain errors map to their code and status
+func (suite *ErrorCatalogTestSuite) TestLookupError_KnownErrors() {
+
+	cases := map[error]ErrorCode{
+		domain.ErrTaskNotFound:        {"TASK_NOT_FOUND", http.StatusNotFound, domain.ErrTaskNotFound.Error()},
+		domain.ErrUserExists:          {"USER_EXISTS", http.StatusConflict, domain.ErrUserExists.Error()},
+		domain.ErrInvalidCredentials:  {"INVALID_CREDENTIALS", http.StatusUnauthorized, domain.ErrInvalidCredentials.Error()},
+		domain.ErrDatabaseBusy:        {"DATABASE_BUSY", http.StatusServiceUnavailable, domain.ErrDatabaseBusy.Error()},
+		domain.ErrInvalidSortField:    {"INVALID_SORT_FIELD", http.StatusBadRequest, domain.ErrInvalidSortField.Error()},
+	}
+
+	for err, expected := range cases {
+		assert.Equal(suite.T(), expected, lookupError(err, http.StatusInternalServerError), err.Error())      // catalog entry wins over fallback
+	}
+}
+
+// tests wrapped domain errors are still found
+func (suite *ErrorCatalogTestSuite) TestLookupError_Wrapped() {
+	err := fmt.Errorf("loading task: %w", domain.ErrTaskNotFound)
+	assert.Equal(suite.T(), "TASK_NOT_FOUND", lookupError(err, http.StatusInternalServerError).Code)       // unwrapped to the sentinel
+}
+
+// tests unknown errors use the handler's fallback status
+func (suite *ErrorCatalogTestSuite) TestLookupError_Unknown() {
+
+	badRequest := lookupError(errors.New("task title cannot be empty"), http.StatusBadRequest)
+	assert.Equal(suite.T(), codeInvalidRequest, badRequest)                      // usecase validation message
+
+	internal := lookupError(errors.New("connection reset"), http.StatusInternalServerError)
+	assert.Equal(suite.T(), "INTERNAL_ERROR", internal.Code)                     // generic internal code
+	assert.Equal(suite.T(), http.StatusInternalServerError, internal.Status)     // status should be 500
+}
+
+// tests the catalog endpoint lists every code exactly once
+func (suite *ErrorCatalogTestSuite) TestListErrorCodes() {
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/errors", ListErrorCodes)
+
+	req, _ := http.NewRequest(http.MethodGet, "/errors", nil)       // create test request
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var codes []ErrorCode
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                          // status should be 200
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &codes))       // body should be json
+	assert.Equal(suite.T(), ErrorCatalog(), codes)                             // every code is listed
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.False(suite.T(), seen[code.Code], code.Code)        // codes should be unique
+		seen[code.Code] = true
+	}
+}
+
+// runs the test suite for the error catalog
+func TestErrorCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorCatalogTestSuite))
+}
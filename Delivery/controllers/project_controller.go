@@ -0,0 +1,158 @@
+package controllers
+
+// imports
+import (
+	"net/http"
+	"strconv"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// project controller
+type ProjectController struct {
+	projectUseCase domain.ProjectUseCase        // project usecase for project operations
+}
+
+// new project controller
+func NewProjectController(uc domain.ProjectUseCase) *ProjectController {
+	return &ProjectController{projectUseCase: uc}        // return new project controller instance
+}
+
+// creates a project owned by the calling user
+func (projectContr *ProjectController) CreateProject(c *gin.Context) {
+
+	var req domain.ProjectRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// create project through usecase layer
+	project, err := projectContr.projectUseCase.CreateProject(c.Request.Context(), req.Name, c.GetString("userID"))
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	respond(c, http.StatusCreated, project)        // return new created project
+}
+
+// lists the caller's projects, admins see every project
+func (projectContr *ProjectController) ListProjects(c *gin.Context) {
+
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	// get projects through usecase layer
+	projects, err := projectContr.projectUseCase.ListProjects(c.Request.Context(), ownerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, projects)        // success response
+}
+
+// reads the project id from the path and the owner the request is limited to,
+// reports false after responding when either is missing or invalid
+func projectScope(c *gin.Context) (string, string, bool) {
+
+	id := c.Param("id")       // get project id from request parameter
+
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {       // validate it is a valid ObjectID
+		badRequest(c, "Invalid project ID format")
+		return "", "", false
+	}
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	return id, ownerID, true
+}
+
+func (projectContr *ProjectController) GetProject(c *gin.Context) {
+
+	id, ownerID, ok := projectScope(c)
+	if !ok {
+		return
+	}
+
+	// get specific project through usecase layer
+	project, err := projectContr.projectUseCase.GetProject(c.Request.Context(), id, ownerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, project)        // return found project
+}
+
+func (projectContr *ProjectController) GetProjectStats(c *gin.Context) {
+
+	id, ownerID, ok := projectScope(c)
+	if !ok {
+		return
+	}
+
+	// count the project's tasks through usecase layer
+	stats, err := projectContr.projectUseCase.ProjectStats(c.Request.Context(), id, ownerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, stats)        // status and priority breakdowns
+}
+
+func (projectContr *ProjectController) RenameProject(c *gin.Context) {
+
+	id, ownerID, ok := projectScope(c)
+	if !ok {
+		return
+	}
+	var req domain.ProjectRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// rename project through usecase layer
+	project, err := projectContr.projectUseCase.RenameProject(c.Request.Context(), id, ownerID, req.Name)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	respond(c, http.StatusOK, project)        // return renamed project
+}
+
+// deletes a project, its tasks stay without a project unless ?cascade=true deletes them too
+func (projectContr *ProjectController) DeleteProject(c *gin.Context) {
+
+	id, ownerID, ok := projectScope(c)
+	if !ok {
+		return
+	}
+	cascade := false
+	if raw := c.Query("cascade"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)       // parse ?cascade=
+		if err != nil {
+			badRequest(c, "cascade must be true or false")
+			return
+		}
+		cascade = parsed
+	}
+
+	// delete project through usecase layer
+	changed, err := projectContr.projectUseCase.DeleteProject(c.Request.Context(), id, ownerID, cascade)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "Project deleted", "tasks": changed})      // tasks detached or deleted
+}
@@ -0,0 +1,48 @@
+package controllers
+
+// imports
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"github.com/gin-gonic/gin"
+)
+
+// media type clients send in Accept to opt into enveloped responses
+const EnvelopeMediaType = "application/vnd.taskmgr.v2+json"
+
+// enveloped response body - data on success, error on failure
+type Envelope struct {
+	Data   interface{}  `json:"data,omitempty"`       // the legacy response body of a success
+	Error  interface{}  `json:"error,omitempty"`      // the legacy response body of a failure
+}
+
+// reports whether the client accepts enveloped responses
+func wantsEnvelope(c *gin.Context) bool {
+
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == EnvelopeMediaType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writes body as json, wrapped in an Envelope for clients that asked for one - legacy clients get the bare body
+func respond(c *gin.Context, status int, body interface{}) {
+
+	c.Writer.Header().Add("Vary", "Accept")        // the shape depends on the Accept header
+	if !wantsEnvelope(c) {
+		c.JSON(status, body)
+		return
+	}
+
+	envelope := Envelope{Data: body}
+	if status >= http.StatusBadRequest {
+		envelope = Envelope{Error: body}
+	}
+	c.Header("Content-Type", EnvelopeMediaType+"; charset=utf-8")
+	c.JSON(status, envelope)
+}
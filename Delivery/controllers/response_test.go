@@ -0,0 +1,111 @@
+package controllers
+
+// imports
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// test suite for the response envelope
+type ResponseTestSuite struct {
+	suite.Suite
+	router  *gin.Engine                        // gin router instance
+	mockUC  *mock_usecases.MockTaskUseCase     // mock task usecase instance
+}
+
+// intialize the test suite before each test
+func (suite *ResponseTestSuite) SetupTest() {
+
+	gin.SetMode(gin.TestMode)
+	suite.mockUC = new(mock_usecases.MockTaskUseCase)
+	controller := NewTaskController(suite.mockUC)
+
+	router := gin.New()
+	router.GET("/tasks", controller.GetAllTasks)        // list route, a bare array in the legacy shape
+	router.GET("/tasks/:id", controller.GetTaskByID)    // single task route
+	suite.router = router
+}
+
+// sends a request with the given Accept header
+func (suite *ResponseTestSuite) get(path, accept string) *httptest.ResponseRecorder {
+
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	return resp
+}
+
+// tests clients without the v2 media type keep the bare legacy shape
+func (suite *ResponseTestSuite) TestLegacyShape() {
+
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{{Title: "legacy"}}, nil)
+
+	for _, accept := range []string{"", "application/json", "*/*"} {
+		resp := suite.get("/tasks", accept)
+
+		var tasks []domain.Task
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)                                   // status should be 200
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &tasks), accept)        // bare array
+		assert.Equal(suite.T(), "legacy", tasks[0].Title)
+		assert.Contains(suite.T(), resp.Header().Get("Content-Type"), "application/json")
+		assert.Equal(suite.T(), "Accept", resp.Header().Get("Vary"))                        // caches keep the shapes apart
+	}
+}
+
+// tests clients accepting the v2 media type get data in an envelope
+func (suite *ResponseTestSuite) TestEnvelopedData() {
+
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{{Title: "enveloped"}}, nil)
+
+	resp := suite.get("/tasks", "application/json, "+EnvelopeMediaType+"; q=0.9")
+
+	var body struct {
+		Data   []domain.Task            `json:"data"`
+		Error  map[string]interface{}   `json:"error"`
+	}
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                                        // status should be 200
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &body))                      // body should be an object
+	assert.Equal(suite.T(), "enveloped", body.Data[0].Title)                                 // tasks under data
+	assert.Nil(suite.T(), body.Error)                                                        // no error member
+	assert.Contains(suite.T(), resp.Header().Get("Content-Type"), EnvelopeMediaType)         // media type echoed
+}
+
+// tests errors are enveloped under error for v2 clients only
+func (suite *ResponseTestSuite) TestEnvelopedError() {
+
+	id := primitive.NewObjectID().Hex()
+	suite.mockUC.On("GetTaskByID", mock.Anything, id).Return(nil, domain.ErrTaskNotFound)
+
+	// legacy clients get the bare error object
+	var legacy map[string]interface{}
+	resp := suite.get("/tasks/"+id, "")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)                      // status should be 404
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &legacy))
+	assert.Equal(suite.T(), "TASK_NOT_FOUND", legacy["code"])                    // code at the top level
+
+	// v2 clients get it under error
+	var enveloped map[string]map[string]interface{}
+	resp = suite.get("/tasks/"+id, EnvelopeMediaType)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)                      // status is unchanged
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &enveloped))
+	assert.Equal(suite.T(), "TASK_NOT_FOUND", enveloped["error"]["code"])        // code inside the envelope
+	assert.NotContains(suite.T(), enveloped, "data")                             // no data member
+}
+
+// runs the test suite for the response envelope
+func TestResponseTestSuite(t *testing.T) {
+	suite.Run(t, new(ResponseTestSuite))
+}
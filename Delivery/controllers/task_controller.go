@@ -2,46 +2,97 @@ package controllers
 
 // imports
 import (
+	"errors"
+	"hash/fnv"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// configurable request handling of the task controller
+type TaskControllerConfig struct {
+	AcceptEpochDueDates  bool        // allow due dates sent as unix epoch seconds or milliseconds
+	RequireIfMatch       bool        // reject updates without an If-Match header
+}
+
+// returns the default task controller settings
+func DefaultTaskControllerConfig() TaskControllerConfig {
+	return TaskControllerConfig{AcceptEpochDueDates: true}
+}
+
 // task controller
 type TaskController struct {
 	taskUseCase domain.TaskUseCase        // task usecase for task operations
+	config      TaskControllerConfig
 }
 
 // new task controller
 func NewTaskController(uc domain.TaskUseCase) *TaskController {
-	return &TaskController{taskUseCase: uc}        // return new task controller instance
+	return NewTaskControllerWithConfig(uc, DefaultTaskControllerConfig())
+}
+
+// new task controller with custom request handling
+func NewTaskControllerWithConfig(uc domain.TaskUseCase, config TaskControllerConfig) *TaskController {
+	return &TaskController{taskUseCase: uc, config: config}        // return new task controller instance
+}
+
+// reports an unparsable or disallowed due date
+func invalidDueDate(c *gin.Context) {
+	respond(c, http.StatusBadRequest, gin.H{
+		"error": "Invalid date format. Use ISO 8601 format like '2025-7-16T00:00:00Z'",
+		"code": codeInvalidRequest.Code,
+		"example": gin.H{
+			"due_date": "2025-07-22T00:00:00Z",
+		},
+	})
+}
+
+// parses a task body, rejecting epoch due dates unless they are enabled
+func (taskContr *TaskController) bindTask(c *gin.Context) (*domain.Task, error) {
+
+	var in taskInput
+	if err := decodeJSON(c, &in); err != nil {
+		return nil, err
+	}
+	if in.DueDate.epoch && !taskContr.config.AcceptEpochDueDates {
+		return nil, errEpochDueDate
+	}
+
+	task := in.task()
+	return &task, nil
 }
 
 
 func (taskContr *TaskController) CreateTask(c *gin.Context) {
 	
-	var task domain.Task
-	err := c.ShouldBindJSON(&task)      // parse request body into task struct
+	task, err := taskContr.bindTask(c)      // parse request body into task struct
 	if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
-        return
-    }
+		if errors.Is(err, errEpochDueDate) {
+			invalidDueDate(c)
+			return
+		}
+		respondBindError(c, err, &taskInput{})
+		return
+	}
 
-	if task.Title == "" || task.Description == "" || task.Status == "" || task.DueDate.IsZero() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "all fields must be set"})
+	// report every missing field at once
+	if fields := missingTaskFields(task); len(fields) > 0 {
+		respond(c, http.StatusBadRequest, gin.H{"errors": fields, "code": codeValidationFailed.Code})
 		return
 	}
 	
 	// create task through usecase layer
-	createdTask, err := taskContr.taskUseCase.CreateTask(&task)
+	createdTask, err := taskContr.taskUseCase.CreateTask(c.Request.Context(), task, c.GetString("userID"))      // authenticated user owns the task
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdTask)        // return created task with 201 status
+	respond(c, http.StatusCreated, createdTask)        // return created task with 201 status
 }
 
 func (taskContr *TaskController) DeleteTask(c *gin.Context) {
@@ -50,39 +101,518 @@ func (taskContr *TaskController) DeleteTask(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		badRequest(c, "Invalid task ID format")
 		return
 	}
 
 	// delete task through usecase layer
-	err = taskContr.taskUseCase.DeleteTask(id)
+	err = taskContr.taskUseCase.DeleteTask(c.Request.Context(), id)
 	if err != nil {
-		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusInternalServerError)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message":"task deleted successfully"})    // success response
+	respond(c, http.StatusOK, gin.H{"message":"task deleted successfully"})    // success response
+}
+
+// brings back a soft deleted task
+func (taskContr *TaskController) RestoreTask(c *gin.Context) {
+
+	id := c.Param("id")       // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid task ID format")
+		return
+	}
+
+	// restore task through usecase layer
+	err = taskContr.taskUseCase.RestoreTask(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message":"task restored successfully"})    // success response
+}
+
+func (taskContr *TaskController) ReopenTask(c *gin.Context) {
+
+	id := c.Param("id")       // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid task ID format")
+		return
+	}
+
+	// reopen task through usecase layer
+	task, err := taskContr.taskUseCase.ReopenTask(c.Request.Context(), id, c.GetString("userID"), c.GetString("role"))      // usecase decides who may reopen
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, task)    // success response
+}
+
+func (taskContr *TaskController) AssignTask(c *gin.Context) {
+
+	id := c.Param("id")       // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid task ID format")
+		return
+	}
+
+	var req domain.AssignRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// assign task through usecase layer
+	err = taskContr.taskUseCase.AssignTask(c.Request.Context(), id, req.UserID, c.GetString("userID"), c.GetString("role"))      // usecase decides who may assign to whom
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message":"task assigned successfully"})    // success response
+}
+
+// moves an own task into one of the user's projects, admins move any task
+func (taskContr *TaskController) MoveTask(c *gin.Context) {
+
+	id := c.Param("id")       // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid task ID format")
+		return
+	}
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.MoveTaskRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+	projectID := ""
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+
+	// move task through usecase layer
+	task, err := taskContr.taskUseCase.MoveTask(c.Request.Context(), id, projectID, ownerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, task)    // success response
+}
+
+// completes every task in ids that may become completed
+func (taskContr *TaskController) CompleteTasks(c *gin.Context) {
+
+	var req domain.CompleteTasksRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// complete tasks through usecase layer
+	completed, err := taskContr.taskUseCase.CompleteTasks(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"completed": completed})    // number of tasks that changed status
+}
+
+// moves every task of the user in the path to new_owner_id
+func (taskContr *TaskController) ReassignTasks(c *gin.Context) {
+
+	id := c.Param("id")       // get user id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid user ID format")
+		return
+	}
+
+	var req domain.ReassignRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// reassign tasks through usecase layer
+	moved, err := taskContr.taskUseCase.ReassignTasks(c.Request.Context(), id, req.NewOwnerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"moved": moved})    // number of tasks that changed owner
 }
 
 func (taskContr *TaskController) GetAllTasks(c *gin.Context) {
 	
-	// get all tasks through usecase layer
-	tasks, err := taskContr.taskUseCase.GetAllTasks()
+	var tasks []domain.Task
+	var err error
+
+	keyword, hasKeyword := c.GetQuery("q")         // e.g. ?q=report
+	projectID, hasProject := c.GetQuery("project") // project id
+	dueFrom, hasDueFrom := c.GetQuery("due_from")  // RFC 3339, e.g. ?due_from=2025-07-01T00:00:00Z
+	dueTo, hasDueTo := c.GetQuery("due_to")        // RFC 3339, inclusive
+	sortField, hasSort := c.GetQuery("sort")       // e.g. ?sort=due_date
+	order, hasOrder := c.GetQuery("order")         // asc or desc
+
+	if hasKeyword {
+		// search titles and descriptions through usecase layer, newest first
+		tasks, err = taskContr.taskUseCase.SearchTasks(c.Request.Context(), keyword)
+	} else if hasProject {
+		ownerID, ok := ownerScope(c)
+		if !ok {
+			respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		// get the project's tasks through usecase layer, newest first, users only list their own projects
+		tasks, err = taskContr.taskUseCase.GetTasksByProject(c.Request.Context(), projectID, ownerID)
+	} else if hasDueFrom || hasDueTo {
+		// a missing bound leaves that side of the window open
+		var from, to time.Time
+		if hasDueFrom {
+			if from, err = time.Parse(time.RFC3339, dueFrom); err != nil {
+				badRequest(c, "due_from must be an RFC 3339 time like 2025-07-01T00:00:00Z")
+				return
+			}
+		}
+		if hasDueTo {
+			if to, err = time.Parse(time.RFC3339, dueTo); err != nil {
+				badRequest(c, "due_to must be an RFC 3339 time like 2025-07-31T23:59:59Z")
+				return
+			}
+		}
+		// get tasks due within the window through usecase layer, soonest first
+		tasks, err = taskContr.taskUseCase.GetTasksByDueDateRange(c.Request.Context(), from, to)
+	} else if hasSort || hasOrder {
+		// default to newest first
+		if sortField == "" {
+			sortField = "created_at"
+		}
+		if order != "" && order != "asc" && order != "desc" {
+			badRequest(c, "order must be asc or desc")
+			return
+		}
+		// get sorted tasks through usecase layer
+		tasks, err = taskContr.taskUseCase.GetTasksSorted(c.Request.Context(), sortField, order == "asc")
+	} else {
+		// get all tasks through usecase layer
+		tasks, err = taskContr.taskUseCase.GetAllTasks(c.Request.Context())
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	if len(tasks) == 0 {
-		c.JSON(http.StatusOK, []domain.Task{})
+		respond(c, http.StatusOK, []domain.Task{})
+		return
+	}
+
+	// conditional GET - polling clients get 304 while no listed task changed
+	// the etag also changes when a task leaves the list or two edits share a second
+	lastModified := latestUpdate(tasks)
+	etag := listETag(tasks, lastModified)
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", roundUpToSecond(lastModified).UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
-	c.JSON(http.StatusOK, tasks)       // return all tasks
+	respond(c, http.StatusOK, tasks)       // return all tasks
+}
+
+// newest UpdatedAt among tasks, zero when none has one
+func latestUpdate(tasks []domain.Task) time.Time {
+
+	var latest time.Time
+	for _, task := range tasks {
+		if task.UpdatedAt.After(latest) {
+			latest = task.UpdatedAt
+		}
+	}
+
+	return latest
+}
+
+// version of a task list from its ids, their count and the newest update in milliseconds
+func listETag(tasks []domain.Task, lastModified time.Time) string {
+
+	hash := fnv.New64a()
+	for _, task := range tasks {
+		hash.Write(task.ID[:])
+	}
+
+	return `"` + strconv.Itoa(len(tasks)) + "-" + strconv.FormatInt(lastModified.UnixMilli(), 36) + "-" + strconv.FormatUint(hash.Sum64(), 36) + `"`
+}
+
+// Last-Modified has second precision, rounding up never reports a change as older than it is
+func roundUpToSecond(t time.Time) time.Time {
+
+	if truncated := t.Truncate(time.Second); !truncated.Equal(t) {
+		return truncated.Add(time.Second)
+	}
+
+	return t
+}
+
+// reports whether the client's copy is current, If-None-Match wins over If-Modified-Since
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false        // missing or malformed header - send the full list
+	}
+
+	return !lastModified.After(since)
+}
+
+// owner a listing is limited to - empty for admins, who see every task
+// reports false when a non-admin request carries no user id
+func ownerScope(c *gin.Context) (string, bool) {
+
+	if c.GetString("role") == "admin" {
+		return "", true
+	}
+	userID := c.GetString("userID")
+
+	return userID, userID != ""
+}
+
+// number of tasks returned by the recent activity view when no limit is given
+const defaultRecentLimit = 10
+
+func (taskContr *TaskController) GetRecentlyUpdated(c *gin.Context) {
+
+	limit := int64(defaultRecentLimit)
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)       // parse ?limit=
+		if err != nil {
+			badRequest(c, "limit must be a number")
+			return
+		}
+		limit = parsed
+	}
+
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	// get recently updated tasks through usecase layer
+	tasks, err := taskContr.taskUseCase.GetRecentlyUpdated(c.Request.Context(), ownerID, limit)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, tasks)       // return recent tasks
+}
+
+// window used by the upcoming tasks view when no days are given
+const defaultUpcomingDays = 7
+
+func (taskContr *TaskController) GetUpcomingTasks(c *gin.Context) {
+
+	days := defaultUpcomingDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)       // parse ?days=
+		if err != nil {
+			badRequest(c, "days must be a number")
+			return
+		}
+		days = parsed
+	}
+
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	// get upcoming tasks through usecase layer
+	tasks, err := taskContr.taskUseCase.GetUpcomingTasks(c.Request.Context(), ownerID, days)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, tasks)       // return upcoming tasks
+}
+
+func (taskContr *TaskController) GetOverdueTasks(c *gin.Context) {
+
+	ownerID, ok := ownerScope(c)
+	if !ok {
+		respondError(c, domain.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	// get overdue tasks through usecase layer
+	tasks, err := taskContr.taskUseCase.GetOverdueTasks(c.Request.Context(), ownerID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, tasks)       // return overdue tasks
+}
+
+// page size of paginated listings when none is given
+const defaultPageSize = 20
+
+// reads ?page= and ?page_size= (or its alias ?limit=), defaulting to the first page
+func pageParams(c *gin.Context) (int, int, bool) {
+
+	page, pageSize := 1, defaultPageSize
+	if raw := c.Query("page"); raw != "" {
+		parsed, err := strconv.Atoi(raw)       // parse ?page=
+		if err != nil {
+			badRequest(c, "page must be a number")
+			return 0, 0, false
+		}
+		page = parsed
+	}
+	raw := c.Query("page_size")
+	if raw == "" {
+		raw = c.Query("limit")
+	}
+	if raw != "" {
+		parsed, err := strconv.Atoi(raw)       // parse ?page_size=
+		if err != nil {
+			badRequest(c, "page_size must be a number")
+			return 0, 0, false
+		}
+		pageSize = parsed
+	}
+
+	return page, pageSize, true
+}
+
+func (taskContr *TaskController) GetTasksByOwner(c *gin.Context) {
+
+	userID := c.Param("id")       // get user id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid user ID format")
+		return
+	}
+
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	// get the user's tasks through usecase layer
+	tasks, total, err := taskContr.taskUseCase.GetTasksByOwner(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))       // all of the user's tasks, for page controls
+
+	respond(c, http.StatusOK, tasks)       // return the requested page
+}
+
+func (taskContr *TaskController) GetTasksByUser(c *gin.Context) {
+
+	userID := c.GetString("userID")       // authenticated user, set by auth middleware
+
+	// get the tasks assigned to the user through usecase layer
+	tasks, err := taskContr.taskUseCase.GetTasksByUser(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, tasks)       // return assigned tasks
+}
+
+func (taskContr *TaskController) GetTaskStats(c *gin.Context) {
+
+	// count tasks per status through usecase layer
+	stats, err := taskContr.taskUseCase.TaskStats(c.Request.Context())
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, stats)       // status -> count
+}
+
+func (taskContr *TaskController) GetStatsByOwner(c *gin.Context) {
+
+	var req domain.OwnerStatsRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// count the owners' tasks through usecase layer
+	stats, err := taskContr.taskUseCase.GetStatsByOwner(c.Request.Context(), req.OwnerIDs)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, stats)       // owner id -> status -> count
+}
+
+// lists the workload of every assignee, ?include_tasks=true adds the tasks to the counts
+func (taskContr *TaskController) GetTasksByAssignee(c *gin.Context) {
+
+	includeTasks := false
+	if raw := c.Query("include_tasks"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)       // parse ?include_tasks=
+		if err != nil {
+			badRequest(c, "include_tasks must be true or false")
+			return
+		}
+		includeTasks = parsed
+	}
+
+	// group the assigned tasks through usecase layer
+	groups, err := taskContr.taskUseCase.GetTasksGroupedByAssignee(c.Request.Context(), includeTasks)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, groups)       // busiest assignee first
 }
 
 func (taskContr *TaskController) GetTaskByID(c *gin.Context) {
@@ -91,22 +621,40 @@ func (taskContr *TaskController) GetTaskByID(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)      // validate it is a valid ObjectID
 	if err != nil {      
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		badRequest(c, "Invalid task ID format")
 		return
 	}
 
 	// get specific task through usecase layer
-	task, err := taskContr.taskUseCase.GetTaskByID(id)
+	task, err := taskContr.taskUseCase.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
-		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("ETag", task.ETag())       // send back as If-Match to update safely
+	respond(c, http.StatusOK, task)       // return found task 
+}
+
+// lists the tasks that must be finished before the task in the path can start
+func (taskContr *TaskController) GetTaskDependencies(c *gin.Context) {
+
+	id := c.Param("id")        // get task id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(id)      // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid task ID format")
 		return
 	}
 
-	c.JSON(http.StatusOK, task)       // return found task 
+	// get the task's dependencies through usecase layer
+	deps, err := taskContr.taskUseCase.GetDependencies(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, deps)       // return dependencies, finished ones included
 }
 
 func (taskContr *TaskController) UpdateTask(c *gin.Context) {
@@ -115,37 +663,39 @@ func (taskContr *TaskController) UpdateTask(c *gin.Context) {
 
 	_, err := primitive.ObjectIDFromHex(id)        // validate it is a valid ObjectID
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		badRequest(c, "Invalid task ID format")
 		return
 	}
 
-	var task domain.Task
-	err = c.ShouldBindJSON(&task)       // parse request body into task struct
+	task, err := taskContr.bindTask(c)       // parse request body into task struct
 	if err != nil {
 		// handle specific date format error case
-		if strings.Contains(err.Error(), "numeric literal") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid date format. Use ISO 8601 format like '2025-7-16T00:00:00Z'",
-				"example": gin.H{
-					"due_date": "2025-07-22T00:00:00Z",
-				},
-			})
+		var parseErr *time.ParseError
+		if errors.Is(err, errEpochDueDate) || errors.As(err, &parseErr) {
+			invalidDueDate(c)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err, &taskInput{})
 		return
 	}
 
-	// update task through usecase layer
-	updatedTask, err := taskContr.taskUseCase.UpdateTask(id, &task)
+	// update task through usecase layer, guarded by If-Match when the client sent one
+	var updatedTask *domain.Task
+	ifMatch := c.GetHeader("If-Match")
+	switch {
+	case ifMatch != "":
+		updatedTask, err = taskContr.taskUseCase.UpdateTaskIfMatch(c.Request.Context(), id, task, ifMatch, c.GetString("role"))
+	case taskContr.config.RequireIfMatch:
+		respondError(c, domain.ErrPreconditionRequired, http.StatusPreconditionRequired)
+		return
+	default:
+		updatedTask, err = taskContr.taskUseCase.UpdateTask(c.Request.Context(), id, task, c.GetString("role"))      // usecase decides what the role may change
+	}
 	if err != nil {
-		if err == domain.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})       
+		respondError(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{ "message":"task updated successfully", "updated_task":updatedTask})       // success response
+	c.Header("ETag", updatedTask.ETag())       // version after the update
+	respond(c, http.StatusOK, gin.H{ "message":"task updated successfully", "updated_task":updatedTask})       // success response
 }
\ No newline at end of file
@@ -5,15 +5,20 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // test suite of TaskController
@@ -24,6 +29,9 @@ type TaskControllerTestSuite struct {
 	controller *TaskController // task controller instance being tested
 }
 
+// user id the test router treats as authenticated
+var taskTestUserID = "60d5ec49f9a3c7001c5b2b0a"
+
 // intialize the test suite before each test
 func (suite *TaskControllerTestSuite) SetupTest() {
 	
@@ -33,11 +41,28 @@ func (suite *TaskControllerTestSuite) SetupTest() {
 
 	// setup test router with all task routes
 	router := gin.Default()      // create new gin router
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", taskTestUserID)                 // stands in for the auth middleware
+		c.Set("role", c.GetHeader("X-Test-Role"))       // non-admin unless the test says otherwise
+	})
 	router.POST("/tasks", suite.controller.CreateTask)          // create task route
 	router.GET("/tasks", suite.controller.GetAllTasks)          // get all tasks route
+	router.GET("/tasks/recent", suite.controller.GetRecentlyUpdated)     // get recently updated tasks route
+	router.GET("/tasks/export", suite.controller.ExportTasksCSV)         // csv export route
+	router.GET("/tasks/overdue", suite.controller.GetOverdueTasks)       // overdue tasks route
+	router.GET("/tasks/stats", suite.controller.GetTaskStats)            // status counts route
+	router.GET("/tasks/by-assignee", suite.controller.GetTasksByAssignee)      // workload per assignee route
 	router.GET("/tasks/:id", suite.controller.GetTaskByID)      // get task by ID route
+	router.GET("/tasks/:id/dependencies", suite.controller.GetTaskDependencies)      // task dependencies route
+	router.GET("/users/:id/tasks", suite.controller.GetTasksByOwner)     // user's tasks route
+	router.GET("/mytasks", suite.controller.GetTasksByUser)     // own assigned tasks route
 	router.PUT("/tasks/:id", suite.controller.UpdateTask)       // update task route
 	router.DELETE("/tasks/:id", suite.controller.DeleteTask)    // delete task route
+	router.POST("/tasks/:id/restore", suite.controller.RestoreTask)     // restore task route
+	router.POST("/users/:id/reassign-tasks", suite.controller.ReassignTasks)     // reassign user's tasks route
+	router.POST("/tasks/complete", suite.controller.CompleteTasks)      // bulk complete tasks route
+	router.PATCH("/tasks/:id/move", suite.controller.MoveTask)  // move task between projects route
+	router.POST("/tasks/:id/reopen", suite.controller.ReopenTask)       // reopen task route
 
 	suite.router = router
 }
@@ -54,12 +79,12 @@ func (suite *TaskControllerTestSuite) TestCreateTask_Success() {
 	}
 
 	// mock CreateTask method to return the mock task
-	suite.mockUC.On("CreateTask", mock.MatchedBy(func(t *domain.Task) bool {
+	suite.mockUC.On("CreateTask", mock.Anything, mock.MatchedBy(func(t *domain.Task) bool {
 		return t.Title == mockTask.Title &&
 			t.Description == mockTask.Description &&
 			t.Status == mockTask.Status &&
 			t.DueDate.Equal(mockTask.DueDate)
-	})).Return(mockTask, nil)
+	}), taskTestUserID).Return(mockTask, nil)
 
 	// create test request with JSON body
 	body, _ := json.Marshal(mockTask)
@@ -75,6 +100,248 @@ func (suite *TaskControllerTestSuite) TestCreateTask_Success() {
 	suite.mockUC.AssertExpectations(suite.T())                        // verify mock was called as expected
 }
 
+// tests task creation with due dates sent as epoch milliseconds and seconds
+func (suite *TaskControllerTestSuite) TestCreateTask_EpochDueDate() {
+
+	due := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	bodies := map[string]string{
+		"millis":  `{"title":"Epoch","description":"epoch due date","status":"pending","due_date":` + strconv.FormatInt(due.UnixMilli(), 10) + `}`,
+		"seconds": `{"title":"Epoch","description":"epoch due date","status":"pending","due_date":` + strconv.FormatInt(due.Unix(), 10) + `}`,
+	}
+
+	// mock CreateTask method to expect the converted utc due date
+	suite.mockUC.On("CreateTask", mock.Anything, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.DueDate.Equal(due) && t.DueDate.Location() == time.UTC
+	}), taskTestUserID).Return(&domain.Task{DueDate: due}, nil).Twice()
+
+	for name, body := range bodies {
+		req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))      // create test request
+		req.Header.Set("Content-Type", "application/json")       // set content type header
+		w := httptest.NewRecorder()
+
+		// serve the request using the router
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusCreated, w.Code, name)       // status should be 201
+	}
+	suite.mockUC.AssertExpectations(suite.T())              // both due dates converted
+}
+
+// tests epoch due dates are rejected when disabled
+func (suite *TaskControllerTestSuite) TestCreateTask_EpochDueDateDisabled() {
+
+	// controller configured for RFC 3339 due dates only
+	controller := NewTaskControllerWithConfig(suite.mockUC, TaskControllerConfig{AcceptEpochDueDates: false})
+	router := gin.New()
+	router.POST("/tasks", controller.CreateTask)
+
+	body := `{"title":"Epoch","description":"epoch due date","status":"pending","due_date":1893553445}`
+	req, _ := http.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")       // set content type header
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)                                       // status should be 400
+	suite.Contains(w.Body.String(), "example")                                       // format hint included
+	suite.mockUC.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything, mock.Anything)      // usecase should not be called
+}
+
+// tests an unparsable due date string gets the format hint
+func (suite *TaskControllerTestSuite) TestUpdateTask_InvalidDueDateFormat() {
+
+	body := `{"due_date":"2025-7-16"}`
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/60d5ec49f9a3c7001c5b2b0a", strings.NewReader(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")       // set content type header
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)              // status should be 400
+	suite.Contains(w.Body.String(), "Invalid date format")  // format hint included
+}
+
+// tests a user's tasks are listed with the requested page
+func (suite *TaskControllerTestSuite) TestGetTasksByOwner_Pagination() {
+
+	owner := "60d5ec49f9a3c7001c5b2b0b"
+	suite.mockUC.On("GetTasksByOwner", mock.Anything, owner, 2, 5).Return([]domain.Task{{Title: "theirs"}}, int64(6), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/"+owner+"/tasks?page=2&page_size=5", nil)      // create test request
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+	suite.Contains(w.Body.String(), "theirs")            // task should be in response body
+	suite.Equal("6", w.Header().Get("X-Total-Count"))    // total of all pages
+	suite.mockUC.AssertExpectations(suite.T())           // page parameters passed through
+}
+
+// tests the first page of the default size is used without parameters
+func (suite *TaskControllerTestSuite) TestGetTasksByOwner_DefaultPage() {
+
+	owner := "60d5ec49f9a3c7001c5b2b0b"
+	suite.mockUC.On("GetTasksByOwner", mock.Anything, owner, 1, defaultPageSize).Return([]domain.Task{}, int64(0), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/"+owner+"/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+	suite.mockUC.AssertExpectations(suite.T())           // defaults passed through
+}
+
+// tests the authenticated user's assigned tasks are listed
+func (suite *TaskControllerTestSuite) TestGetTasksByUser_Success() {
+
+	suite.mockUC.On("GetTasksByUser", mock.Anything, taskTestUserID).Return([]domain.Task{{Title: "assigned"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/mytasks", nil)      // create test request
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+	suite.Contains(w.Body.String(), "assigned")          // task should be in response body
+	suite.mockUC.AssertExpectations(suite.T())           // user id taken from the context
+}
+
+// tests invalid user ids and page parameters are rejected
+func (suite *TaskControllerTestSuite) TestGetTasksByOwner_InvalidInput() {
+
+	for _, url := range []string{"/users/invalid/tasks", "/users/60d5ec49f9a3c7001c5b2b0b/tasks?page=first"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)      // create test request
+		w := httptest.NewRecorder()
+
+		// serve the request using the router
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code, url)      // status should be 400
+	}
+	suite.mockUC.AssertNotCalled(suite.T(), "GetTasksByOwner", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests the task list answers 304 to a repeated poll when nothing changed
+func (suite *TaskControllerTestSuite) TestGetAllTasks_NotModified() {
+
+	updated := time.Date(2025, 7, 1, 12, 30, 15, 500, time.UTC)
+	tasks := []domain.Task{
+		{Title: "older", UpdatedAt: updated.Add(-time.Hour)},
+		{Title: "newer", UpdatedAt: updated},
+	}
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return(tasks, nil).Twice()
+
+	// first poll gets the list and its Last-Modified
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	lastModified := w.Header().Get("Last-Modified")
+	suite.Equal(http.StatusOK, w.Code)                                            // status should be 200
+	suite.Equal("Tue, 01 Jul 2025 12:30:16 GMT", lastModified)                    // newest UpdatedAt, rounded up
+
+	// second poll with If-Modified-Since and no changes
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	req.Header.Set("If-Modified-Since", lastModified)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotModified, w.Code)      // status should be 304
+	suite.Empty(w.Body.String())                     // no body
+}
+
+// tests a task changed after If-Modified-Since returns the full list
+func (suite *TaskControllerTestSuite) TestGetAllTasks_ModifiedSince() {
+
+	updated := time.Date(2025, 7, 1, 12, 30, 15, 0, time.UTC)
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{{Title: "changed", UpdatedAt: updated}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	req.Header.Set("If-Modified-Since", updated.Add(-time.Minute).Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+	suite.Contains(w.Body.String(), "changed")           // task should be in response body
+}
+
+// tests a poll after a task was deleted returns the shorter list
+func (suite *TaskControllerTestSuite) TestGetAllTasks_DeletedSincePoll() {
+
+	updated := time.Date(2025, 7, 1, 12, 30, 15, 0, time.UTC)
+	kept := domain.Task{ID: primitive.NewObjectID(), Title: "kept", UpdatedAt: updated}
+	deleted := domain.Task{ID: primitive.NewObjectID(), Title: "deleted", UpdatedAt: updated.Add(-time.Hour)}
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{kept, deleted}, nil).Once()
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{kept}, nil).Once()
+
+	// first poll gets the list and its validators
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+
+	// second poll after the delete, newest update is unchanged
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	req.Header.Set("If-Modified-Since", w.Header().Get("Last-Modified"))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                         // status should be 200
+	suite.NotContains(w.Body.String(), "deleted")              // deleted task is gone
+}
+
+// tests a poll after an update within the same second returns the new list
+func (suite *TaskControllerTestSuite) TestGetAllTasks_UpdatedSameSecond() {
+
+	updated := time.Date(2025, 7, 1, 12, 30, 15, 200*int(time.Millisecond), time.UTC)
+	id := primitive.NewObjectID()
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{{ID: id, Title: "before", UpdatedAt: updated}}, nil).Once()
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return([]domain.Task{{ID: id, Title: "after", UpdatedAt: updated.Add(500 * time.Millisecond)}}, nil).Once()
+
+	// first poll gets the list and its validators
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+
+	// second poll after an edit in the same second
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	req.Header.Set("If-Modified-Since", w.Header().Get("Last-Modified"))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                   // status should be 200
+	suite.Contains(w.Body.String(), "after")             // updated task in response body
+}
+
+// tests the task list answers 304 to a matching If-None-Match
+func (suite *TaskControllerTestSuite) TestGetAllTasks_NoneMatch() {
+
+	tasks := []domain.Task{{ID: primitive.NewObjectID(), Title: "same", UpdatedAt: time.Date(2025, 7, 1, 12, 30, 15, 0, time.UTC)}}
+	suite.mockUC.On("GetAllTasks", mock.Anything).Return(tasks, nil).Twice()
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	req.Header.Set("If-None-Match", `"other", W/` + w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotModified, w.Code)      // status should be 304
+	suite.Empty(w.Body.String())                     // no body
+}
+
 // tests task creation with invalid input
 func (suite *TaskControllerTestSuite) TestCreateTask_InvalidInput() {
 	
@@ -110,12 +377,85 @@ func (suite *TaskControllerTestSuite) TestCreateTask_MissingFields() {
     suite.Contains(w.Body.String(), "error")          // should contain error message
 }
 
+// tests task creation with a wrongly typed field names the field and expected type
+func (suite *TaskControllerTestSuite) TestCreateTask_TypeMismatch() {
+
+    body := []byte(`{"title":5,"description":"A test task","due_date":"2030-07-30T00:00:00Z","status":"pending"}`)
+
+    req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+
+    suite.router.ServeHTTP(w, req)
+
+    var resp map[string]string
+    suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+    suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))        // body should be json
+    suite.Equal("invalid type for field 'title' at offset 10, expected string", resp["error"])
+    suite.Equal("INVALID_REQUEST", resp["code"])
+    suite.mockUC.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything, mock.Anything)      // usecase not reached
+}
+
+// tests task creation with broken json reports where it broke
+func (suite *TaskControllerTestSuite) TestCreateTask_SyntaxError() {
+
+    body := []byte(`{"title":"Test Task",}`)
+
+    req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+
+    suite.router.ServeHTTP(w, req)
+
+    var resp map[string]string
+    suite.Equal(http.StatusBadRequest, w.Code)                                  // status should be 400
+    suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))                        // body should be json
+    suite.True(strings.HasPrefix(resp["error"], "malformed JSON at offset 22:"), resp["error"])        // offset of the stray brace
+    suite.Equal("INVALID_REQUEST", resp["code"])
+    suite.mockUC.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything, mock.Anything)      // usecase not reached
+}
+
+// tests task creation without a title reports the missing field
+func (suite *TaskControllerTestSuite) TestCreateTask_MissingTitle() {
+
+    body := []byte(`{"description":"A test task","due_date":"2030-07-30T00:00:00Z","status":"pending"}`)
+
+    req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(body))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+
+    suite.router.ServeHTTP(w, req)
+
+    var resp struct {
+        Errors map[string]string `json:"errors"`
+        Code   string            `json:"code"`
+    }
+    suite.Equal(http.StatusBadRequest, w.Code)                              // status should be 400
+    suite.NoError(json.Unmarshal(w.Body.Bytes(), &resp))                    // body should be json
+    suite.Equal(map[string]string{"title": "required"}, resp.Errors)        // only the title is missing
+    suite.Equal("VALIDATION_FAILED", resp.Code)
+    suite.mockUC.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything, mock.Anything)      // usecase not reached
+}
+
+// tests task creation with malformed json still gets a generic error
+func (suite *TaskControllerTestSuite) TestCreateTask_MalformedJSON() {
+
+    req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(`{"title":`))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+
+    suite.router.ServeHTTP(w, req)
+    suite.Equal(http.StatusBadRequest, w.Code)                       // status should be 400
+    suite.Contains(w.Body.String(), codeInvalidRequest.Code)         // no per-field map
+    suite.NotContains(w.Body.String(), `"errors"`)
+}
+
 // tests getting all tasks when empty
 func (suite *TaskControllerTestSuite) TestGetAllTasks_Empty() {
 	
 	// mock GetAllTasks to return empty slice
 	suite.mockUC.
-		On("GetAllTasks").
+		On("GetAllTasks", mock.Anything).
 		Return([]domain.Task{}, nil)
 
 	// create test request
@@ -130,12 +470,47 @@ func (suite *TaskControllerTestSuite) TestGetAllTasks_Empty() {
 	suite.Contains(w.Body.String(), "[]")         // reponse body should be empty array
 }
 
+// tests completed tasks listed without a description leave the field out
+func (suite *TaskControllerTestSuite) TestGetAllTasks_CompletedWithoutDescription() {
+
+	// list as returned by a repository omitting completed descriptions
+	suite.mockUC.
+		On("GetAllTasks", mock.Anything).
+		Return([]domain.Task{{Title: "Done", Status: "completed"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var tasks []map[string]interface{}
+	suite.Equal(http.StatusOK, w.Code)                                // status should be 200
+	suite.NoError(json.Unmarshal(w.Body.Bytes(), &tasks))             // body should be json
+	suite.NotContains(tasks[0], "description")                        // no empty description
+	suite.Equal("completed", tasks[0]["status"])
+}
+
+// tests the detail view still returns the description of a completed task
+func (suite *TaskControllerTestSuite) TestGetTaskByID_CompletedKeepsDescription() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.
+		On("GetTaskByID", mock.Anything, id).
+		Return(&domain.Task{Title: "Done", Description: "what was done", Status: "completed"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id, nil)      // create test request
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)                                        // status should be 200
+	suite.Contains(w.Body.String(), `"description":"what was done"`)          // full details
+}
+
 // tests getting all tasks with usecase error
 func (suite *TaskControllerTestSuite) TestGetAllTasks_Error() {
     
 	// mock GetAllTasks to return nil and error
 	suite.mockUC.
-        On("GetAllTasks").
+        On("GetAllTasks", mock.Anything).
         Return(nil, errors.New("db error"))
 
     req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
@@ -146,6 +521,399 @@ func (suite *TaskControllerTestSuite) TestGetAllTasks_Error() {
     suite.Contains(w.Body.String(), "db error")               // should contain error message
 }
 
+// tests getting all tasks when the database limiter is saturated
+func (suite *TaskControllerTestSuite) TestGetAllTasks_DatabaseBusy() {
+
+	// mock GetAllTasks to return database busy error
+	suite.mockUC.
+		On("GetAllTasks", mock.Anything).
+		Return(nil, domain.ErrDatabaseBusy)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusServiceUnavailable, w.Code)              // status should be 503
+	suite.Contains(w.Body.String(), "database is busy")              // should contain error message
+}
+
+// tests getting tasks sorted by due date ascending
+func (suite *TaskControllerTestSuite) TestGetAllTasks_Sorted() {
+
+	// mock GetTasksSorted to return tasks
+	suite.mockUC.
+		On("GetTasksSorted", mock.Anything, "due_date", true).
+		Return([]domain.Task{{Title: "soonest"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?sort=due_date&order=asc", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "soonest")          // should contain sorted task
+}
+
+// tests sorting defaults to creation time descending when only order is given
+func (suite *TaskControllerTestSuite) TestGetAllTasks_SortDefault() {
+
+	// mock GetTasksSorted to return no tasks
+	suite.mockUC.
+		On("GetTasksSorted", mock.Anything, "created_at", false).
+		Return([]domain.Task{}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?order=desc", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.mockUC.AssertExpectations(suite.T())          // verify default sort was used
+}
+
+// tests sorting by an unknown field
+func (suite *TaskControllerTestSuite) TestGetAllTasks_InvalidSortField() {
+
+	// mock GetTasksSorted to reject the field
+	suite.mockUC.
+		On("GetTasksSorted", mock.Anything, "password", false).
+		Return(nil, domain.ErrInvalidSortField)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?sort=password", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), "invalid sort field")       // should contain error message
+}
+
+// tests sorting with an invalid order
+func (suite *TaskControllerTestSuite) TestGetAllTasks_InvalidSortOrder() {
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?sort=title&order=up", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                        // status should be 400
+	suite.Contains(w.Body.String(), "order must be asc or desc")      // should contain error message
+}
+
+// tests a q parameter searches instead of listing every task
+func (suite *TaskControllerTestSuite) TestGetAllTasks_Search() {
+
+	// mock SearchTasks to return a match
+	suite.mockUC.
+		On("SearchTasks", mock.Anything, "report").
+		Return([]domain.Task{{Title: "Weekly report"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?q=report", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "Weekly report")    // should contain matching task
+	suite.mockUC.AssertNotCalled(suite.T(), "GetAllTasks", mock.Anything)
+}
+
+// tests a project parameter lists the project's tasks
+func (suite *TaskControllerTestSuite) TestGetAllTasks_Project() {
+
+	suite.mockUC.On("GetTasksByProject", mock.Anything, "invalid", mock.Anything).Return(nil, domain.ErrInvalidProjectID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?project=invalid", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), "INVALID_PROJECT_ID")       // should contain error code
+	suite.mockUC.AssertNotCalled(suite.T(), "GetAllTasks", mock.Anything)
+}
+
+// tests moving a task into a project and out of it again as its owner
+func (suite *TaskControllerTestSuite) TestMoveTask() {
+
+	taskID := "60d5ec49f9a3c7001c5b2b0d"
+	projectID := "60d5ec49f9a3c7001c5b2b0e"
+	suite.mockUC.On("MoveTask", mock.Anything, taskID, projectID, taskTestUserID).Return(&domain.Task{Title: "Deploy"}, nil)
+	suite.mockUC.On("MoveTask", mock.Anything, taskID, "", taskTestUserID).Return(&domain.Task{Title: "Deploy"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/tasks/"+taskID+"/move", strings.NewReader(`{"project_id":"`+projectID+`"}`))
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                 // status should be 200
+	suite.Contains(w.Body.String(), "Deploy")          // should contain the moved task
+
+	req, _ = http.NewRequest(http.MethodPatch, "/tasks/"+taskID+"/move", strings.NewReader(`{"project_id":null}`))
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                 // null takes the task out of its project
+	suite.mockUC.AssertExpectations(suite.T())
+}
+
+// tests moving a task into another user's project is rejected
+func (suite *TaskControllerTestSuite) TestMoveTask_ForeignProject() {
+
+	taskID := "60d5ec49f9a3c7001c5b2b0d"
+	projectID := "60d5ec49f9a3c7001c5b2b0e"
+	suite.mockUC.On("MoveTask", mock.Anything, taskID, projectID, taskTestUserID).Return(nil, domain.ErrProjectOwnerMismatch)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/tasks/"+taskID+"/move", strings.NewReader(`{"project_id":"`+projectID+`"}`))
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), "PROJECT_OWNER_MISMATCH")   // should contain error code
+}
+
+// tests the status overview returns the counts per status
+func (suite *TaskControllerTestSuite) TestGetTaskStats() {
+
+	suite.mockUC.On("TaskStats", mock.Anything).Return(map[string]int64{"pending": 3, "in_progress": 1, "completed": 10}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/stats", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                                    // status should be 200
+	suite.JSONEq(`{"pending":3,"in_progress":1,"completed":10}`, w.Body.String())         // counts per status
+}
+
+// tests reopening passes the acting user and reports tasks that are not completed
+func (suite *TaskControllerTestSuite) TestReopenTask() {
+
+	done, open := "60d5ec49f9a3c7001c5b2b0d", "60d5ec49f9a3c7001c5b2b0e"
+	suite.mockUC.On("ReopenTask", mock.Anything, done, taskTestUserID, "").Return(&domain.Task{Title: "Deploy", Status: "in_progress"}, nil)
+	suite.mockUC.On("ReopenTask", mock.Anything, open, taskTestUserID, "").Return(nil, domain.ErrTaskNotCompleted)
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks/"+done+"/reopen", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                       // status should be 200
+	suite.Contains(w.Body.String(), "in_progress")           // should contain the reopened task
+
+	req, _ = http.NewRequest(http.MethodPost, "/tasks/"+open+"/reopen", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusConflict, w.Code)                 // status should be 409
+	suite.Contains(w.Body.String(), "TASK_NOT_COMPLETED")    // should contain error code
+}
+
+// tests an empty q parameter is rejected
+func (suite *TaskControllerTestSuite) TestGetAllTasks_SearchEmpty() {
+
+	// mock SearchTasks to reject the keyword
+	suite.mockUC.
+		On("SearchTasks", mock.Anything, "").
+		Return(nil, domain.ErrInvalidSearchQuery)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?q=", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), "INVALID_SEARCH_QUERY")     // should contain error code
+}
+
+// tests due_from and due_to list tasks due within the window
+func (suite *TaskControllerTestSuite) TestGetAllTasks_DueDateRange() {
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 23, 59, 59, 0, time.UTC)
+	suite.mockUC.
+		On("GetTasksByDueDateRange", mock.Anything, from, to).
+		Return([]domain.Task{{Title: "due in july"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?due_from=2025-07-01T00:00:00Z&due_to=2025-07-31T23:59:59Z", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "due in july")      // should contain task in the window
+}
+
+// tests a single bound gives an open ended window
+func (suite *TaskControllerTestSuite) TestGetAllTasks_DueDateRangeToOnly() {
+
+	to := time.Date(2025, 7, 31, 23, 59, 59, 0, time.UTC)
+	suite.mockUC.
+		On("GetTasksByDueDateRange", mock.Anything, time.Time{}, to).
+		Return([]domain.Task{}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?due_to=2025-07-31T23:59:59Z", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.mockUC.AssertExpectations(suite.T())          // lower bound left open
+}
+
+// tests unparsable and inverted windows are rejected
+func (suite *TaskControllerTestSuite) TestGetAllTasks_DueDateRangeInvalid() {
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks?due_from=2025-07-01", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                         // status should be 400
+	suite.Contains(w.Body.String(), "due_from must be an RFC 3339")    // should name the parameter
+
+	from := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	suite.mockUC.
+		On("GetTasksByDueDateRange", mock.Anything, from, to).
+		Return(nil, domain.ErrInvalidDueDateRange)
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks?due_from=2025-07-31T00:00:00Z&due_to=2025-07-01T00:00:00Z", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                      // status should be 400
+	suite.Contains(w.Body.String(), "INVALID_DUE_DATE_RANGE")       // should contain error code
+}
+
+// tests overdue tasks are limited to the caller's own tasks, admins see every task
+func (suite *TaskControllerTestSuite) TestGetOverdueTasks_Scope() {
+
+	suite.mockUC.On("GetOverdueTasks", mock.Anything, taskTestUserID).Return([]domain.Task{{Title: "mine"}}, nil)
+	suite.mockUC.On("GetOverdueTasks", mock.Anything, "").Return([]domain.Task{{Title: "everyone's"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/overdue", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "mine")             // own tasks only
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks/overdue", nil)
+	req.Header.Set("X-Test-Role", "admin")
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "everyone's")       // admins see every task
+}
+
+// tests the workload view only lists tasks when asked to
+func (suite *TaskControllerTestSuite) TestGetTasksByAssignee() {
+
+	suite.mockUC.On("GetTasksGroupedByAssignee", mock.Anything, false).Return([]domain.AssigneeTasks{{Count: 2}}, nil)
+	suite.mockUC.On("GetTasksGroupedByAssignee", mock.Anything, true).Return([]domain.AssigneeTasks{{Count: 1, Tasks: []domain.Task{{Title: "assigned"}}}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/by-assignee", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                  // status should be 200
+	suite.Contains(w.Body.String(), `"count":2`)                         // counts only
+	suite.NotContains(w.Body.String(), `"tasks"`)                       // no task lists
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks/by-assignee?include_tasks=true", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                  // status should be 200
+	suite.Contains(w.Body.String(), "assigned")                         // tasks listed
+
+	req, _ = http.NewRequest(http.MethodGet, "/tasks/by-assignee?include_tasks=maybe", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                          // status should be 400
+	suite.Contains(w.Body.String(), "include_tasks must be true or false")     // should contain error message
+}
+
+// tests recently updated tasks use the default limit
+func (suite *TaskControllerTestSuite) TestGetRecentlyUpdated_DefaultLimit() {
+
+	// mock GetRecentlyUpdated to expect the default limit for the caller's own tasks
+	suite.mockUC.
+		On("GetRecentlyUpdated", mock.Anything, taskTestUserID, int64(defaultRecentLimit)).
+		Return([]domain.Task{{Title: "recent"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/recent", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "recent")           // should contain recent task
+	suite.mockUC.AssertExpectations(suite.T())          // verify default limit was used
+}
+
+// tests admins see recently updated tasks of every owner
+func (suite *TaskControllerTestSuite) TestGetRecentlyUpdated_AdminUnscoped() {
+
+	// mock GetRecentlyUpdated to expect no owner filter
+	suite.mockUC.
+		On("GetRecentlyUpdated", mock.Anything, "", int64(defaultRecentLimit)).
+		Return([]domain.Task{}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/recent", nil)
+	req.Header.Set("X-Test-Role", "admin")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.mockUC.AssertExpectations(suite.T())          // verify no owner filter
+}
+
+// tests recently updated tasks with a non numeric limit
+func (suite *TaskControllerTestSuite) TestGetRecentlyUpdated_InvalidLimit() {
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/recent?limit=ten", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), "limit must be a number")   // should contain error message
+}
+
+// tests the csv export returns every task with range support advertised
+func (suite *TaskControllerTestSuite) TestExportTasksCSV_Full() {
+
+	// mock GetAllTasks to return one task
+	suite.mockUC.
+		On("GetAllTasks", mock.Anything).
+		Return([]domain.Task{{Title: "write report", Status: "pending", Priority: "high"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/export", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                      // status should be 200
+	suite.Equal("bytes", w.Header().Get("Accept-Ranges"))                   // range support advertised
+	suite.Equal(strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))      // length matches body
+	suite.True(strings.HasPrefix(w.Body.String(), "id,title,description,"))       // header row first
+	suite.Contains(w.Body.String(), "write report")                         // task row included
+}
+
+// tests the csv export is not bound by the page size cap of the list endpoints
+func (suite *TaskControllerTestSuite) TestExportTasksCSV_MoreThanPageCap() {
+
+	tasks := make([]domain.Task, usecases.MaxPageSize+50)
+	for i := range tasks {
+		tasks[i] = domain.Task{Title: fmt.Sprintf("task %d", i)}
+	}
+	suite.mockUC.
+		On("GetAllTasks", mock.Anything).
+		Return(tasks, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/export", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                               // status should be 200
+	suite.Equal(len(tasks)+1, strings.Count(w.Body.String(), "\n"))                  // header row plus every task
+	suite.Contains(w.Body.String(), fmt.Sprintf("task %d", len(tasks)-1))             // last task included
+}
+
+// tests the csv export answers a byte range with a partial response
+func (suite *TaskControllerTestSuite) TestExportTasksCSV_Range() {
+
+	// mock GetAllTasks to return one task
+	suite.mockUC.
+		On("GetAllTasks", mock.Anything).
+		Return([]domain.Task{{Title: "write report"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/export", nil)
+	req.Header.Set("Range", "bytes=0-1")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPartialContent, w.Code)                          // status should be 206
+	suite.Equal("id", w.Body.String())                                      // only the requested bytes
+	suite.True(strings.HasPrefix(w.Header().Get("Content-Range"), "bytes 0-1/"))      // range echoed back
+}
+
 // tests getting a task with invalid ID format
 func (suite *TaskControllerTestSuite) TestGetTaskByID_InvalidID() {
 
@@ -162,7 +930,7 @@ func (suite *TaskControllerTestSuite) TestGetTaskByID_Error() {
 
     id := "60d5ec49f9a3c7001c5b2b0d"
     suite.mockUC.
-        On("GetTaskByID", id).
+        On("GetTaskByID", mock.Anything, id).
         Return(nil, errors.New("db error"))
 
     req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id, nil)
@@ -181,7 +949,7 @@ func (suite *TaskControllerTestSuite) TestGetTaskByID_NotFound() {
 	
 	// mock GetTaskByID to return not found error
 	suite.mockUC.
-		On("GetTaskByID", id).
+		On("GetTaskByID", mock.Anything, id).
 		Return(nil, domain.ErrTaskNotFound)
 
 	// create test request
@@ -211,12 +979,12 @@ func (suite *TaskControllerTestSuite) TestUpdateTask_Success() {
 	id := "60d5ec49f9a3c7001c5b2b0d" 
 
 	// mock UpdateTask method to return the updated task
-	suite.mockUC.On("UpdateTask", id, mock.MatchedBy(func(t *domain.Task) bool {
+	suite.mockUC.On("UpdateTask", mock.Anything, id, mock.MatchedBy(func(t *domain.Task) bool {
         return t.Title == task.Title &&
             t.Description == task.Description &&
             t.Status == task.Status &&
             t.DueDate.Round(time.Second).Equal(task.DueDate.Round(time.Second))
-    })).Return(&task, nil)
+    }), mock.Anything).Return(&task, nil)
 
 	// create test request with JSON body
 	body, _ := json.Marshal(task)
@@ -232,6 +1000,26 @@ func (suite *TaskControllerTestSuite) TestUpdateTask_Success() {
 	suite.Contains(w.Body.String(), "task updated successfully")      // message should be in response body
 }
 
+// tests updating a task ignores fields that are not part of the task update
+func (suite *TaskControllerTestSuite) TestUpdateTask_IgnoresProtectedFields() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	body := []byte(`{"title":"Updated","owner_id":"60d5ec49f9a3c7001c5b2b0e","created_at":"2020-01-01T00:00:00Z"}`)
+
+	// only the title should reach the usecase
+	suite.mockUC.On("UpdateTask", mock.Anything, id, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.Title == "Updated" && t.ID.IsZero()
+	}), mock.Anything).Return(&domain.Task{Title: "Updated"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/"+id, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.mockUC.AssertExpectations(suite.T())          // verify mock was called as expected
+}
+
 // tests updating a task with invalid ID format
 func (suite *TaskControllerTestSuite) TestUpdateTask_InvalidID() {
 
@@ -265,7 +1053,7 @@ func (suite *TaskControllerTestSuite) TestUpdateTask_NotFound() {
     task := &domain.Task{Title: "Updated"}
 
     suite.mockUC.
-        On("UpdateTask", id, mock.AnythingOfType("*domain.Task")).
+        On("UpdateTask", mock.Anything, id, mock.AnythingOfType("*domain.Task"), mock.Anything).
         Return(nil, domain.ErrTaskNotFound)
 
     body, _ := json.Marshal(task)
@@ -285,7 +1073,7 @@ func (suite *TaskControllerTestSuite) TestUpdateTask_Error() {
     task := &domain.Task{Title: "Updated"}
 
     suite.mockUC.
-        On("UpdateTask", id, mock.AnythingOfType("*domain.Task")).
+        On("UpdateTask", mock.Anything, id, mock.AnythingOfType("*domain.Task"), mock.Anything).
         Return(nil, errors.New("update error"))
 
     body, _ := json.Marshal(task)
@@ -306,7 +1094,7 @@ func (suite *TaskControllerTestSuite) TestDeleteTask_Error() {
 	
 	// mock DeleteTask method to return an error
 	suite.mockUC.
-		On("DeleteTask", id).
+		On("DeleteTask", mock.Anything, id).
 		Return(errors.New("failed to delete"))
 
 	// create test request
@@ -338,7 +1126,7 @@ func (suite *TaskControllerTestSuite) TestDeleteTask_NotFound() {
 	id := "60d5ec49f9a3c7001c5b2b0d"
     
 	suite.mockUC.
-        On("DeleteTask", id).
+        On("DeleteTask", mock.Anything, id).
         Return(domain.ErrTaskNotFound)
 
     req, _ := http.NewRequest(http.MethodDelete, "/tasks/"+id, nil)
@@ -349,6 +1137,263 @@ func (suite *TaskControllerTestSuite) TestDeleteTask_NotFound() {
     suite.Contains(w.Body.String(), "task not found")       // should contain error message
 }
 
+// tests an id rejected below the controller is a bad request, even when wrapped
+func (suite *TaskControllerTestSuite) TestGetTaskByID_InvalidIDFromUsecase() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("GetTaskByID", mock.Anything, id).Return(nil, fmt.Errorf("loading task: %w", domain.ErrInvalidTaskID))
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id, nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                 // status should be 400, not 500
+	suite.Contains(w.Body.String(), "INVALID_TASK_ID")         // should contain error code
+}
+
+// tests the acting role reaches the usecase and a locked completed task is forbidden
+func (suite *TaskControllerTestSuite) TestUpdateTask_CompletedLocked() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("UpdateTask", mock.Anything, id, mock.AnythingOfType("*domain.Task"), "user").Return(nil, domain.ErrTaskCompletedLocked)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/"+id, strings.NewReader(`{"title":"Updated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-Role", "user")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusForbidden, w.Code)                        // status should be 403
+	suite.Contains(w.Body.String(), "TASK_COMPLETED_LOCKED")         // should contain error code
+}
+
+// tests an id rejected below the controller is a bad request on update
+func (suite *TaskControllerTestSuite) TestUpdateTask_InvalidIDFromUsecase() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("UpdateTask", mock.Anything, id, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(nil, domain.ErrInvalidTaskID)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/"+id, strings.NewReader(`{"title":"Updated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                 // status should be 400
+	suite.Contains(w.Body.String(), "INVALID_TASK_ID")         // should contain error code
+}
+
+// tests an id rejected below the controller is a bad request on delete, even when wrapped
+func (suite *TaskControllerTestSuite) TestDeleteTask_InvalidIDFromUsecase() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("DeleteTask", mock.Anything, id).Return(fmt.Errorf("deleting task: %w", domain.ErrInvalidTaskID))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/tasks/"+id, nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                 // status should be 400, not 500
+	suite.Contains(w.Body.String(), "INVALID_TASK_ID")         // should contain error code
+}
+
+// tests restoring a soft deleted task
+func (suite *TaskControllerTestSuite) TestRestoreTask_Success() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("RestoreTask", mock.Anything, id).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks/"+id+"/restore", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                                // status should be 200
+	suite.Contains(w.Body.String(), "task restored successfully")     // should contain success message
+}
+
+// tests restoring a non-existent task
+func (suite *TaskControllerTestSuite) TestRestoreTask_NotFound() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("RestoreTask", mock.Anything, id).Return(domain.ErrTaskNotFound)
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks/"+id+"/restore", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)                // status should be 404
+	suite.Contains(w.Body.String(), "TASK_NOT_FOUND")       // should contain error code
+}
+
+// tests listing the dependencies of a task
+func (suite *TaskControllerTestSuite) TestGetTaskDependencies() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("GetDependencies", mock.Anything, id).Return([]domain.Task{{Title: "Build", Status: "completed"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id+"/dependencies", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                  // status should be 200
+	suite.Contains(w.Body.String(), "Build")            // should contain dependency
+}
+
+// tests listing the dependencies of a non-existent task
+func (suite *TaskControllerTestSuite) TestGetTaskDependencies_NotFound() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	suite.mockUC.On("GetDependencies", mock.Anything, id).Return(nil, domain.ErrTaskNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id+"/dependencies", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)            // status should be 404
+}
+
+// tests GetTaskByID returns the task's ETag
+func (suite *TaskControllerTestSuite) TestGetTaskByID_ETag() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	task := &domain.Task{Title: "Task", UpdatedAt: time.Now()}
+	suite.mockUC.On("GetTaskByID", mock.Anything, id).Return(task, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id, nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                      // status should be 200
+	suite.Equal(task.ETag(), w.Header().Get("ETag"))        // current version
+}
+
+// tests updating a task with a matching If-Match header
+func (suite *TaskControllerTestSuite) TestUpdateTask_IfMatch() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+	etag := (&domain.Task{UpdatedAt: time.Now().Add(-time.Minute)}).ETag()
+	updated := &domain.Task{Title: "Updated", UpdatedAt: time.Now()}
+
+	suite.mockUC.
+		On("UpdateTaskIfMatch", mock.Anything, id, mock.AnythingOfType("*domain.Task"), etag, mock.Anything).
+		Return(updated, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/"+id, bytes.NewBufferString(`{"title":"Updated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", etag)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                         // status should be 200
+	suite.Equal(updated.ETag(), w.Header().Get("ETag"))        // new version
+	suite.mockUC.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)       // guarded update only
+}
+
+// tests updating a task with a stale If-Match header
+func (suite *TaskControllerTestSuite) TestUpdateTask_IfMatchStale() {
+
+	id := "60d5ec49f9a3c7001c5b2b0d"
+
+	suite.mockUC.
+		On("UpdateTaskIfMatch", mock.Anything, id, mock.AnythingOfType("*domain.Task"), `"stale"`, mock.Anything).
+		Return(nil, domain.ErrPreconditionFailed)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/"+id, bytes.NewBufferString(`{"title":"Updated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"stale"`)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPreconditionFailed, w.Code)             // status should be 412
+	suite.Contains(w.Body.String(), "PRECONDITION_FAILED")
+}
+
+// tests updating a task without If-Match when it is required
+func (suite *TaskControllerTestSuite) TestUpdateTask_IfMatchRequired() {
+
+	controller := NewTaskControllerWithConfig(suite.mockUC, TaskControllerConfig{RequireIfMatch: true})
+	router := gin.New()
+	router.PUT("/tasks/:id", controller.UpdateTask)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tasks/60d5ec49f9a3c7001c5b2b0d", bytes.NewBufferString(`{"title":"Updated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusPreconditionRequired, w.Code)           // status should be 428
+	suite.Contains(w.Body.String(), "PRECONDITION_REQUIRED")
+	suite.mockUC.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)       // nothing updated
+}
+
+// tests reassigning a user's tasks returns the moved count
+func (suite *TaskControllerTestSuite) TestReassignTasks_Success() {
+
+	from := "60d5ec49f9a3c7001c5b2b0e"
+	to := "60d5ec49f9a3c7001c5b2b0f"
+	suite.mockUC.On("ReassignTasks", mock.Anything, from, to).Return(int64(2), nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/"+from+"/reassign-tasks", bytes.NewBufferString(`{"new_owner_id":"`+to+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                 // status should be 200
+	suite.JSONEq(`{"moved":2}`, w.Body.String())       // number of tasks moved
+}
+
+// tests bulk completion returns the number of completed tasks
+func (suite *TaskControllerTestSuite) TestCompleteTasks() {
+
+	ids := []string{"60d5ec49f9a3c7001c5b2b0e", "60d5ec49f9a3c7001c5b2b0f"}
+	suite.mockUC.On("CompleteTasks", mock.Anything, ids).Return(int64(1), nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks/complete", bytes.NewBufferString(`{"ids":["`+ids[0]+`","`+ids[1]+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)                    // status should be 200
+	suite.JSONEq(`{"completed":1}`, w.Body.String())      // number of tasks completed
+}
+
+// tests bulk completion without ids reports the missing field
+func (suite *TaskControllerTestSuite) TestCompleteTasks_MissingIDs() {
+
+	req, _ := http.NewRequest(http.MethodPost, "/tasks/complete", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)            // status should be 400
+	suite.Contains(w.Body.String(), `"ids":"required"`)
+}
+
+// tests reassigning without a new owner reports the missing field
+func (suite *TaskControllerTestSuite) TestReassignTasks_MissingNewOwner() {
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/60d5ec49f9a3c7001c5b2b0e/reassign-tasks", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)                  // status should be 400
+	suite.Contains(w.Body.String(), `"new_owner_id":"required"`)
+}
+
+// tests reassigning to an unknown user
+func (suite *TaskControllerTestSuite) TestReassignTasks_NewOwnerNotFound() {
+
+	from := "60d5ec49f9a3c7001c5b2b0e"
+	to := "60d5ec49f9a3c7001c5b2b0f"
+	suite.mockUC.On("ReassignTasks", mock.Anything, from, to).Return(int64(0), domain.ErrUserNotFound)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/"+from+"/reassign-tasks", bytes.NewBufferString(`{"new_owner_id":"`+to+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)           // status should be 404
+}
+
 // runs the test suite for TaskController
 func TestTaskControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(TaskControllerTestSuite))        // run the test suite
@@ -0,0 +1,65 @@
+package controllers
+
+// imports
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// column order of the csv export
+var taskCSVHeader = []string{"id", "title", "description", "due_date", "status", "priority", "created_at", "updated_at", "owner_id"}
+
+// renders tasks as csv with a header row, times in RFC 3339
+func tasksCSV(tasks []domain.Task) ([]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(taskCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		record := []string{
+			task.ID.Hex(),
+			task.Title,
+			task.Description,
+			task.DueDate.Format(time.RFC3339),
+			task.Status,
+			task.Priority,
+			task.CreatedAt.Format(time.RFC3339),
+			task.UpdatedAt.Format(time.RFC3339),
+			task.OwnerID.Hex(),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func (taskContr *TaskController) ExportTasksCSV(c *gin.Context) {
+
+	// get all tasks through usecase layer
+	tasks, err := taskContr.taskUseCase.GetAllTasks(c.Request.Context())
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	data, err := tasksCSV(tasks)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	// the export is fully buffered, so ServeContent can answer Range requests for resumed downloads
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="tasks.csv"`)
+	http.ServeContent(c.Writer, c.Request, "tasks.csv", time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,76 @@
+package controllers
+
+// imports
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// epoch values at or above this are read as milliseconds (1e12 ms is September 2001, 1e12 s is year 33658)
+const epochMillisThreshold = 1e12
+
+// layout of due dates sent without a time, read as midnight UTC
+const dateOnlyLayout = "2006-01-02"
+
+// due date as sent by clients - an RFC 3339 or date-only string, or a unix epoch in seconds or milliseconds
+type dueDate struct {
+	time.Time
+	epoch bool        // value was sent as a number
+}
+
+// accepts an RFC 3339 or date-only string, a numeric epoch or null
+func (d *dueDate) UnmarshalJSON(data []byte) error {
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var raw string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if day, err := time.Parse(dateOnlyLayout, raw); err == nil {
+			d.Time = day        // time.Parse without a zone gives UTC
+			return nil
+		}
+		return d.Time.UnmarshalJSON(data)
+	}
+
+	var epoch json.Number
+	if err := json.Unmarshal(data, &epoch); err != nil {
+		return err
+	}
+	value, err := epoch.Int64()
+	if err != nil {
+		return err
+	}
+
+	d.epoch = true
+	if value >= epochMillisThreshold || value <= -epochMillisThreshold {
+		d.Time = time.UnixMilli(value).UTC()
+	} else {
+		d.Time = time.Unix(value, 0).UTC()
+	}
+
+	return nil
+}
+
+// returned when an epoch due date is sent while they are disabled
+var errEpochDueDate = errors.New("epoch due dates are not accepted")
+
+// task body of create and update requests
+type taskInput struct {
+	domain.Task
+	DueDate dueDate `json:"due_date"`        // shadows Task.DueDate so epochs are accepted
+}
+
+// returns the task with the parsed due date
+func (in *taskInput) task() domain.Task {
+	task := in.Task
+	task.DueDate = in.DueDate.Time
+	return task
+}
@@ -0,0 +1,55 @@
+package controllers
+
+// imports
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for due date parsing
+type DueDateTestSuite struct {
+	suite.Suite
+}
+
+// tests every accepted due date format
+func (suite *DueDateTestSuite) TestUnmarshalJSON_Formats() {
+
+	want := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	cases := map[string]struct {
+		input  string
+		want   time.Time
+		epoch  bool
+	}{
+		"rfc3339":       {`"2030-01-02T03:04:05Z"`, want, false},
+		"epoch seconds": {"1893553445", want, true},
+		"epoch millis":  {"1893553445000", want, true},
+		"date only":     {`"2030-01-02"`, time.Date(2030, time.January, 2, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for name, tc := range cases {
+		var due dueDate
+		assert.NoError(suite.T(), json.Unmarshal([]byte(tc.input), &due), name)       // format accepted
+		assert.True(suite.T(), tc.want.Equal(due.Time), name)                         // same instant
+		assert.Equal(suite.T(), time.UTC, due.Location(), name)                       // normalized to UTC
+		assert.Equal(suite.T(), tc.epoch, due.epoch, name)                            // epoch flagged
+	}
+}
+
+// tests null leaves the due date unset and garbage is rejected
+func (suite *DueDateTestSuite) TestUnmarshalJSON_NullAndInvalid() {
+
+	var due dueDate
+	assert.NoError(suite.T(), json.Unmarshal([]byte("null"), &due))     // null is allowed
+	assert.True(suite.T(), due.IsZero())                                // nothing set
+
+	assert.Error(suite.T(), json.Unmarshal([]byte(`"next week"`), &due))     // not a date
+	assert.Error(suite.T(), json.Unmarshal([]byte("1.5"), &due))             // fractional epoch
+}
+
+// runs the test suite for due date parsing
+func TestDueDateTestSuite(t *testing.T) {
+	suite.Run(t, new(DueDateTestSuite))
+}
@@ -2,72 +2,94 @@ package controllers
 
 // imports
 import (
+	"errors"
 	"net/http"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// configurable response handling of the user controller
+type UserControllerConfig struct {
+	ExposePasswordPolicy  bool        // add the password policy to weak password errors
+}
+
+// returns the default user controller settings, the password policy stays private
+func DefaultUserControllerConfig() UserControllerConfig {
+	return UserControllerConfig{}
+}
+
 // user controller
 type UserController struct {
 	userUseCase domain.UserUseCase        // user usecase for user operations 
+	config      UserControllerConfig
 }
 
 // new user controller
 func NewUserController(uc domain.UserUseCase) *UserController {
-	return &UserController{userUseCase: uc}        // return new user controller instance
+	return NewUserControllerWithConfig(uc, DefaultUserControllerConfig())
 }
 
-func (uc *UserController) Register(c *gin.Context) {
-	
-	var user domain.User
-	err := c.ShouldBindJSON(&user)       // parse request body into user struct
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// new user controller with custom response handling
+func NewUserControllerWithConfig(uc domain.UserUseCase, config UserControllerConfig) *UserController {
+	return &UserController{userUseCase: uc, config: config}        // return new user controller instance
+}
+
+// writes err like respondError, adding the password policy to weak password errors when enabled
+func (uc *UserController) respondPasswordError(c *gin.Context, err error, fallback int) {
+
+	var weak *domain.WeakPasswordError
+	if !uc.config.ExposePasswordPolicy || !errors.As(err, &weak) {
+		respondError(c, err, fallback)
 		return
 	}
 
-	if user.Username == "" || user.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "username and password must be set"})
+	code := lookupError(err, fallback)
+	respond(c, code.Status, gin.H{"error": err.Error(), "code": code.Code, "policy": weak.Policy})
+}
+
+func (uc *UserController) Register(c *gin.Context) {
+	
+	var req domain.RegisterRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
 		return
 	}
+	user := domain.User{Username: req.Username, Password: req.Password, Email: req.Email}      // the usecase decides the role
 
 	// create user through usecase layer
-	if err := uc.userUseCase.Register(&user); err != nil {
-		if err == domain.ErrUserExists {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := uc.userUseCase.Register(c.Request.Context(), &user); err != nil {
+		uc.respondPasswordError(c, err, http.StatusBadRequest)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "user created successfully"})       // success response
+	respond(c, http.StatusCreated, gin.H{"message": "user created successfully"})       // success response
 }
 
 func (uc *UserController) Login(c *gin.Context) {
 	
 	var creds domain.Credentials
-	err := c.ShouldBindJSON(&creds)        // parse request body into user struct
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &creds) {        // parse request body, reporting what is wrong with it
 		return
 	}
 
 	// authenticate user through usecase layer
-	token, user, err := uc.userUseCase.Login(&creds)
+	token, user, err := uc.userUseCase.Login(c.Request.Context(), &creds)
 	if err != nil {
-		if err == domain.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	// long lived token used to get new access tokens from /refresh
+	refreshToken, err := uc.userUseCase.IssueRefreshToken(user.ID.Hex())
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	// return token, user info (excluding sensitive data)
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"token": token,
+		"refresh_token": refreshToken,
 		"user": gin.H{
 			"id":       user.ID,
 			"username": user.Username,
@@ -76,26 +98,157 @@ func (uc *UserController) Login(c *gin.Context) {
 	})
 }
 
+func (uc *UserController) Refresh(c *gin.Context) {
+
+	var req domain.RefreshRequest
+	if !bindJSON(c, &req) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// exchange the refresh token through usecase layer
+	token, err := uc.userUseCase.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"token": token})       // success response
+}
+
+func (uc *UserController) ListUsers(c *gin.Context) {
+
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	// get one page of users through usecase layer
+	users, err := uc.userUseCase.ListUsers(c.Request.Context(), page, pageSize)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	// return user info (excluding sensitive data)
+	response := make([]gin.H, 0, len(users))
+	for _, user := range users {
+		response = append(response, gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"role":     user.Role,
+		})
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
 func (uc *UserController) PromoteToAdmin(c *gin.Context) {
 	
 	userID := c.Param("id")       // get user id from request parameter
 	 
 	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		badRequest(c, "Invalid user ID format")
 		return
 	}
 
 	// promote user through usecase layer
-	err = uc.userUseCase.PromoteToAdmin(userID) 
+	err = uc.userUseCase.PromoteToAdmin(c.Request.Context(), userID) 
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)        // known errors keep their 400/404, anything else is a database failure
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "user promoted to admin successfully"})       // success response
+}
+
+func (uc *UserController) DemoteFromAdmin(c *gin.Context) {
+	
+	userID := c.Param("id")       // get user id from request parameter
+	 
+	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
+	if err != nil {
+		badRequest(c, "Invalid user ID format")
+		return
+	}
+
+	// demote user through usecase layer
+	err = uc.userUseCase.DemoteFromAdmin(c.Request.Context(), userID) 
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "admin demoted to user successfully"})       // success response
+}
+
+func (uc *UserController) DeleteUser(c *gin.Context) {
+
+	userID := c.Param("id")       // get user id from request parameter
+
+	_, err := primitive.ObjectIDFromHex(userID)       // validate it is a valid ObjectID
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		badRequest(c, "Invalid user ID format")
 		return
 	}
+	// an admin removing their own account could lock everyone out
+	if userID == c.GetString("userID") {
+		respondError(c, domain.ErrCannotDeleteSelf, http.StatusForbidden)
+		return
+	}
+
+	// delete user through usecase layer
+	err = uc.userUseCase.DeleteUser(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "user deleted successfully"})       // success response
+}
+
+func (uc *UserController) GetPreferences(c *gin.Context) {
 
-	c.JSON(http.StatusOK, gin.H{"message": "user promoted to admin successfully"})       // success response
-}
\ No newline at end of file
+	// get preferences of the authenticated user through usecase layer
+	prefs, err := uc.userUseCase.GetPreferences(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	respond(c, http.StatusOK, prefs)        // return stored preferences
+}
+
+func (uc *UserController) UpdatePreferences(c *gin.Context) {
+
+	var prefs domain.Preferences
+	if !bindJSON(c, &prefs) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// save preferences of the authenticated user through usecase layer
+	err := uc.userUseCase.UpdatePreferences(c.Request.Context(), c.GetString("userID"), &prefs)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	respond(c, http.StatusOK, prefs)        // return saved preferences
+}
+
+func (uc *UserController) ChangePassword(c *gin.Context) {
+
+	var change domain.PasswordChange
+	if !bindJSON(c, &change) {        // parse request body, reporting what is wrong with it
+		return
+	}
+
+	// change password of the authenticated user through usecase layer
+	err := uc.userUseCase.ChangePassword(c.Request.Context(), c.GetString("userID"), change.OldPassword, change.NewPassword)
+	if err != nil {
+		uc.respondPasswordError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "password changed successfully"})       // success response
+}
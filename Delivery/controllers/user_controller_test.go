@@ -4,6 +4,7 @@ package controllers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -33,9 +35,27 @@ func (suite *UserControllerTestSuite) SetupTest() {
 	// setup test router with all user routes
 	suite.router.POST("/register", suite.controller.Register)             // user registration route
 	suite.router.POST("/login", suite.controller.Login)                   // user login route
+	suite.router.POST("/refresh", suite.controller.Refresh)               // token refresh route
 	suite.router.PUT("/promote/:id", suite.controller.PromoteToAdmin)     // promote user to admin route
+	suite.router.PUT("/demote/:id", suite.controller.DemoteFromAdmin)        // demote admin to user route
+	suite.router.GET("/users", suite.controller.ListUsers)                // list users route
+	suite.router.GET("/me/preferences", func(c *gin.Context) {
+		c.Set("userID", preferencesUserID)        // stands in for the auth middleware
+	}, suite.controller.GetPreferences)                                   // get own preferences route
+	suite.router.PUT("/me/preferences", func(c *gin.Context) {
+		c.Set("userID", preferencesUserID)        // stands in for the auth middleware
+	}, suite.controller.UpdatePreferences)                                // update own preferences route
+	suite.router.PUT("/password", func(c *gin.Context) {
+		c.Set("userID", preferencesUserID)        // stands in for the auth middleware
+	}, suite.controller.ChangePassword)                                   // change own password route
+	suite.router.DELETE("/users/:id", func(c *gin.Context) {
+		c.Set("userID", preferencesUserID)        // stands in for the auth middleware
+	}, suite.controller.DeleteUser)                                       // delete user route
 }
 
+// user id the test router treats as authenticated
+var preferencesUserID = primitive.NewObjectID().Hex()
+
 // tests successful user registration
 func (suite *UserControllerTestSuite) TestRegister_Success() {
 	
@@ -43,16 +63,15 @@ func (suite *UserControllerTestSuite) TestRegister_Success() {
 	user := domain.User{
 		Username: "john", 
 		Password: "password123",
-		Role: "user",
 	}
 
 	// mock Register method to return no error
 	suite.mockUseCase.
-		On("Register", &user).
+		On("Register", mock.Anything, &user).
 		Return(nil)
 
 	// create test request with JSON body
-	body, _ := json.Marshal(user)
+	body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
 	req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))      // create test request
 	req.Header.Set("Content-Type", "application/json")      // set content type header
 	resp := httptest.NewRecorder()
@@ -62,7 +81,65 @@ func (suite *UserControllerTestSuite) TestRegister_Success() {
 
 	// verify response
 	assert.Equal(suite.T(), http.StatusCreated, resp.Code)             // status should be 201
-	suite.mockUseCase.AssertCalled(suite.T(), "Register", &user)       // verify mock was called
+	suite.mockUseCase.AssertCalled(suite.T(), "Register", mock.Anything, &user)       // verify mock was called
+}
+
+// tests a weak password error carries the password policy when enabled
+func (suite *UserControllerTestSuite) TestRegister_WeakPasswordPolicy() {
+
+	user := domain.User{Username: "john", Password: "short"}
+	weak := &domain.WeakPasswordError{
+		Reason: "password must be at least 8 characters",
+		Policy: domain.PasswordPolicy{MinLength: 8, RequiredClasses: []string{"digit"}},
+	}
+	suite.mockUseCase.
+		On("Register", mock.Anything, &user).
+		Return(weak)
+
+	for _, expose := range []bool{true, false} {
+		router := gin.New()
+		router.POST("/register", NewUserControllerWithConfig(suite.mockUseCase, UserControllerConfig{ExposePasswordPolicy: expose}).Register)
+
+		body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
+		req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))      // create test request
+		req.Header.Set("Content-Type", "application/json")      // set content type header
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]interface{}
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                    // status should be 400
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &response))     // body should be json
+		assert.Equal(suite.T(), "WEAK_PASSWORD", response["code"])                   // weak password code
+		if expose {
+			assert.Equal(suite.T(), map[string]interface{}{
+				"min_length":       float64(8),
+				"required_classes": []interface{}{"digit"},
+			}, response["policy"])                                                    // policy block included
+		} else {
+			assert.NotContains(suite.T(), response, "policy")                        // policy kept private
+		}
+	}
+}
+
+// tests the default controller keeps the password policy out of weak password errors
+func (suite *UserControllerTestSuite) TestRegister_WeakPasswordDefault() {
+
+	user := domain.User{Username: "john", Password: "short"}
+	suite.mockUseCase.
+		On("Register", mock.Anything, &user).
+		Return(&domain.WeakPasswordError{Reason: "password must be at least 8 characters", Policy: domain.PasswordPolicy{MinLength: 8}})
+
+	router := gin.New()
+	router.POST("/register", NewUserController(suite.mockUseCase).Register)
+
+	body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
+	req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)             // status should be 400
+	assert.NotContains(suite.T(), resp.Body.String(), "min_length")       // policy kept private
 }
 
 // tests registration with existing username
@@ -72,16 +149,15 @@ func (suite *UserControllerTestSuite) TestRegister_Conflict() {
 	user := domain.User{
 		Username: "john", 
 		Password: "password123",
-		Role:     "user",
 	}
 
 	// mock Register method to return error
 	suite.mockUseCase.
-		On("Register", &user).
+		On("Register", mock.Anything, &user).
 		Return(domain.ErrUserExists)
 
 	// create test request with JSON body
-	body, _ := json.Marshal(user)
+	body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
 	req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))        // create test request
 	req.Header.Set("Content-Type", "application/json")        // set content type header
 	resp := httptest.NewRecorder()
@@ -99,11 +175,10 @@ func (suite *UserControllerTestSuite) TestRegister_MissingUsername() {
 	// create test user with missing username
     user := domain.User{
         Password: "password123",
-        Role:     "user",
     }
 
     // create test request with JSON body
-    body, _ := json.Marshal(user)
+    body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
     req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))       // create test request
     req.Header.Set("Content-Type", "application/json")       // set content type header
     resp := httptest.NewRecorder()
@@ -122,10 +197,9 @@ func (suite *UserControllerTestSuite) TestRegister_MissingPassword() {
 	// create test user with missing password
     user := domain.User{
         Username: "john",
-        Role:     "user",
     }
 
-    body, _ := json.Marshal(user)
+    body, _ := json.Marshal(domain.RegisterRequest{Username: user.Username, Password: user.Password})
     req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))       // create test request 
     req.Header.Set("Content-Type", "application/json")         // set content type header
     resp := httptest.NewRecorder()
@@ -135,6 +209,30 @@ func (suite *UserControllerTestSuite) TestRegister_MissingPassword() {
     assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)       // status should be 400
 }
 
+// tests registration with missing password returns structured field errors
+func (suite *UserControllerTestSuite) TestRegister_MissingPasswordStructured() {
+
+	// create test request without a password
+	body := []byte(`{"username":"john"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))       // create test request
+	req.Header.Set("Content-Type", "application/json")         // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify structured response
+	var result struct {
+		Errors  map[string]string  `json:"errors"`
+		Code    string             `json:"code"`
+	}
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                              // status should be 400
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &result))                  // body should be json
+	assert.Equal(suite.T(), map[string]string{"password": "required"}, result.Errors)      // only password is reported
+	assert.Equal(suite.T(), "VALIDATION_FAILED", result.Code)                              // code should be validation failed
+	suite.mockUseCase.AssertNotCalled(suite.T(), "Register", mock.Anything, mock.Anything)                         // usecase should not be called
+}
+
 // tests successful user login
 func (suite *UserControllerTestSuite) TestLogin_Success() {
 	
@@ -156,8 +254,12 @@ func (suite *UserControllerTestSuite) TestLogin_Success() {
 
 	// mock Login method to return token, user and no error
 	suite.mockUseCase.
-		On("Login", &creds).
+		On("Login", mock.Anything, &creds).
 		Return(token, user, nil)
+	// mock IssueRefreshToken method to return a refresh token
+	suite.mockUseCase.
+		On("IssueRefreshToken", user.ID.Hex()).
+		Return("mocked.refresh.token", nil)
 
 	// create test request with JSON body
 	body, _ := json.Marshal(creds)
@@ -169,7 +271,66 @@ func (suite *UserControllerTestSuite) TestLogin_Success() {
 	suite.router.ServeHTTP(resp, req)
 
 	// verify response
-	assert.Equal(suite.T(), http.StatusOK, resp.Code)       // status should be 200
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                       // status should be 200
+	assert.Contains(suite.T(), resp.Body.String(), "mocked.refresh.token")  // refresh token returned
+}
+
+// tests successful token refresh
+func (suite *UserControllerTestSuite) TestRefresh_Success() {
+
+	// mock Refresh method to return a new access token
+	suite.mockUseCase.
+		On("Refresh", mock.Anything, "refresh.token").
+		Return("new.access.token", nil)
+
+	// create test request with JSON body
+	body, _ := json.Marshal(domain.RefreshRequest{RefreshToken: "refresh.token"})
+	req, _ := http.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(body))       // create test request
+	req.Header.Set("Content-Type", "application/json")        // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                                // status should be 200
+	assert.JSONEq(suite.T(), `{"token":"new.access.token"}`, resp.Body.String())     // new access token returned
+}
+
+// tests refresh with an invalid token
+func (suite *UserControllerTestSuite) TestRefresh_InvalidToken() {
+
+	// mock Refresh method to return unauthorized
+	suite.mockUseCase.
+		On("Refresh", mock.Anything, "access.token").
+		Return("", domain.ErrUnauthorized)
+
+	// create test request with JSON body
+	body, _ := json.Marshal(domain.RefreshRequest{RefreshToken: "access.token"})
+	req, _ := http.NewRequest(http.MethodPost, "/refresh", bytes.NewBuffer(body))       // create test request
+	req.Header.Set("Content-Type", "application/json")        // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)       // status should be 401
+}
+
+// tests refresh without a token
+func (suite *UserControllerTestSuite) TestRefresh_MissingToken() {
+
+	req, _ := http.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(`{}`))       // create test request
+	req.Header.Set("Content-Type", "application/json")        // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                    // status should be 400
+	suite.mockUseCase.AssertNotCalled(suite.T(), "Refresh", mock.Anything, mock.Anything)       // usecase should not be called
 }
 
 // tests login with invalid credentials
@@ -183,7 +344,7 @@ func (suite *UserControllerTestSuite) TestLogin_InvalidCredentials() {
 	
 	// mock Login method to return empty, nil and  error 
 	suite.mockUseCase.
-		On("Login", &creds).
+		On("Login", mock.Anything, &creds).
 		Return("", nil, domain.ErrInvalidCredentials)
 
 	// create test request with JSON body
@@ -218,6 +379,85 @@ func (suite *UserControllerTestSuite) TestLogin_EmptyCredentials() {
     assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)         // status should be 400
 }
 
+// tests login with missing password returns structured field errors
+func (suite *UserControllerTestSuite) TestLogin_MissingPasswordStructured() {
+
+	// create test request without a password
+	body := []byte(`{"username":"john"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))        // create test request
+	req.Header.Set("Content-Type", "application/json")         // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify structured response
+	var result struct {
+		Errors  map[string]string  `json:"errors"`
+		Code    string             `json:"code"`
+	}
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                              // status should be 400
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &result))                  // body should be json
+	assert.Equal(suite.T(), map[string]string{"password": "required"}, result.Errors)      // only password is reported
+	assert.Equal(suite.T(), "VALIDATION_FAILED", result.Code)                              // code should be validation failed
+}
+
+// tests listing users never exposes passwords
+func (suite *UserControllerTestSuite) TestListUsers_Success() {
+
+	// mock ListUsers to return users, one still carrying a hash
+	suite.mockUseCase.
+		On("ListUsers", mock.Anything, 1, defaultPageSize).
+		Return([]domain.User{
+			{ID: primitive.NewObjectID(), Username: "alice", Role: "admin"},
+			{ID: primitive.NewObjectID(), Username: "bob", Password: "$2a$10$hash", Role: "user"},
+		}, nil)
+
+	// create test request
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)       // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	var result []map[string]interface{}
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                         // status should be 200
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &result))     // body should be json
+	assert.Len(suite.T(), result, 2)                                          // every user listed
+	assert.NotContains(suite.T(), resp.Body.String(), "hash")                 // hash never serialized
+	assert.NotContains(suite.T(), resp.Body.String(), "assword")              // no password key at all
+}
+
+// tests page and limit are passed to the usecase
+func (suite *UserControllerTestSuite) TestListUsers_Page() {
+
+	suite.mockUseCase.
+		On("ListUsers", mock.Anything, 2, 50).
+		Return([]domain.User{{ID: primitive.NewObjectID(), Username: "carol", Password: "$2a$10$hash", Role: "user"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users?page=2&limit=50", nil)       // create test request
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                         // status should be 200
+	assert.Contains(suite.T(), resp.Body.String(), "carol")                   // requested page listed
+	assert.NotContains(suite.T(), resp.Body.String(), "assword")              // password never serialized
+}
+
+// tests an out of range page size is rejected
+func (suite *UserControllerTestSuite) TestListUsers_InvalidLimit() {
+
+	suite.mockUseCase.On("ListUsers", mock.Anything, 1, 500).Return(nil, domain.ErrInvalidPageSize)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users?limit=500", nil)       // create test request
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                 // status should be 400
+	assert.Contains(suite.T(), resp.Body.String(), "INVALID_PAGE_SIZE")       // should contain error code
+}
+
 // tests successful user promotion to admin
 func (suite *UserControllerTestSuite) TestPromoteToAdmin_Success() {
 
@@ -226,7 +466,7 @@ func (suite *UserControllerTestSuite) TestPromoteToAdmin_Success() {
 
 	// mock PromoteToAdmin to return no error
 	suite.mockUseCase.
-		On("PromoteToAdmin", id).
+		On("PromoteToAdmin", mock.Anything, id).
 		Return(nil)
 
 	// create test request
@@ -245,7 +485,7 @@ func (suite *UserControllerTestSuite) TestPromoteToAdmin_InvalidID() {
 
 	// mock PromoteToAdmin method to return error 
 	suite.mockUseCase.
-		On("PromoteToAdmin", "invalid-id").
+		On("PromoteToAdmin", mock.Anything, "invalid-id").
 		Return(domain.ErrInvalidUserID)
 
 	// create test request with invalid ID
@@ -266,7 +506,7 @@ func (suite *UserControllerTestSuite) TestPromoteToAdmin_UserNotFound() {
 
     // mock PromoteToAdmin to return user not found
     suite.mockUseCase.
-        On("PromoteToAdmin", validID).
+        On("PromoteToAdmin", mock.Anything, validID).
         Return(domain.ErrUserNotFound)
 
 	// create test request with valid ID
@@ -279,6 +519,27 @@ func (suite *UserControllerTestSuite) TestPromoteToAdmin_UserNotFound() {
     assert.Equal(suite.T(), http.StatusNotFound, resp.Code)         // status should be 404
 }
 
+// tests promotion reports database failures as 500
+func (suite *UserControllerTestSuite) TestPromoteToAdmin_DatabaseError() {
+
+	validID := primitive.NewObjectID().Hex()
+
+	// mock PromoteToAdmin to fail with a raw database error
+	suite.mockUseCase.
+		On("PromoteToAdmin", mock.Anything, validID).
+		Return(errors.New("db down"))
+
+	// create test request with valid ID
+	req, _ := http.NewRequest(http.MethodPut, "/promote/"+validID, nil)
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusInternalServerError, resp.Code)       // status should be 500
+	assert.Contains(suite.T(), resp.Body.String(), "INTERNAL_ERROR")         // generic internal code
+}
+
 // tests promotion with empty ID parameter
 func (suite *UserControllerTestSuite) TestPromoteToAdmin_EmptyID() {
 
@@ -292,6 +553,285 @@ func (suite *UserControllerTestSuite) TestPromoteToAdmin_EmptyID() {
     assert.Equal(suite.T(), http.StatusNotFound, resp.Code)        // status should be 404
 }
 
+// tests successful demotion of an admin
+func (suite *UserControllerTestSuite) TestDemoteFromAdmin_Success() {
+
+	// mock user ID
+	id := primitive.NewObjectID().Hex()
+
+	// mock DemoteFromAdmin to return no error
+	suite.mockUseCase.
+		On("DemoteFromAdmin", mock.Anything, id).
+		Return(nil)
+
+	// create test request
+	req, _ := http.NewRequest(http.MethodPut, "/demote/"+id, nil)       // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)       // status should be 200
+}
+
+// tests demotion of the last admin
+func (suite *UserControllerTestSuite) TestDemoteFromAdmin_LastAdmin() {
+
+	// mock user ID
+	id := primitive.NewObjectID().Hex()
+
+	// mock DemoteFromAdmin to return last admin error
+	suite.mockUseCase.
+		On("DemoteFromAdmin", mock.Anything, id).
+		Return(domain.ErrLastAdmin)
+
+	// create test request
+	req, _ := http.NewRequest(http.MethodPut, "/demote/"+id, nil)       // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	assert.Equal(suite.T(), http.StatusConflict, resp.Code)       // status should be 409
+	assert.Contains(suite.T(), resp.Body.String(), "LAST_ADMIN")  // code should be in response body
+}
+
+// tests demotion when user is not found
+func (suite *UserControllerTestSuite) TestDemoteFromAdmin_UserNotFound() {
+
+	// mock valid user id
+	validID := primitive.NewObjectID().Hex()
+
+	// mock DemoteFromAdmin to return user not found
+	suite.mockUseCase.
+		On("DemoteFromAdmin", mock.Anything, validID).
+		Return(domain.ErrUserNotFound)
+
+	// create test request with valid ID
+	req, _ := http.NewRequest(http.MethodPut, "/demote/"+validID, nil)
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)         // status should be 404
+}
+
+// tests demotion with invalid user ID format
+func (suite *UserControllerTestSuite) TestDemoteFromAdmin_InvalidID() {
+
+	// create test request with invalid ID
+	req, _ := http.NewRequest(http.MethodPut, "/demote/invalid-id", nil)      // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                    // status should be 400
+	suite.mockUseCase.AssertNotCalled(suite.T(), "DemoteFromAdmin", mock.Anything, mock.Anything)  // usecase should not be called
+}
+
+// tests successful update of own preferences
+func (suite *UserControllerTestSuite) TestUpdatePreferences_Success() {
+
+	prefs := domain.Preferences{DefaultPriority: "high"}
+
+	// mock UpdatePreferences to return no error for the authenticated user
+	suite.mockUseCase.
+		On("UpdatePreferences", mock.Anything, preferencesUserID, &prefs).
+		Return(nil)
+
+	// create test request with JSON body
+	body, _ := json.Marshal(prefs)
+	req, _ := http.NewRequest(http.MethodPut, "/me/preferences", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	var result domain.Preferences
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                         // status should be 200
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &result))     // body should be json
+	assert.Equal(suite.T(), prefs, result)                                    // saved preferences are returned
+}
+
+// tests reading own preferences
+func (suite *UserControllerTestSuite) TestGetPreferences_Success() {
+
+	prefs := &domain.Preferences{Timezone: "Africa/Addis_Ababa", DefaultPriority: "low", PageSize: 25, Notifications: true}
+
+	// mock GetPreferences to return stored preferences of the authenticated user
+	suite.mockUseCase.
+		On("GetPreferences", mock.Anything, preferencesUserID).
+		Return(prefs, nil)
+
+	// create test request
+	req, _ := http.NewRequest(http.MethodGet, "/me/preferences", nil)      // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+
+	// verify response
+	var result domain.Preferences
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)                         // status should be 200
+	assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &result))     // body should be json
+	assert.Equal(suite.T(), *prefs, result)                                   // stored preferences are returned
+}
+
+// tests preferences update with an invalid priority
+func (suite *UserControllerTestSuite) TestUpdatePreferences_InvalidPriority() {
+
+	// mock UpdatePreferences to reject the priority
+	suite.mockUseCase.
+		On("UpdatePreferences", mock.Anything, preferencesUserID, mock.Anything).
+		Return(domain.ErrInvalidPriority)
+
+	// create test request with unknown priority
+	req, _ := http.NewRequest(http.MethodPut, "/me/preferences", bytes.NewBufferString(`{"default_priority":"urgent"}`))
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)        // status should be 400
+}
+
+// tests successful password change
+func (suite *UserControllerTestSuite) TestChangePassword_Success() {
+
+	// mock ChangePassword to return no error for the authenticated user
+	suite.mockUseCase.
+		On("ChangePassword", mock.Anything, preferencesUserID, "oldpassword", "newpassword").
+		Return(nil)
+
+	// create test request with JSON body
+	body := []byte(`{"old_password":"oldpassword","new_password":"newpassword"}`)
+	req, _ := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)        // status should be 200
+}
+
+// tests password change with a wrong current password
+func (suite *UserControllerTestSuite) TestChangePassword_WrongOldPassword() {
+
+	// mock ChangePassword to reject the old password
+	suite.mockUseCase.
+		On("ChangePassword", mock.Anything, preferencesUserID, "wrongpassword", "newpassword").
+		Return(domain.ErrInvalidCredentials)
+
+	// create test request with JSON body
+	body := []byte(`{"old_password":"wrongpassword","new_password":"newpassword"}`)
+	req, _ := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)        // status should be 401
+}
+
+// tests password change with a too short new password
+func (suite *UserControllerTestSuite) TestChangePassword_ShortNewPassword() {
+
+	// mock ChangePassword to reject the new password
+	suite.mockUseCase.
+		On("ChangePassword", mock.Anything, preferencesUserID, "oldpassword", "short").
+		Return(errors.New("password must be at least 8 characters"))
+
+	// create test request with JSON body
+	body := []byte(`{"old_password":"oldpassword","new_password":"short"}`)
+	req, _ := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)        // status should be 400
+}
+
+// tests password change with a missing field
+func (suite *UserControllerTestSuite) TestChangePassword_MissingField() {
+
+	// create test request without the new password
+	body := []byte(`{"old_password":"oldpassword"}`)
+	req, _ := http.NewRequest(http.MethodPut, "/password", bytes.NewBuffer(body))      // create test request
+	req.Header.Set("Content-Type", "application/json")      // set content type header
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                                                      // status should be 400
+	assert.Contains(suite.T(), resp.Body.String(), `"new_password":"required"`)                                    // missing field reported
+	suite.mockUseCase.AssertNotCalled(suite.T(), "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)    // usecase should not be called
+}
+
+// tests successful deletion of another user
+func (suite *UserControllerTestSuite) TestDeleteUser_Success() {
+
+	id := primitive.NewObjectID().Hex()
+
+	// mock DeleteUser to return no error
+	suite.mockUseCase.
+		On("DeleteUser", mock.Anything, id).
+		Return(nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/users/"+id, nil)      // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)          // status should be 200
+	suite.mockUseCase.AssertExpectations(suite.T())            // verify mock was called
+}
+
+// tests an admin cannot delete their own account
+func (suite *UserControllerTestSuite) TestDeleteUser_Self() {
+
+	req, _ := http.NewRequest(http.MethodDelete, "/users/"+preferencesUserID, nil)      // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusForbidden, resp.Code)                        // status should be 403
+	assert.Contains(suite.T(), resp.Body.String(), "CANNOT_DELETE_SELF")            // check response body
+	suite.mockUseCase.AssertNotCalled(suite.T(), "DeleteUser", mock.Anything, mock.Anything)       // usecase should not be called
+}
+
+// tests deletion of a user that does not exist
+func (suite *UserControllerTestSuite) TestDeleteUser_NotFound() {
+
+	id := primitive.NewObjectID().Hex()
+
+	// mock DeleteUser to return user not found
+	suite.mockUseCase.
+		On("DeleteUser", mock.Anything, id).
+		Return(domain.ErrUserNotFound)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/users/"+id, nil)      // create test request
+	resp := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(resp, req)
+	// verify response
+	assert.Equal(suite.T(), http.StatusNotFound, resp.Code)        // status should be 404
+}
+
 // runs the test suite for UserController
 func TestUserController(t *testing.T) {
 	suite.Run(t, new(UserControllerTestSuite))       // run the test suite
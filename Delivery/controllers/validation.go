@@ -0,0 +1,154 @@
+package controllers
+
+// imports
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// converts binding validation errors into a field keyed map, e.g. {"password": "required"}
+// reports false when err is not a validation error (malformed json, wrong types ...)
+func validationErrors(err error, obj interface{}) (map[string]string, bool) {
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+
+	fields := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		fields[jsonFieldName(obj, fe.StructField())] = fe.Tag()       // failed rule, e.g. "required"
+	}
+
+	return fields, true
+}
+
+// prefix of the decoder error for a field obj does not have, e.g. json: unknown field "titel"
+const unknownFieldPrefix = "json: unknown field "
+
+// returned by decodeJSON for a body field the target struct does not have
+type unknownFieldError struct {
+	Field  string        // json name as sent by the client
+}
+
+func (e *unknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field '%s'", e.Field)
+}
+
+// decodes the json body into obj, rejecting fields obj does not have, then runs the binding rules
+func decodeJSON(c *gin.Context, obj interface{}) error {
+
+	if c.Request == nil || c.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()        // a misspelled field is an error, not silently dropped
+	if err := decoder.Decode(obj); err != nil {
+		// the decoder only reports unknown fields as text, give them a type handlers can check
+		if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+			return &unknownFieldError{Field: strings.Trim(field, `"`)}
+		}
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// binds the json body into obj, answering 400 itself when the body is unusable
+func bindJSON(c *gin.Context, obj interface{}) bool {
+
+	err := decodeJSON(c, obj)
+	if err != nil {
+		respondBindError(c, err, obj)
+		return false
+	}
+
+	return true
+}
+
+// reports a binding failure: per field rules, where the json broke, or the raw error
+func respondBindError(c *gin.Context, err error, obj interface{}) {
+
+	if fields, ok := validationErrors(err, obj); ok {
+		respond(c, http.StatusBadRequest, gin.H{"errors": fields, "code": codeValidationFailed.Code})
+		return
+	}
+	var unknownErr *unknownFieldError
+	if errors.As(err, &unknownErr) {
+		respond(c, http.StatusBadRequest, gin.H{"errors": gin.H{unknownErr.Field: "unknown"}, "code": codeValidationFailed.Code})
+		return
+	}
+	if message, ok := jsonBindError(err); ok {
+		badRequest(c, message)
+		return
+	}
+
+	badRequest(c, err.Error())
+}
+
+// describes broken json and wrongly typed values with the byte offset they were found at
+// reports false for any other error
+func jsonBindError(err error) (string, bool) {
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("malformed JSON at offset %d: %s", syntaxErr.Offset, syntaxErr.Error()), true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return fmt.Sprintf("invalid type for request body at offset %d, expected %s", typeErr.Offset, typeErr.Type), true
+		}
+		return fmt.Sprintf("invalid type for field '%s' at offset %d, expected %s", typeErr.Field, typeErr.Offset, typeErr.Type), true
+	}
+
+	return "", false
+}
+
+// returns the json name of a struct field, falling back to the lowercased field name
+func jsonFieldName(obj interface{}, field string) string {
+
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if f, ok := t.FieldByName(field); ok {
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return strings.ToLower(field)
+}
+
+// lists the fields a new task is missing, in the same shape as validationErrors
+func missingTaskFields(task *domain.Task) map[string]string {
+
+	fields := map[string]string{}
+	if task.Title == "" {
+		fields["title"] = "required"
+	}
+	if task.Description == "" {
+		fields["description"] = "required"
+	}
+	if task.Status == "" {
+		fields["status"] = "required"
+	}
+	if task.DueDate.IsZero() {
+		fields["due_date"] = "required"
+	}
+
+	return fields
+}
@@ -0,0 +1,105 @@
+package controllers
+
+// imports
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// body bound by the test route
+type bindTarget struct {
+	Name   string   `json:"name" binding:"required"`
+	Count  int      `json:"count"`
+}
+
+// test suite for the shared body binding
+type BindJSONTestSuite struct {
+	suite.Suite
+	router  *gin.Engine        // gin router instance
+	bound   bindTarget         // body seen by the handler after a successful bind
+}
+
+// intialize the test suite before each test
+func (suite *BindJSONTestSuite) SetupTest() {
+
+	gin.SetMode(gin.TestMode)
+	suite.bound = bindTarget{}
+
+	router := gin.New()
+	router.POST("/bind", func(c *gin.Context) {
+		var target bindTarget
+		if !bindJSON(c, &target) {
+			return
+		}
+		suite.bound = target
+		c.Status(http.StatusNoContent)
+	})
+	suite.router = router
+}
+
+// posts body to the test route and decodes the json response, if any
+func (suite *BindJSONTestSuite) post(body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+
+	req, _ := http.NewRequest(http.MethodPost, "/bind", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var decoded map[string]interface{}
+	if resp.Body.Len() > 0 {
+		assert.NoError(suite.T(), json.Unmarshal(resp.Body.Bytes(), &decoded))        // body should be json
+	}
+
+	return resp, decoded
+}
+
+// tests a valid body reaches the handler
+func (suite *BindJSONTestSuite) TestValidInput() {
+
+	resp, _ := suite.post(`{"name":"weekly","count":3}`)
+
+	assert.Equal(suite.T(), http.StatusNoContent, resp.Code)                       // handler ran
+	assert.Equal(suite.T(), bindTarget{Name: "weekly", Count: 3}, suite.bound)     // body was bound
+}
+
+// tests a field the target does not have is reported by name
+func (suite *BindJSONTestSuite) TestUnknownField() {
+
+	resp, body := suite.post(`{"name":"weekly","cuont":3}`)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                                     // status should be 400
+	assert.Equal(suite.T(), map[string]interface{}{"cuont": "unknown"}, body["errors"])           // misspelled field
+	assert.Equal(suite.T(), "VALIDATION_FAILED", body["code"])
+	assert.Equal(suite.T(), bindTarget{}, suite.bound)                                            // handler not reached
+}
+
+// tests a wrongly typed value names the field and the expected type
+func (suite *BindJSONTestSuite) TestTypeMismatch() {
+
+	resp, body := suite.post(`{"name":"weekly","count":"three"}`)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)        // status should be 400
+	assert.Equal(suite.T(), "invalid type for field 'count' at offset 32, expected int", body["error"])
+	assert.Equal(suite.T(), "INVALID_REQUEST", body["code"])
+}
+
+// tests binding rules still run after decoding
+func (suite *BindJSONTestSuite) TestMissingRequiredField() {
+
+	resp, body := suite.post(`{"count":3}`)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.Code)                                   // status should be 400
+	assert.Equal(suite.T(), map[string]interface{}{"name": "required"}, body["errors"])         // rule that failed
+	assert.Equal(suite.T(), "VALIDATION_FAILED", body["code"])
+}
+
+// runs the test suite for the shared body binding
+func TestBindJSONTestSuite(t *testing.T) {
+	suite.Run(t, new(BindJSONTestSuite))
+}
@@ -3,25 +3,124 @@ package main
 // imports
 import (
 	"log"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Delivery/controllers"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Delivery/routers"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases"
 )
 
 // entry point of the Task Management application
 func main() {
 
-	jwtservice, _ := infrastructure.NewJWTService()              // setup jwt service infrastructure
+	jwtservice, err := infrastructure.NewJWTService()            // setup jwt service infrastructure
+	if err != nil {
+		log.Fatalf("cannot set up the jwt service, check JWT_SECRET or JWT_ALG and JWT_PRIVATE_KEY_PATH: %v", err)
+	}
 	passwordService := infrastructure.NewPasswordService()       // setup password service infrastructure
 
-	taskRepo := repositories.NewTaskRepository()       // setup task repositorie
-	userRepo := repositories.NewUserRepository()       // setup user repositorie
+	config := infrastructure.LoadConfig()        // load application configuration
 
-	taskUC := usecases.NewTaskUseCase(taskRepo)                                    // setup task use case
-	userUC := usecases.NewUserUseCase(userRepo, jwtservice, passwordService)       // setup user use case
+	// shared limiter for concurrent database operations
+	dbLimiter := adapters.NewOperationLimiter(config.DBMaxConcurrentOps, config.DBOpQueueTimeout)
+	dbTimeouts := repositories.OperationTimeouts{
+		Read:      config.DBReadTimeout,
+		Write:     config.DBWriteTimeout,
+		Aggregate: config.DBAggregateTimeout,
+	}
 
-	router := routers.SetupRouter(taskUC, userUC, jwtservice)       // initialize the router with all configured routes
+	// setup task repositorie
+	taskRepo, err := repositories.NewTaskRepositoryWithConfig(dbLimiter, repositories.TaskRepositoryConfig{
+		OmitCompletedDescriptions: config.TaskListOmitCompletedDescriptions,
+		MaxDocumentFraction:       config.TaskMaxDocumentFraction,
+		Timeouts:                  dbTimeouts,
+	})
+	if err != nil {
+		log.Fatalf("cannot set up the task repository, check MONGO_URI: %v", err)
+	}
+	userRepo, err := repositories.NewUserRepository(dbLimiter, dbTimeouts)       // setup user repositorie
+	if err != nil {
+		log.Fatalf("cannot set up the user repository, check MONGO_URI: %v", err)
+	}
+	projectRepo, err := repositories.NewProjectRepository(dbLimiter, dbTimeouts)       // setup project repositorie
+	if err != nil {
+		log.Fatalf("cannot set up the project repository, check MONGO_URI: %v", err)
+	}
+
+	// setup task use case with configured task rules
+	taskUC := usecases.NewTaskUseCaseWithConfig(taskRepo, userRepo, usecases.TaskConfig{
+		UpdatableFields: config.TaskUpdatableFields,
+		StrictDueDate:   config.TaskStrictDueDate,
+		MaxDescriptionBytes: config.TaskMaxDescriptionBytes,
+		WorkdayDueDates: config.TaskWorkdayDueDates,
+		CreateRateLimit: config.TaskCreateRateLimit,
+		CreateAttempts:  infrastructure.NewMemoryAttemptStore(config.TaskCreateRateWindow, nil),
+		SelfAssign:      config.TaskSelfAssign,
+		LockInProgressAssignee: config.TaskLockInProgressAssignee,
+		ReopenCompleted:        config.TaskReopenCompleted,
+		LockCompleted:          config.TaskLockCompleted,
+		MaxDependencies:        config.TaskMaxDependencies,
+		MaxDependencyDepth:     config.TaskMaxDependencyDepth,
+		Projects:               projectRepo,
+	})
+	// setup user use case with configured user rules
+	userUC := usecases.NewUserUseCaseWithConfig(userRepo, jwtservice, passwordService, usecases.UserConfig{
+		SeedAdminUsername: config.SeedAdminUsername,
+		PasswordChangeGrace: config.PasswordChangeGrace,
+		PasswordPolicy: domain.PasswordPolicy{
+			MinLength:       config.PasswordMinLength,
+			RequiredClasses: config.PasswordRequiredClasses,
+		},
+	})
+
+	// archive old completed tasks in the background when enabled
+	if config.TaskArchiveEnabled && config.TaskArchiveAfter > 0 && config.TaskArchiveInterval > 0 {
+		archiveJob := usecases.NewArchiveJob(taskRepo, config.TaskArchiveAfter, config.TaskArchiveInterval, nil)
+		go archiveJob.Start(make(chan struct{}))
+	}
+	// permanently delete tasks kept longer than their status allows when rules are configured
+	if len(config.TaskRetentionRules) > 0 && config.TaskRetentionInterval > 0 {
+		retentionJob := usecases.NewRetentionJob(taskRepo, config.TaskRetentionRules, config.TaskRetentionInterval, nil)
+		go retentionJob.Start(make(chan struct{}))
+	}
+
+	// api keys for service clients when enabled
+	var apiKeyUC domain.APIKeyUseCase
+	if config.APIKeysEnabled {
+		apiKeyRepo, err := repositories.NewAPIKeyRepository(dbLimiter, dbTimeouts)
+		if err != nil {
+			log.Fatalf("cannot set up the api key repository, check MONGO_URI: %v", err)
+		}
+		apiKeyUC = usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo, passwordService)
+	}
+
+	// readiness probe pings the same database the repositories use
+	mongoURI, _ := repositories.MongoSettings()
+	readiness, err := infrastructure.NewMongoPinger(mongoURI)
+	if err != nil {
+		log.Fatalf("cannot set up the readiness probe, check MONGO_URI: %v", err)
+	}
+
+	// initialize the router with all configured routes
+	router := routers.SetupRouterWithConfig(taskUC, userUC, jwtservice, routers.RouterConfig{
+		TaskController: controllers.TaskControllerConfig{
+			AcceptEpochDueDates: config.TaskEpochDueDates,
+			RequireIfMatch:      config.TaskRequireIfMatch,
+		},
+		UserController: controllers.UserControllerConfig{ExposePasswordPolicy: config.PasswordPolicyInErrors},
+		MaxConcurrentAuth: config.AuthMaxConcurrent,
+		CORSOrigins: config.CORSOrigins,
+		HTTPS: infrastructure.HTTPSConfig{Redirect: config.HTTPSRedirect, HSTSMaxAge: config.HSTSMaxAge},
+		LoginMaxAttempts: config.LoginMaxAttempts,
+		LoginWindow: config.LoginWindow,
+		LoginWarnPercent: config.LoginWarnPercent,
+		Readiness: readiness,
+		APIKeys: apiKeyUC,
+		Projects: usecases.NewProjectUseCase(projectRepo, taskRepo),
+		AccessLog: infrastructure.AccessLogConfig{SampleRate: config.AccessLogSampleRate},
+	})
 
 	// start the server on port 8080
 	router.Run(":8080")                        
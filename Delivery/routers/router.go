@@ -2,32 +2,122 @@ package routers
 
 // imports
 import (
+	"time"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Delivery/controllers"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure"
 )
 
+// configurable request handling of the router
+type RouterConfig struct {
+	TaskController  controllers.TaskControllerConfig        // task request parsing rules
+	UserController  controllers.UserControllerConfig        // user response rules
+	Blacklist       infrastructure.TokenBlacklist           // logged out tokens (nil = new in-memory blacklist)
+	MaxConcurrentAuth  int                                  // concurrent password hashing requests (0 = unlimited)
+	CORSOrigins     []string                                // origins allowed to call the api from a browser ("*" = any)
+	HTTPS           infrastructure.HTTPSConfig              // https redirect and HSTS, probes are always exempt from the redirect
+	LoginMaxAttempts   int                                  // failed logins per client before a lockout (0 = unlimited)
+	LoginWindow     time.Duration                           // how long failed logins count, also the lockout length
+	LoginWarnPercent   int                                  // warn clients once at most this percent of login attempts is left (0 = never)
+	Readiness       infrastructure.Pinger                   // database checked by /readyz (nil = always ready)
+	APIKeys         domain.APIKeyUseCase                    // api key authentication for service clients (nil = disabled)
+	Projects        domain.ProjectUseCase                   // project endpoints (nil = disabled)
+	AccessLog       infrastructure.AccessLogConfig          // sampling and output of the request log
+}
+
+// returns the default router settings
+func DefaultRouterConfig() RouterConfig {
+	return RouterConfig{
+		TaskController: controllers.DefaultTaskControllerConfig(),
+		UserController: controllers.DefaultUserControllerConfig(),
+		CORSOrigins:    []string{"*"},
+		LoginMaxAttempts:  5,
+		LoginWindow:    15 * time.Minute,
+		LoginWarnPercent:  20,
+	}
+}
+
 // setup router
 func SetupRouter( taskUsc domain.TaskUseCase, userUsc domain.UserUseCase, jwtServ domain.JWTService) *gin.Engine {
+	return SetupRouterWithConfig(taskUsc, userUsc, jwtServ, DefaultRouterConfig())
+}
+
+// setup router with custom request handling
+func SetupRouterWithConfig(taskUsc domain.TaskUseCase, userUsc domain.UserUseCase, jwtServ domain.JWTService, config RouterConfig) *gin.Engine {
 
-	router := gin.Default()     // create default gin router
+	router := gin.New()         // create gin router
+	router.Use(infrastructure.NewAccessLogMiddleware(config.AccessLog), gin.Recovery())      // log panics recovered below as 500s too
 
-	taskContrl := controllers.NewTaskController(taskUsc)        // initialize task controller with task usecase
-	userContrl := controllers.NewUserController(userUsc)        // initialize user controller with user usecase
+	// orchestrators probe over plain http inside the cluster
+	httpsConfig := config.HTTPS
+	httpsConfig.ExemptPaths = append([]string{"/livez", "/readyz", "/health"}, config.HTTPS.ExemptPaths...)
+	router.Use(infrastructure.NewHTTPSMiddleware(httpsConfig))              // first, so plain http never reaches a handler
+	router.Use(infrastructure.NewCORSMiddleware(config.CORSOrigins))        // before the groups, so preflights skip auth
+
+	taskContrl := controllers.NewTaskControllerWithConfig(taskUsc, config.TaskController)        // initialize task controller with task usecase
+	userContrl := controllers.NewUserControllerWithConfig(userUsc, config.UserController)        // initialize user controller with user usecase
+
+	// shared bound on routes hashing or checking passwords
+	authThrottle := infrastructure.NewAuthThrottle(config.MaxConcurrentAuth).Handler()
 
 	// public routes
-	router.POST("/register", userContrl.Register)         // register new user
-	router.POST("/login", userContrl.Login)               // authenticate a user
+	router.POST("/register", authThrottle, userContrl.Register)         // register new user
+	loginLimiter := infrastructure.NewLoginRateLimiterWithConfig(infrastructure.LoginLimitConfig{
+		MaxAttempts: config.LoginMaxAttempts,
+		Window:      config.LoginWindow,
+		WarnPercent: config.LoginWarnPercent,
+	}).Handler()
+	router.POST("/login", loginLimiter, authThrottle, userContrl.Login)   // authenticate a user
+	router.POST("/refresh", userContrl.Refresh)           // exchange a refresh token for a new access token
+	router.GET("/errors", controllers.ListErrorCodes)     // list error codes clients can receive
+
+	// probes for orchestrators such as kubernetes
+	health := infrastructure.NewHealthHandler(config.Readiness, 2*time.Second)
+	router.GET("/livez", health.Livez)         // process is up
+	router.GET("/readyz", health.Readyz)       // database is reachable
+	router.GET("/health", health.Health)       // readiness with the database state in the body
 
 	// authenticated routes
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtServ)
+	blacklist := config.Blacklist
+	if blacklist == nil {
+		blacklist = infrastructure.NewMemoryBlacklist()
+	}
+	authMiddleware := infrastructure.NewAuthMiddlewareWithConfig(jwtServ, infrastructure.AuthConfig{
+		TokenChecker: userUsc,        // revokes tokens issued before a password change
+		Blacklist:    blacklist,      // revokes tokens on logout
+		APIKeys:      config.APIKeys, // accepts X-API-Key when enabled
+	})
 
 	authGroup := router.Group("")
 	authGroup.Use(authMiddleware.Handler())
 	{
 		authGroup.GET("/tasks", taskContrl.GetAllTasks)             // get all tasks
+		authGroup.GET("/tasks/recent", taskContrl.GetRecentlyUpdated)       // get recently updated tasks
+		authGroup.GET("/tasks/upcoming", taskContrl.GetUpcomingTasks)       // get tasks due within n days
+		authGroup.GET("/tasks/overdue", taskContrl.GetOverdueTasks)         // get tasks past their due date
+		authGroup.GET("/tasks/export", taskContrl.ExportTasksCSV)           // download all tasks as csv
+		authGroup.GET("/tasks/stats", taskContrl.GetTaskStats)              // count tasks per status
 		authGroup.GET("/tasks/:id", taskContrl.GetTaskByID)         // get specific task by id
+		authGroup.GET("/tasks/:id/dependencies", taskContrl.GetTaskDependencies)     // get the tasks a task depends on
+		authGroup.GET("/mytasks", taskContrl.GetTasksByUser)        // get tasks assigned to the current user
+		authGroup.PATCH("/tasks/:id/assign", taskContrl.AssignTask)         // assign task to a user, policy checked by the usecase
+		authGroup.POST("/tasks/:id/reopen", taskContrl.ReopenTask)          // move own completed task back to in_progress, policy checked by the usecase
+		authGroup.GET("/me/preferences", userContrl.GetPreferences)         // get own preferences
+		authGroup.PUT("/me/preferences", userContrl.UpdatePreferences)      // update own preferences
+		authGroup.PUT("/password", authThrottle, userContrl.ChangePassword)     // change own password
+		authGroup.POST("/logout", authMiddleware.Logout())                  // revoke the current token
+		authGroup.GET("/token", authMiddleware.Introspect())                // describe the current token
+		if config.Projects != nil {
+			projectContrl := controllers.NewProjectController(config.Projects)
+			authGroup.POST("/projects", projectContrl.CreateProject)         // create own project
+			authGroup.GET("/projects", projectContrl.ListProjects)           // list own projects, admins see all
+			authGroup.GET("/projects/:id", projectContrl.GetProject)         // get own project by id
+			authGroup.GET("/projects/:id/stats", projectContrl.GetProjectStats)      // count own project's tasks per status and priority
+			authGroup.PUT("/projects/:id", projectContrl.RenameProject)      // rename own project by id
+			authGroup.DELETE("/projects/:id", projectContrl.DeleteProject)   // delete own project by id, optionally with its tasks
+			authGroup.PATCH("/tasks/:id/move", taskContrl.MoveTask)          // move own task into another own project or out of its project
+		}
 	}
 
 	// admin routes
@@ -38,8 +128,23 @@ func SetupRouter( taskUsc domain.TaskUseCase, userUsc domain.UserUseCase, jwtSer
 	{
 		adminGroup.POST("/tasks", taskContrl.CreateTask)                 // create new task
 		adminGroup.PUT("/tasks/:id", taskContrl.UpdateTask)              // update existing task by id
-		adminGroup.DELETE("/tasks/:id", taskContrl.DeleteTask)           // delete existing task by id
+		adminGroup.DELETE("/tasks/:id", taskContrl.DeleteTask)           // soft delete existing task by id
+		adminGroup.POST("/tasks/:id/restore", taskContrl.RestoreTask)    // restore soft deleted task by id
+		adminGroup.POST("/tasks/complete", taskContrl.CompleteTasks)     // complete many tasks at once
+		adminGroup.POST("/tasks/stats/by-owner", taskContrl.GetStatsByOwner)     // count tasks per owner and status
+		adminGroup.GET("/tasks/by-assignee", taskContrl.GetTasksByAssignee)      // count assigned tasks per assignee
 		adminGroup.PUT("/promote/:id", userContrl.PromoteToAdmin)        // promote user to admin by id
+		adminGroup.PUT("/demote/:id", userContrl.DemoteFromAdmin)        // demote admin to user by id
+		adminGroup.GET("/users", userContrl.ListUsers)                   // list all users
+		adminGroup.DELETE("/users/:id", userContrl.DeleteUser)           // delete user by id
+		adminGroup.GET("/users/:id/tasks", taskContrl.GetTasksByOwner)   // list one page of a user's tasks
+		adminGroup.POST("/users/:id/reassign-tasks", taskContrl.ReassignTasks)   // move a user's tasks to another user
+		if config.APIKeys != nil {
+			keyContrl := controllers.NewAPIKeyController(config.APIKeys)
+			adminGroup.POST("/apikeys", keyContrl.CreateAPIKey)          // mint an api key for a service client
+			adminGroup.GET("/apikeys", keyContrl.ListAPIKeys)            // list own api keys by prefix
+			adminGroup.DELETE("/apikeys/:id", keyContrl.RevokeAPIKey)    // revoke an api key by id
+		}
 	}
 
 	return router        // return configured router
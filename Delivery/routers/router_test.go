@@ -12,10 +12,12 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure/mocks"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Usecases/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -55,7 +57,7 @@ func (suite *RouterTestSuite) TestGetTaskByID_Authenticated() {
 
 	// mock task retrieval
 	suite.mockTaskUC.
-		On("GetTaskByID", validTaskID).
+		On("GetTaskByID", mock.Anything, validTaskID).
 		Return(&domain.Task{}, nil)
 
 	// create test request 
@@ -91,16 +93,21 @@ func (suite *RouterTestSuite) TestCreateTask_AdminSuccess() {
     adminToken := "admin.token.here"
 	
 	// mock admin claims
-    claims := jwt.MapClaims{"role": "admin"}
+    claims := jwt.MapClaims{"role": "admin", "userId": "60d5ec49f9a3c7001c5b2b0a"}
 
     // mock ValidateToken to return admin claims
     suite.mockJWT.
         On("ValidateToken", adminToken).
         Return(&jwt.Token{Valid: true, Claims: claims}, nil)
 
-    // mock CreateTask to return a new task and no error
+    // token was not revoked by a password change
+    suite.mockUserUC.
+        On("CheckTokenIssuedAt", mock.Anything, "60d5ec49f9a3c7001c5b2b0a", mock.AnythingOfType("time.Time")).
+        Return(nil)
+
+    // mock CreateTask to expect the admin from the token as owner
     suite.mockTaskUC.
-        On("CreateTask", mock.AnythingOfType("*domain.Task")).
+        On("CreateTask", mock.Anything, mock.AnythingOfType("*domain.Task"), "60d5ec49f9a3c7001c5b2b0a").
         Return(&domain.Task{}, nil)
 
 	// create test task
@@ -151,7 +158,7 @@ func (suite *RouterTestSuite) TestUpdateTask_AdminSuccess() {
 
     // mock UpdateTask to return updated task and no error
     suite.mockTaskUC.
-        On("UpdateTask", taskID, mock.AnythingOfType("*domain.Task")).
+        On("UpdateTask", mock.Anything, taskID, mock.AnythingOfType("*domain.Task"), "admin").
         Return(&domain.Task{}, nil)
 
 	// create test request with request body
@@ -192,7 +199,7 @@ func (suite *RouterTestSuite) TestDeleteTask_AdminSuccess() {
 
     // mock DeleteTask to return no error
     suite.mockTaskUC.
-        On("DeleteTask", taskID).
+        On("DeleteTask", mock.Anything, taskID).
         Return(nil)
 
 	// create test request
@@ -226,7 +233,7 @@ func (suite *RouterTestSuite) TestPromoteToAdmin_Success() {
 
 	// mock PromoteToAdmin to return nil - successful promotion
 	suite.mockUserUC.
-		On("PromoteToAdmin", validUserID).
+		On("PromoteToAdmin", mock.Anything, validUserID).
 		Return(nil)
 
 	// create test request
@@ -273,7 +280,7 @@ func (suite *RouterTestSuite) TestRegister_Success() {
 	
 	// mock Register to return no error
 	suite.mockUserUC.
-		On("Register", mock.AnythingOfType("*domain.User")).
+		On("Register", mock.Anything, mock.AnythingOfType("*domain.User")).
 		Return(nil)
 
 	// create test request with request body
@@ -310,8 +317,11 @@ func (suite *RouterTestSuite) TestLogin_Success() {
 
     // mock Login to return token and no error
     suite.mockUserUC.
-        On("Login", &creds).
+        On("Login", mock.Anything, &creds).
         Return("mock.jwt.token", user, nil)
+    suite.mockUserUC.
+        On("IssueRefreshToken", user.ID.Hex()).
+        Return("mock.refresh.token", nil)
 
 	// create test request with JSON body
 	body, _ := json.Marshal(creds)
@@ -326,6 +336,79 @@ func (suite *RouterTestSuite) TestLogin_Success() {
     suite.mockUserUC.AssertExpectations(suite.T())        // verify mock was called
 }
 
+// tests a logged out token cannot be used again
+func (suite *RouterTestSuite) TestLogout_RevokesToken() {
+
+	token := "user.token.here"
+	claims := jwt.MapClaims{"role": "user", "exp": float64(time.Now().Add(time.Hour).Unix())}
+
+	// mock ValidateToken
+	suite.mockJWT.
+		On("ValidateToken", token).
+		Return(&jwt.Token{Valid: true, Claims: claims}, nil)
+
+	// logout with the token
+	req, _ := http.NewRequest("POST", "/logout", nil)       // create test request
+	req.Header.Set("Authorization", "Bearer "+token)        // set auth header
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)          // status should be 200
+
+	// next request with the same token
+	req, _ = http.NewRequest("GET", "/tasks", nil)          // create test request
+	req.Header.Set("Authorization", "Bearer "+token)        // set auth header
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)                 // status should be 401
+	suite.mockTaskUC.AssertNotCalled(suite.T(), "GetAllTasks", mock.Anything)               // handler should not run
+}
+
+// tests the user id claim written by GenerateToken reaches handlers through the auth middleware
+func (suite *RouterTestSuite) TestAuthenticated_UserIDFromRealToken() {
+
+	// real jwt service, so claim names on both sides are exercised
+	suite.T().Setenv("JWT_SECRET", "router-test-secret")
+	jwtService, err := infrastructure.NewJWTService()
+	require.NoError(suite.T(), err)
+	router := SetupRouter(suite.mockTaskUC, suite.mockUserUC, jwtService)
+
+	userID := primitive.NewObjectID().Hex()
+	token, err := jwtService.GenerateToken(userID, "john", "user")
+	require.NoError(suite.T(), err)
+
+	// token was not revoked by a password change
+	suite.mockUserUC.
+		On("CheckTokenIssuedAt", mock.Anything, userID, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	// handler must receive the id from the token
+	suite.mockTaskUC.
+		On("GetTasksByUser", mock.Anything, userID).
+		Return([]domain.Task{}, nil)
+
+	req, _ := http.NewRequest("GET", "/mytasks", nil)       // create test request
+	req.Header.Set("Authorization", "Bearer "+token)        // set auth header
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)          // status should be 200
+	suite.mockTaskUC.AssertExpectations(suite.T())          // user id available downstream
+	suite.mockUserUC.AssertExpectations(suite.T())          // same id used for the revocation check
+}
+
+// tests browser preflights are answered before authentication
+func (suite *RouterTestSuite) TestPreflight_NoAuth() {
+
+	req, _ := http.NewRequest("OPTIONS", "/tasks", nil)                // create test request
+	req.Header.Set("Origin", "https://app.example.com")                // cross origin caller
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)                           // status should be 204
+	assert.Equal(suite.T(), "*", w.Header().Get("Access-Control-Allow-Origin"))     // default allows any origin
+	suite.mockJWT.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything)        // no token needed
+}
+
 // suite entry point for running the tests
 func TestRouterTestSuite(t *testing.T) {
 	suite.Run(t, new(RouterTestSuite))         // run the test suite
@@ -4,6 +4,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
 	"github.com/dgrijalva/jwt-go"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,19 +14,63 @@ import (
 
 // task item
 type Task struct {
-	ID              primitive.ObjectID         // unique identifier of task 
-	Title           string                     // title of task
-	Description     string                     // description of task
-	DueDate         time.Time                  // due date of task 
-	Status          string                     // status of task
+	ID              primitive.ObjectID    `json:"id" bson:"_id"`                       // unique identifier of task 
+	Title           string                `json:"title" bson:"title"`                  // title of task
+	Description     string                `json:"description,omitempty" bson:"description"`      // description of task, left out of lists for completed tasks when configured
+	DueDate         time.Time             `json:"due_date" bson:"due_date"`            // due date of task 
+	DueDateTZ       string                `json:"due_date_tz,omitempty" bson:"due_date_tz,omitempty"`      // IANA zone the due date was entered in, for display (empty = none)
+	Status          string                `json:"status" bson:"status"`                // status of task
+	Priority        string                `json:"priority" bson:"priority"`            // priority of task - low/medium/high
+	CreatedAt       time.Time             `json:"created_at" bson:"created_at"`        // creation time of task
+	UpdatedAt       time.Time             `json:"updated_at" bson:"updated_at"`        // last modification time of task
+	OwnerID         primitive.ObjectID    `json:"owner_id" bson:"owner_id"`            // user who created the task
+	CompletedAt     time.Time             `json:"completed_at" bson:"completed_at"`    // time the task was last marked completed
+	AssignedTo      primitive.ObjectID    `json:"assigned_to" bson:"assigned_to"`      // user the task is assigned to
+	DependsOn       []primitive.ObjectID  `json:"depends_on,omitempty" bson:"depends_on,omitempty"`      // tasks that must be finished before this one starts
+	ProjectID       primitive.ObjectID    `json:"project_id,omitempty" bson:"project_id,omitempty"`      // project of the task owner the task is grouped in (zero = none)
+	StatusHistory   []StatusChange        `json:"status_history,omitempty" bson:"status_history,omitempty"`      // status changes recorded so far, oldest first - only reopening records one yet
+	Deleted         bool                  `json:"-" bson:"deleted"`                    // soft deleted - hidden from every read until restored
+	DeletedAt       time.Time             `json:"-" bson:"deleted_at"`                 // time the task was deleted
+}
+
+// one recorded move of a task from one status to another
+type StatusChange struct {
+	From  string     `json:"from" bson:"from"`        // status before the change
+	To    string     `json:"to" bson:"to"`            // status after the change
+	At    time.Time  `json:"at" bson:"at"`            // time of the change
+}
+
+// entity tag of the task's current version, changes whenever the task is updated
+func (t *Task) ETag() string {
+	return `"` + strconv.FormatInt(t.UpdatedAt.UnixMilli(), 36) + `"`        // millisecond precision, as stored by mongodb
 }
 
 // user item
 type User struct {
-	ID              primitive.ObjectID         // unique identifier for users 
-	Username     	string                     // username 
-	Password     	string                     // password - hashed before storage
-	Role         	string                     // user role - role/user 
+	ID              primitive.ObjectID    `json:"id" bson:"_id"`            // unique identifier for users 
+	Username     	string    `json:"username" bson:"username"`        // username - required
+	Password     	string    `json:"-" bson:"password"`               // password - hashed before storage, never serialized to clients
+	Email           string    `json:"email" bson:"email"`      // email for account recovery - required on register, unique
+	Role         	string    `json:"role" bson:"role"`                // user role - role/user 
+	Preferences     Preferences    `json:"preferences" bson:"preferences"`       // per-user settings
+	TokensValidAfter time.Time     `json:"-" bson:"tokens_valid_after"`      // tokens issued before this are revoked, set on password change
+	Deleted         bool           `json:"-" bson:"deleted"`                // soft deleted - hidden from lookups and unable to log in
+	DeletedAt       time.Time      `json:"-" bson:"deleted_at"`          // time the user was deleted
+}
+
+// user preferences item
+type Preferences struct {
+	Timezone        string    `json:"timezone" bson:"timezone"`                      // IANA zone used for day boundaries, e.g. Africa/Addis_Ababa (empty = server time)
+	DefaultPriority string    `json:"default_priority" bson:"default_priority"`      // priority for new tasks that omit one - low/medium/high
+	PageSize        int       `json:"page_size" bson:"page_size"`                    // preferred number of items per page (0 = server default)
+	Notifications   bool      `json:"notifications" bson:"notifications"`            // opted in to notifications
+}
+
+// register request item
+type RegisterRequest struct {
+	Username  string    `json:"username" binding:"required"`      // username - required
+	Password  string    `json:"password" binding:"required"`      // password - required, hashed before storage
+	Email     string    `json:"email"`                            // email for account recovery - required on register, unique
 }
 
 // credential item
@@ -34,6 +79,112 @@ type Credentials struct {
     Password 	 string 	   `binding:"required"`      // login password - required
 }
 
+// workload of one assignee
+type AssigneeTasks struct {
+	AssigneeID  primitive.ObjectID  `json:"assignee_id" bson:"_id"`      // user the tasks are assigned to
+	Count       int64               `json:"count" bson:"count"`          // number of tasks assigned to the user
+	Tasks       []Task              `json:"tasks,omitempty" bson:"tasks,omitempty"`      // the assigned tasks, newest first (only when requested)
+}
+
+// owner stats request item
+type OwnerStatsRequest struct {
+	OwnerIDs  []string    `json:"owner_ids" binding:"required,min=1"`      // ids of the owners to count - required
+}
+
+// assign request item
+type AssignRequest struct {
+	UserID  string    `json:"user_id" binding:"required"`      // id of the assignee - required
+}
+
+// move request item
+type MoveTaskRequest struct {
+	ProjectID  *string    `json:"project_id"`      // id of the target project - null or missing takes the task out of its project
+}
+
+// bulk complete request item
+type CompleteTasksRequest struct {
+	IDs  []string    `json:"ids" binding:"required,min=1"`      // ids of the tasks to complete - required
+}
+
+// reassign request item
+type ReassignRequest struct {
+	NewOwnerID  string    `json:"new_owner_id" binding:"required"`      // id of the user taking over the tasks - required
+}
+
+// refresh request item
+type RefreshRequest struct {
+	RefreshToken  string    `json:"refresh_token" binding:"required"`      // refresh token from login - required
+}
+
+// password rules applied on register and password change
+type PasswordPolicy struct {
+	MinLength        int         `json:"min_length"`            // shortest accepted password
+	RequiredClasses  []string    `json:"required_classes"`      // character classes every password needs - upper, lower, digit, special
+}
+
+// password rejected by the policy, matches ErrWeakPassword with errors.Is
+type WeakPasswordError struct {
+	Reason  string            // first unmet rule, used as the message
+	Policy  PasswordPolicy    // policy the password was checked against
+}
+
+func (e *WeakPasswordError) Error() string {
+	return e.Reason
+}
+
+func (e *WeakPasswordError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// api key item for service clients, only a hash of the secret is stored
+type APIKey struct {
+	ID          primitive.ObjectID    `json:"id" bson:"_id"`                     // unique identifier, also part of the raw key
+	Hash        string                `json:"-" bson:"hash"`                     // hashed secret, never returned
+	Prefix      string                `json:"prefix" bson:"prefix"`              // first characters of the secret, to tell keys apart
+	Role        string                `json:"role" bson:"role"`                  // role requests made with the key get - admin/user
+	OwnerID     primitive.ObjectID    `json:"owner_id" bson:"owner_id"`          // admin who minted the key, acts as the request's user
+	CreatedAt   time.Time             `json:"created_at" bson:"created_at"`      // creation time of key
+	ExpiresAt   time.Time             `json:"expires_at" bson:"expires_at"`      // key stops working after this (zero = never)
+	RevokedAt   time.Time             `json:"revoked_at" bson:"revoked_at"`      // time an admin revoked the key (zero = active)
+}
+
+// api key request item
+type APIKeyRequest struct {
+	Role        string    `json:"role" binding:"required,oneof=admin user"`      // role of the new key - required
+	ExpiresIn   string    `json:"expires_in"`                                    // lifetime as a Go duration, e.g. 720h (empty = never expires)
+}
+
+// retention rule, tasks in the status not changed for longer than the duration are deleted for good
+type RetentionRule struct {
+	Status      string                // status the rule applies to, e.g. archived
+	After       time.Duration         // how long after its last change a task in the status is deleted
+}
+
+// project item, groups tasks of one owner
+type Project struct {
+	ID          primitive.ObjectID    `json:"id" bson:"_id"`                     // unique identifier of project
+	Name        string                `json:"name" bson:"name"`                  // name of project
+	OwnerID     primitive.ObjectID    `json:"owner_id" bson:"owner_id"`          // user who created the project, only their tasks may join it
+	CreatedAt   time.Time             `json:"created_at" bson:"created_at"`      // creation time of project
+}
+
+// task counts of one project
+type ProjectStats struct {
+	ByStatus    map[string]int64      `json:"by_status"`                         // status -> number of tasks
+	ByPriority  map[string]int64      `json:"by_priority"`                       // priority -> number of tasks
+}
+
+// project request item
+type ProjectRequest struct {
+	Name        string    `json:"name" binding:"required,max=100"`      // name of the project - required
+}
+
+// password change item
+type PasswordChange struct {
+	OldPassword  string    `json:"old_password" binding:"required"`      // current password - required
+	NewPassword  string    `json:"new_password" binding:"required"`      // replacement password - required
+}
+
 // claim item
 type Claims struct {
 	ID           primitive.ObjectID         // id for claim
@@ -43,42 +194,138 @@ type Claims struct {
 
 // task repository interface 
 type TaskRepository interface {
-	CreateTask(task *Task) (*Task, error)                     // create new task with validation
-	DeleteTask(taskID string) error                 		  // delete existing task or return error if not found
-	GetAllTasks() ([]Task, error)         					  // get all tasks in the system
-	GetTaskByID(taskID string) (*Task, error) 				  // get specific task by id or return error if not found
-	UpdateTask(taskID string, task *Task) (*Task, error)      // update existing task or return error if not found
+	CreateTask(ctx context.Context, task *Task) (*Task, error)                     // create new task with validation
+	DeleteTask(ctx context.Context, taskID string) error                 		  // soft delete existing task or return error if not found
+	RestoreTask(ctx context.Context, taskID string) error                          // bring back a soft deleted task or return error if not found
+	GetAllTasks(ctx context.Context) ([]Task, error)         					  // get all tasks in the system
+	GetTasksSorted(ctx context.Context, field string, ascending bool) ([]Task, error)    // get all tasks sorted by an allowed field
+	SearchTasks(ctx context.Context, keyword string) ([]Task, error)               // get tasks whose title or description contains the keyword, ignoring case, newest first
+	GetTasksByDueDateRange(ctx context.Context, from, to time.Time) ([]Task, error)       // get tasks due within the window, soonest first (zero bound = open ended)
+	GetRecentlyUpdated(ctx context.Context, ownerID string, limit int64) ([]Task, error)       // get most recently updated tasks, newest first (empty owner = all)
+	GetUpcomingTasks(ctx context.Context, ownerID string, from, to time.Time) ([]Task, error)  // get not completed tasks due within the window (empty owner = all)
+	GetOverdueTasks(ctx context.Context, ownerID string, now time.Time) ([]Task, error)        // get not completed tasks due before now, most overdue first (empty owner = all)
+	GetTasksByOwner(ctx context.Context, ownerID string, page, pageSize int64) ([]Task, int64, error)      // get one page of a user's tasks, newest first, and their total (pageSize 0 = all)
+	GetTasksByUser(ctx context.Context, userID string) ([]Task, error)             // get tasks assigned to a user, newest first
+	CountByOwnerStatus(ctx context.Context, ownerIDs []string) (map[string]map[string]int64, error)  // count tasks per owner and status (owner id -> status -> count)
+	CountByStatus(ctx context.Context) (map[string]int64, error)                 // count tasks per status (status -> count)
+	GroupByAssignee(ctx context.Context, includeTasks bool) ([]AssigneeTasks, error)      // count assigned tasks per assignee, busiest first, optionally with the tasks
+	GetTaskByID(ctx context.Context, taskID string) (*Task, error) 				  // get specific task by id or return error if not found
+	GetTasksByIDs(ctx context.Context, ids []primitive.ObjectID) ([]Task, error)   // get the tasks among ids that exist, in no particular order
+	UpdateTask(ctx context.Context, taskID string, task *Task) (*Task, error)      // update existing task or return error if not found
+	ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) (int64, error)   // archive tasks completed before the cutoff and return how many changed
+	DeleteStatusBefore(ctx context.Context, status string, cutoff time.Time) (int64, error)   // permanently delete tasks in the status last changed before the cutoff and return how many
+	ReassignOwner(ctx context.Context, fromOwnerID, toOwnerID string) (int64, error)      // move every task of one owner to another and return how many changed
+	GetTasksByProject(ctx context.Context, projectID string) ([]Task, error)       // get the tasks of a project, newest first
+	DetachProject(ctx context.Context, projectID string) (int64, error)            // take every task out of a project and return how many changed
+	DeleteTasksByProject(ctx context.Context, projectID string) (int64, error)     // soft delete every active task of a project, taking it out of the project, and return how many changed
+	CountByProject(ctx context.Context, projectID string) (*ProjectStats, error)   // count the tasks of a project per status and per priority
+	SetTaskProject(ctx context.Context, taskID string, projectID primitive.ObjectID) (*Task, error)    // put a task in a project (zero = none) or return error if not found
+	ReopenTask(ctx context.Context, taskID string) (*Task, error)                  // move a completed task back to in_progress and clear its completion time, or return error if not found or not completed
+	CompleteTasks(ctx context.Context, taskIDs, fromStatuses []string) (int64, error)     // complete the listed tasks currently in one of the statuses and return how many changed
 }
 
 // user repository interface
 type UserRepository interface {    
-	CreateUser(user *User) error                              // create new user with validation
-	GetByUsername(username string) (*User, error)             // get specific user by username or return error if not found
-	GetUserById(id primitive.ObjectID) (*User, error)         // get specific user by id or return error if not found
-	GetUserCount() (int64, error)                             // get total user count or return error 
-	UpdateRole(id primitive.ObjectID, role string) error      // update user's role to admin or return error if not found                            
+	CreateUser(ctx context.Context, user *User) error                              // create new user with validation
+	GetByUsername(ctx context.Context, username string) (*User, error)             // get specific user by username or return error if not found
+	GetByEmail(ctx context.Context, email string) (*User, error)                   // get specific user by email or return error if not found
+	GetUserById(ctx context.Context, id primitive.ObjectID) (*User, error)         // get specific user by id or return error if not found
+	GetUserCount(ctx context.Context) (int64, error)                             // get total user count or return error 
+	GetAdminCount(ctx context.Context) (int64, error)                            // get number of admins or return error
+	GetAllUsers(ctx context.Context, skip, limit int64) ([]User, error)            // get one page of users in the system without their passwords
+	UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error      // update user's role to admin or return error if not found                            
+	UpdatePreferences(ctx context.Context, id primitive.ObjectID, prefs Preferences) error     // replace user's preferences or return error if not found
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, hashed string) error           // replace user's hashed password or return error if not found
+	SoftDeleteUser(ctx context.Context, id primitive.ObjectID) error               // mark active user as deleted, keeping the record for task references, or return error if not found
+}
+
+// api key repository interface
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error                           // store new api key
+	GetAPIKeyByID(ctx context.Context, id primitive.ObjectID) (*APIKey, error)     // get specific api key by id or return error if not found
+	GetAPIKeysByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]APIKey, error)      // get every key minted by an admin, newest first
+	RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error   // mark a key revoked or return error if not found
+}
+
+// project repository interface
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *Project) error                     // store new project
+	GetProjectByID(ctx context.Context, id primitive.ObjectID) (*Project, error)   // get specific project by id or return error if not found
+	GetProjectsByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]Project, error)    // get the projects of an owner by name (zero owner = all)
+	RenameProject(ctx context.Context, id primitive.ObjectID, name string) (*Project, error)  // change a project's name or return error if not found
+	DeleteProject(ctx context.Context, id primitive.ObjectID) error                // remove a project or return error if not found
 }
 
 // task usecase interface
 type TaskUseCase interface {
-	CreateTask(task *Task) (*Task, error)                     // create new task with validation
-	DeleteTask(taskID string) error                 		  // delete existing task or return error if not found
-	GetAllTasks() ([]Task, error)         					  // get all tasks in the system
-	GetTaskByID(taskID string) (*Task, error) 				  // get specific task by id or return error if not found
-	UpdateTask(taskID string, task *Task) (*Task, error)      // update existing task or return error if not found
+	CreateTask(ctx context.Context, task *Task, ownerID string) (*Task, error)     // create new task owned by the user, applying their preferences
+	DeleteTask(ctx context.Context, taskID string) error                 		  // soft delete existing task or return error if not found
+	RestoreTask(ctx context.Context, taskID string) error                          // bring back a soft deleted task or return error if not found
+	GetAllTasks(ctx context.Context) ([]Task, error)         					  // get all tasks in the system
+	GetTasksSorted(ctx context.Context, field string, ascending bool) ([]Task, error)    // get all tasks sorted by an allowed field
+	SearchTasks(ctx context.Context, keyword string) ([]Task, error)               // get tasks matching a keyword in title or description or return error if the keyword is invalid
+	GetTasksByDueDateRange(ctx context.Context, from, to time.Time) ([]Task, error)       // get tasks due within the window or return error if it is inverted (zero bound = open ended)
+	GetRecentlyUpdated(ctx context.Context, ownerID string, limit int64) ([]Task, error)      // get most recently updated tasks, newest first (empty owner = all)
+	GetUpcomingTasks(ctx context.Context, ownerID string, days int) ([]Task, error)           // get not completed tasks due within the next days (empty owner = all)
+	GetOverdueTasks(ctx context.Context, ownerID string) ([]Task, error)                      // get not completed tasks already past their due date (empty owner = all)
+	GetTasksByOwner(ctx context.Context, ownerID string, page, pageSize int) ([]Task, int64, error)  // get one page of a user's tasks and their total or return error if id is invalid
+	GetTasksByUser(ctx context.Context, userID string) ([]Task, error)             // get tasks assigned to a user or return error if id is invalid
+	GetStatsByOwner(ctx context.Context, ownerIDs []string) (map[string]map[string]int64, error)  // count tasks per owner and status, every requested owner included
+	TaskStats(ctx context.Context) (map[string]int64, error)                     // count tasks per status, every status included
+	GetTasksGroupedByAssignee(ctx context.Context, includeTasks bool) ([]AssigneeTasks, error)   // count assigned tasks per assignee, optionally with the tasks
+	GetTaskByID(ctx context.Context, taskID string) (*Task, error) 				  // get specific task by id or return error if not found
+	GetDependencies(ctx context.Context, taskID string) ([]Task, error)            // get the tasks a task depends on or return error if not found
+	UpdateTask(ctx context.Context, taskID string, task *Task, actorRole string) (*Task, error)      // update existing task as the acting role or return error if not found or not allowed
+	AssignTask(ctx context.Context, taskID, userID, actorID, actorRole string) error      // assign task to an existing user as the acting user, or return error if not allowed or not found
+	ReassignTasks(ctx context.Context, fromUserID, toUserID string) (int64, error)        // move every task of a user to an existing user and return how many moved
+	UpdateTaskIfMatch(ctx context.Context, taskID string, task *Task, etag, actorRole string) (*Task, error)     // update task as the acting role only if its current ETag matches
+	GetTasksByProject(ctx context.Context, projectID, ownerID string) ([]Task, error)       // get the tasks of a project of the owner (empty = any) or return error if not found
+	MoveTask(ctx context.Context, taskID, projectID, ownerID string) (*Task, error)        // move a task of the user into one of their projects (empty project = none, empty owner = any task)
+	ReopenTask(ctx context.Context, taskID, actorID, actorRole string) (*Task, error)      // move a completed task back to in_progress as its owner or an admin
+	CompleteTasks(ctx context.Context, taskIDs []string) (int64, error)                    // complete every listed task allowed to become completed and return how many changed
 }
 
 // user usecase interface
 type UserUseCase interface {
-	Register(user *User) error                                 // register new user with validation
-	Login(credentials *Credentials) (string, *User, error)     // authenticate user and return token, user or error
-	PromoteToAdmin(userID string) error                        // promote user to admin role or return error if not found
+	Register(ctx context.Context, user *User) error                                 // register new user with validation
+	Login(ctx context.Context, credentials *Credentials) (string, *User, error)     // authenticate user and return token, user or error
+	PromoteToAdmin(ctx context.Context, userID string) error                        // promote user to admin role or return error if not found
+	DemoteFromAdmin(ctx context.Context, userID string) error                       // demote admin to user role unless they are the last admin
+	DeleteUser(ctx context.Context, userID string) error                            // delete existing user or return error if not found
+	ListUsers(ctx context.Context, page, pageSize int) ([]User, error)              // get one page of users without their passwords
+	GetPreferences(ctx context.Context, userID string) (*Preferences, error)        // get user's preferences or return error if not found
+	UpdatePreferences(ctx context.Context, userID string, prefs *Preferences) error // validate and save user's preferences or return error if not found
+	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error      // verify old password and store the new one
+	ValidatePassword(password string) error                   // check a password against the policy, naming the first unmet rule
+	IssueRefreshToken(userID string) (string, error)           // create a refresh token for a logged in user
+	Refresh(ctx context.Context, refreshToken string) (string, error)               // exchange a refresh token for a new access token
+	CheckTokenIssuedAt(ctx context.Context, userID string, issuedAt time.Time) error       // reject tokens issued before the user's last password change
+}
+
+// api key usecase interface
+type APIKeyUseCase interface {
+	CreateAPIKey(ctx context.Context, ownerID, role string, ttl time.Duration) (string, *APIKey, error)     // mint a key and return its raw value, shown only once
+	Authenticate(ctx context.Context, rawKey string) (*APIKey, error)               // return the key a raw value belongs to or ErrInvalidAPIKey
+	ListAPIKeys(ctx context.Context, ownerID string) ([]APIKey, error)              // list the keys an admin minted, secrets are never included
+	RevokeAPIKey(ctx context.Context, keyID string) error                           // revoke a key so it stops working on the next request
+}
+
+// project usecase interface, an empty owner id acts as an admin who may see and change every project
+type ProjectUseCase interface {
+	CreateProject(ctx context.Context, name, ownerID string) (*Project, error)     // create a project owned by the user
+	ListProjects(ctx context.Context, ownerID string) ([]Project, error)           // list the user's projects (empty owner = all)
+	GetProject(ctx context.Context, projectID, ownerID string) (*Project, error)   // get a project of the user or return error if not found
+	RenameProject(ctx context.Context, projectID, ownerID, name string) (*Project, error)     // rename a project of the user or return error if not found
+	DeleteProject(ctx context.Context, projectID, ownerID string, cascade bool) (int64, error)    // delete a project, detaching its tasks or deleting them too, and return how many tasks changed
+	ProjectStats(ctx context.Context, projectID, ownerID string) (*ProjectStats, error)      // count the tasks of a project of the user per status and priority
 }
 
 // jwt service interface
 type JWTService interface {
 	GenerateToken(userID, username, role string) (string, error)       	// generate token or return error
-	ValidateToken(tokenStr string) (*jwt.Token, error)                 	// validate token or return error
+	ValidateToken(tokenStr string) (*jwt.Token, error)                 	// validate access token or return error
+	GenerateRefreshToken(userID string) (string, error)                 	// generate long lived refresh token or return error
+	ValidateRefreshToken(tokenStr string) (string, time.Time, error)    	// validate refresh token and return its user id and issue time
 }
 
 // password service interface
@@ -87,6 +334,13 @@ type PasswordService interface {
 	CheckPassword(hashed, plain string) bool            	   // check password and return bool (true/false)
 }
 
+// attempt store interface, counts recent attempts per key within a sliding window
+type AttemptStore interface {
+	Record(key string)            // record an attempt for key at the current time
+	Count(key string) int         // number of attempts for key still inside the window
+	Reset(key string)             // forget every attempt for key
+}
+
 // single result interface 
 type SingleResult interface {
 	Decode(v interface{}) error           // decode single result into provided interface
@@ -100,6 +354,9 @@ type MongoCollection interface {
 	FindOneAndUpdate(context.Context, interface{}, interface{}, ...*options.FindOneAndUpdateOptions) SingleResult       // find one document and update it
 	DeleteOne(context.Context, interface{}, ...*options.DeleteOptions) (*mongo.DeleteResult, error)                     // delete one document from collection
 	CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error)                               // count documents in collection
+	UpdateMany(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)       // update all documents matching filter
+	DeleteMany(context.Context, interface{}, ...*options.DeleteOptions) (*mongo.DeleteResult, error)                    // delete all documents matching filter
+	Aggregate(context.Context, interface{}, ...*options.AggregateOptions) (*mongo.Cursor, error)                        // run an aggregation pipeline
 }
 
 // custom errors
@@ -112,5 +369,44 @@ var (
 	ErrInvalidCredentials    = errors.New("invalid credentials")        	     // custom invalid credentials error
 	ErrUnauthorized          = errors.New("unauthorized access")         		 // custom unauthorized access error
 	ErrInvalidDueDate        = errors.New("due date must be in the future")      // custom invalid due date error
+	ErrDatabaseBusy          = errors.New("database is busy, try again later")   // custom database saturated error
+	ErrInvalidSortField      = errors.New("invalid sort field")                  // custom invalid sort field error
+	ErrInvalidLimit          = errors.New("limit must be a positive number")     // custom invalid limit error
+	ErrInvalidDays           = errors.New("days must be between 1 and 90")       // custom invalid days window error
+	ErrInvalidPriority       = errors.New("invalid task priority")               // custom invalid priority error
+	ErrInvalidTimezone       = errors.New("invalid timezone")                    // custom invalid timezone error
+	ErrInvalidPageSize       = errors.New("page size must be between 1 and 100") // custom invalid page size error
+	ErrLastAdmin             = errors.New("cannot demote the last admin")        // custom last admin error
+	ErrDescriptionTooLong    = errors.New("task description is too long")        // custom description size error
+	ErrInvalidPage           = errors.New("page must be a positive number")      // custom invalid page error
+	ErrTooManyOwners         = errors.New("too many owner ids")                  // custom owner batch size error
+	ErrWeakPassword          = errors.New("password does not meet the policy")   // custom weak password error
+	ErrDueDateNotWorkday     = errors.New("due date must be a workday")          // custom weekend due date error
+	ErrCreateRateExceeded    = errors.New("task creation rate exceeded")         // custom task creation rate error
+	ErrCannotDeleteSelf      = errors.New("users cannot delete themselves")      // custom self deletion error
+	ErrPreconditionFailed    = errors.New("task was modified since it was read")      // custom stale If-Match error
+	ErrPreconditionRequired  = errors.New("If-Match header is required")      // custom missing If-Match error
+	ErrInvalidEmail          = errors.New("invalid email address")               // custom invalid email error
+	ErrEmailExists           = errors.New("email already in use")                // custom duplicate email error
+	ErrSameOwner             = errors.New("new owner is the current owner")      // custom reassign to self error
+	ErrInvalidAPIKey         = errors.New("invalid API key")                     // custom unknown or expired api key error
+	ErrAPIKeyNotFound        = errors.New("API key not found")                   // custom api key not found error
+	ErrAssignForbidden       = errors.New("not allowed to assign this task to that user")  // custom assignment policy error
+	ErrTaskBlocked           = errors.New("task is blocked by incomplete dependencies")  // custom unfinished dependency error
+	ErrDependencyCycle       = errors.New("task dependencies would form a cycle")        // custom circular dependency error
+	ErrDependencyNotFound    = errors.New("dependency task not found")                   // custom unknown dependency error
+	ErrInvalidDueDateRange   = errors.New("due_from must not be after due_to")  // custom inverted due date window error
+	ErrTaskInProgress        = errors.New("task is in progress with another assignee")  // custom locked assignment error
+	ErrInvalidSearchQuery    = errors.New("search query must be between 1 and 100 characters")  // custom invalid search keyword error
+	ErrInvalidStatusTransition  = errors.New("task cannot move from its current status to the requested one")  // custom disallowed status change error
+	ErrTooManyDependencies   = errors.New("task has too many dependencies")      // custom dependency count limit error
+	ErrDependencyTooDeep     = errors.New("task dependency chain is too deep")   // custom dependency depth limit error
+	ErrTaskTooLarge          = errors.New("task exceeds the maximum stored size")  // custom oversized task document error
+	ErrProjectNotFound       = errors.New("project not found")                   // custom project not found error
+	ErrInvalidProjectID      = errors.New("invalid project ID")                  // custom invalid project id error
+	ErrProjectOwnerMismatch  = errors.New("project belongs to another owner than the task")  // custom foreign project error
+	ErrTaskCompletedLocked   = errors.New("completed tasks can only be changed by admins")   // custom locked completed task error
+	ErrTaskNotCompleted      = errors.New("only completed tasks can be reopened")  // custom reopen of unfinished task error
+	ErrTooManyTasks          = errors.New("too many task ids")                   // custom task batch size error
 )
 
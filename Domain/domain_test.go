@@ -0,0 +1,74 @@
+package domain
+
+// imports
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// test suite for the domain types
+type DomainTestSuite struct {
+	suite.Suite
+}
+
+// populated user with a password hash
+func testUser() User {
+	return User{
+		ID:               primitive.NewObjectID(),
+		Username:         "john",
+		Password:         "$2a$10$hashedpassword",
+		Email:            "john@example.com",
+		Role:             "admin",
+		Preferences:      Preferences{Timezone: "Africa/Addis_Ababa"},
+		TokensValidAfter: time.Now(),
+		Deleted:          true,
+		DeletedAt:        time.Now(),
+	}
+}
+
+// tests a user serialized to clients never carries the password or internal bookkeeping
+func (suite *DomainTestSuite) TestUser_JSONOmitsPassword() {
+
+	user := testUser()
+	data, err := json.Marshal(user)
+	assert.NoError(suite.T(), err)                                         // user should marshal
+	assert.NotContains(suite.T(), string(data), user.Password)             // hash not serialized
+	assert.NotContains(suite.T(), string(data), "password")                // nor an empty password field
+
+	var fields map[string]interface{}
+	assert.NoError(suite.T(), json.Unmarshal(data, &fields))
+	assert.Equal(suite.T(), user.ID.Hex(), fields["id"])                   // lowercase json names
+	assert.Equal(suite.T(), "john", fields["username"])
+	assert.Equal(suite.T(), "admin", fields["role"])
+	for _, internal := range []string{"tokens_valid_after", "deleted", "deleted_at"} {
+		assert.NotContains(suite.T(), fields, internal)                    // bookkeeping fields stay server side
+	}
+}
+
+// tests the password is still stored with the user
+func (suite *DomainTestSuite) TestUser_BSONKeepsPassword() {
+
+	user := testUser()
+	data, err := bson.Marshal(user)
+	assert.NoError(suite.T(), err)                                         // user should marshal
+
+	var doc bson.M
+	assert.NoError(suite.T(), bson.Unmarshal(data, &doc))
+	assert.Equal(suite.T(), user.ID, doc["_id"])                           // id is the document id the queries use
+	assert.Equal(suite.T(), user.Password, doc["password"])                // hash persisted
+	assert.Equal(suite.T(), "john", doc["username"])
+	assert.Equal(suite.T(), "admin", doc["role"])
+	assert.Equal(suite.T(), true, doc["deleted"])                          // bookkeeping fields persisted
+	assert.Contains(suite.T(), doc, "tokens_valid_after")
+	assert.Contains(suite.T(), doc, "deleted_at")
+}
+
+// runs the test suite for the domain types
+func TestDomainTestSuite(t *testing.T) {
+	suite.Run(t, new(DomainTestSuite))
+}
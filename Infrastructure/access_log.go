@@ -0,0 +1,73 @@
+package infrastructure
+
+// imports
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// settings of the access log
+type AccessLogConfig struct {
+	SampleRate  int          // log 1 in SampleRate successful requests, 4xx and 5xx are always logged (0 or 1 = every request)
+	Output      io.Writer    // where the lines are written (nil = stdout)
+}
+
+// one structured access log line
+type accessLogEntry struct {
+	Time        time.Time  `json:"time"`
+	Method      string     `json:"method"`
+	Path        string     `json:"path"`
+	Status      int        `json:"status"`
+	LatencyMS   float64    `json:"latency_ms"`
+	ClientIP    string     `json:"client_ip"`
+	UserID      string     `json:"user_id,omitempty"`
+	SampleRate  int        `json:"sample_rate,omitempty"`      // successes this line stands for, left out on errors
+}
+
+// writes one json line per logged request, successful requests are sampled and errors never are
+func NewAccessLogMiddleware(config AccessLogConfig) gin.HandlerFunc {
+
+	rate := config.SampleRate
+	if rate < 1 {
+		rate = 1
+	}
+	output := config.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	encoder := json.NewEncoder(output)
+	var mu sync.Mutex                  // keeps concurrent lines from interleaving
+	var successes atomic.Uint64        // successful requests seen, the first of every rate is logged
+
+	return func(c *gin.Context) {
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := accessLogEntry{
+			Time:      start.UTC(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    status,
+			LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			ClientIP:  c.ClientIP(),
+			UserID:    c.GetString("userID"),
+		}
+		if status < 400 {
+			if (successes.Add(1)-1)%uint64(rate) != 0 {
+				return
+			}
+			entry.SampleRate = rate
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		encoder.Encode(entry)
+	}
+}
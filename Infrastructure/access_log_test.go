@@ -0,0 +1,99 @@
+package infrastructure
+
+// imports
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for the access log middleware
+type AccessLogTestSuite struct {
+	suite.Suite
+	output  *bytes.Buffer        // captured log lines
+}
+
+// sets up gin for testing
+func (suite *AccessLogTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.output = &bytes.Buffer{}
+}
+
+// router with one successful and one failing route behind the access log
+func (suite *AccessLogTestSuite) router(sampleRate int) *gin.Engine {
+
+	router := gin.New()
+	router.Use(NewAccessLogMiddleware(AccessLogConfig{SampleRate: sampleRate, Output: suite.output}))
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+	})
+
+	return router
+}
+
+// decodes every captured log line
+func (suite *AccessLogTestSuite) entries() []accessLogEntry {
+
+	var entries []accessLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(suite.output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry accessLogEntry
+		assert.NoError(suite.T(), json.Unmarshal([]byte(line), &entry))       // every line is json
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// tests errors are always logged while only 1 in rate successes is
+func (suite *AccessLogTestSuite) TestSampling() {
+
+	router := suite.router(5)
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	}
+
+	counts := map[int]int{}
+	for _, entry := range suite.entries() {
+		counts[entry.Status]++
+		if entry.Status == http.StatusOK {
+			assert.Equal(suite.T(), 5, entry.SampleRate)           // sampled line says how many requests it stands for
+		} else {
+			assert.Zero(suite.T(), entry.SampleRate)               // errors are not sampled
+		}
+	}
+	assert.Equal(suite.T(), 2, counts[http.StatusOK])                       // 10 successes at 1 in 5
+	assert.Equal(suite.T(), 10, counts[http.StatusInternalServerError])     // every error
+}
+
+// tests every request is logged without sampling, with its request details
+func (suite *AccessLogTestSuite) TestNoSampling() {
+
+	router := suite.router(0)
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	}
+
+	entries := suite.entries()
+	assert.Len(suite.T(), entries, 3)                                  // one line per request
+	assert.Equal(suite.T(), http.MethodGet, entries[0].Method)
+	assert.Equal(suite.T(), "/ok", entries[0].Path)
+	assert.False(suite.T(), entries[0].Time.IsZero())                  // request time recorded
+}
+
+// runs the test suite for the access log middleware
+func TestAccessLogTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessLogTestSuite))
+}
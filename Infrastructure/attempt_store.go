@@ -0,0 +1,92 @@
+package infrastructure
+
+// imports
+import (
+	"sync"
+	"time"
+)
+
+// in-memory attempt store with a sliding window - attempts older than the window are dropped
+type MemoryAttemptStore struct {
+	mu        sync.Mutex
+	window    time.Duration                 // how long an attempt counts
+	attempts  map[string][]time.Time        // key -> attempt times, oldest first
+	now       func() time.Time              // clock used for the window
+}
+
+// creates an empty attempt store counting attempts of the last window, now may be nil for the real clock
+func NewMemoryAttemptStore(window time.Duration, now func() time.Time) *MemoryAttemptStore {
+	if now == nil {
+		now = time.Now
+	}
+	return &MemoryAttemptStore{window: window, attempts: make(map[string][]time.Time), now: now}
+}
+
+// records an attempt for key
+func (store *MemoryAttemptStore) Record(key string) {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.prune()
+	store.attempts[key] = append(store.attempts[key], store.now())
+}
+
+// number of attempts for key inside the window
+func (store *MemoryAttemptStore) Count(key string) int {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	recent := store.recent(key)
+	if len(recent) == 0 {
+		delete(store.attempts, key)
+		return 0
+	}
+	store.attempts[key] = recent
+
+	return len(recent)
+}
+
+// forgets every attempt for key
+func (store *MemoryAttemptStore) Reset(key string) {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.attempts, key)
+}
+
+// number of keys currently held
+func (store *MemoryAttemptStore) Len() int {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return len(store.attempts)
+}
+
+// attempts of key inside the window, callers hold the lock
+func (store *MemoryAttemptStore) recent(key string) []time.Time {
+
+	cutoff := store.now().Add(-store.window)
+	times := store.attempts[key]
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+
+	return times
+}
+
+// drops keys without attempts inside the window, callers hold the lock
+func (store *MemoryAttemptStore) prune() {
+
+	for key := range store.attempts {
+		recent := store.recent(key)
+		if len(recent) == 0 {
+			delete(store.attempts, key)
+		} else {
+			store.attempts[key] = recent
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package infrastructure
+
+// imports
+import (
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for MemoryAttemptStore
+type MemoryAttemptStoreTestSuite struct {
+	suite.Suite
+	now    time.Time                  // fixed clock for the tests
+	store  *MemoryAttemptStore        // store being tested
+}
+
+// initializes a store with a ten minute window and a controllable clock before each test
+func (suite *MemoryAttemptStoreTestSuite) SetupTest() {
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.store = NewMemoryAttemptStore(10*time.Minute, func() time.Time { return suite.now })
+}
+
+// tests attempts are counted per key and slide out of the window
+func (suite *MemoryAttemptStoreTestSuite) TestSlidingWindow() {
+
+	suite.store.Record("alice")
+	suite.now = suite.now.Add(6 * time.Minute)
+	suite.store.Record("alice")
+	suite.store.Record("bob")
+	assert.Equal(suite.T(), 2, suite.store.Count("alice"))       // both attempts inside the window
+	assert.Equal(suite.T(), 1, suite.store.Count("bob"))         // keys counted separately
+
+	suite.now = suite.now.Add(5 * time.Minute)
+	assert.Equal(suite.T(), 1, suite.store.Count("alice"))       // first attempt slid out
+
+	suite.now = suite.now.Add(10 * time.Minute)
+	assert.Equal(suite.T(), 0, suite.store.Count("alice"))       // window passed
+}
+
+// tests reset forgets a key and stale keys are pruned
+func (suite *MemoryAttemptStoreTestSuite) TestResetAndPrune() {
+
+	suite.store.Record("alice")
+	suite.store.Record("bob")
+	suite.store.Reset("alice")
+	assert.Equal(suite.T(), 0, suite.store.Count("alice"))       // reset key
+	assert.Equal(suite.T(), 1, suite.store.Count("bob"))         // other key kept
+
+	suite.now = suite.now.Add(time.Hour)
+	suite.store.Record("carol")
+	assert.Equal(suite.T(), 1, suite.store.Len())                // stale bob dropped
+}
+
+// runs the test suite for MemoryAttemptStore
+func TestMemoryAttemptStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryAttemptStoreTestSuite))
+}
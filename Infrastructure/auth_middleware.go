@@ -2,18 +2,45 @@ package infrastructure
 
 // imports
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 )
 
+// decides whether a token issued at the given time is still valid for the user
+type TokenChecker interface {
+	CheckTokenIssuedAt(ctx context.Context, userID string, issuedAt time.Time) error
+}
+
+// resolves the raw value of an X-API-Key header to its stored key
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error)
+}
+
+// optional checks run after the token signature is valid
+type AuthConfig struct {
+	TokenChecker  TokenChecker        // rejects tokens issued before a password change (nil = skip)
+	Blacklist     TokenBlacklist      // rejects logged out tokens (nil = skip)
+	APIKeys       APIKeyAuthenticator // accepts X-API-Key as an alternative to a token (nil = tokens only)
+}
+
 type AuthMiddleWare struct {
 	jwtService domain.JWTService
+	config     AuthConfig
 }
 
 func NewAuthMiddleware(jwtServ domain.JWTService) *AuthMiddleWare {
-	return &AuthMiddleWare{jwtService: jwtServ}
+	return NewAuthMiddlewareWithConfig(jwtServ, AuthConfig{})
+}
+
+// creates the auth middleware with optional extra token checks
+func NewAuthMiddlewareWithConfig(jwtServ domain.JWTService, config AuthConfig) *AuthMiddleWare {
+	return &AuthMiddleWare{jwtService: jwtServ, config: config}
 }
 
 // auth handler
@@ -21,6 +48,12 @@ func (authmidlw *AuthMiddleWare) Handler() gin.HandlerFunc {
 	
 	return func(c *gin.Context) {
 
+		// service clients may send an api key instead of a token
+		if rawKey := c.GetHeader("X-API-Key"); rawKey != "" && authmidlw.config.APIKeys != nil {
+			authmidlw.authenticateAPIKey(c, rawKey)
+			return
+		}
+
 		tokenStr := c.GetHeader("Authorization")        // get token from authorization header
 		// reject if empty
 		if tokenStr == "" {
@@ -29,6 +62,14 @@ func (authmidlw *AuthMiddleWare) Handler() gin.HandlerFunc {
 			return
 		}
 		
+		tokenStr = stripBearer(tokenStr)        // accept "Bearer <token>" as well as a bare token
+		// reject a scheme without a token
+		if tokenStr == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "malformed authorization header"})
+			c.Abort()
+			return
+		}
+
 		// validate token structure/signature with error handling 
 		token, err := authmidlw.jwtService.ValidateToken(tokenStr)     
 		if err != nil || !token.Valid {
@@ -37,18 +78,150 @@ func (authmidlw *AuthMiddleWare) Handler() gin.HandlerFunc {
 			return
 		}
 
+		// reject tokens revoked by logout
+		if authmidlw.config.Blacklist != nil && authmidlw.config.Blacklist.IsRevoked(tokenStr) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			c.Abort()
+			return
+		}
+		c.Set("token", tokenStr)        // raw token, used by logout
+
 		// if token is valid, extract claims and store in request context
 		claims, ok := token.Claims.(jwt.MapClaims)      
 		if ok {
-			c.Set("userID", claims["sub"])             // user id
+			c.Set("userID", claims["userId"])          // user id - same key GenerateToken writes
 			c.Set("username", claims["username"])      // username 
 			c.Set("role", claims["role"])              // user role (admin/user)
+			if exp, ok := claims["exp"].(float64); ok {
+				c.Set("tokenExpiry", time.Unix(int64(exp), 0))        // how long a revocation must be kept
+			}
+			if iat, ok := claims["iat"].(float64); ok {
+				c.Set("tokenIssuedAt", time.Unix(int64(iat), 0))      // used for the token age
+			}
+
+			// tokens issued before a password change are revoked
+			if !authmidlw.tokenStillValid(c.Request.Context(), claims) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				c.Abort()
+				return
+			}
 		}
 
 		c.Next()       // proceed to next handler
 	}
 }
 
+// authenticates the request with an api key, acting as the admin who minted it with the key's role
+func (authmidlw *AuthMiddleWare) authenticateAPIKey(c *gin.Context, rawKey string) {
+
+	key, err := authmidlw.config.APIKeys.Authenticate(c.Request.Context(), rawKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check API key"})
+		}
+		c.Abort()
+		return
+	}
+	// reject keys an admin revoked, like tokens revoked by logout
+	if !key.RevokedAt.IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key has been revoked"})
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", key.OwnerID.Hex())       // owner of tasks created with the key
+	c.Set("role", key.Role)                  // checked by AdminOnly like a token role
+	c.Set("apiKeyID", key.ID.Hex())          // which key made the request
+
+	c.Next()       // proceed to next handler
+}
+
+// revokes the token of the current request, must run after Handler
+func (authmidlw *AuthMiddleWare) Logout() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		if authmidlw.config.Blacklist == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "logout is not enabled"})
+			return
+		}
+
+		// api keys have no token to revoke, they are revoked by id
+		token := c.GetString("token")
+		if c.GetString("apiKeyID") != "" || token == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "api keys cannot log out, revoke the key at DELETE /apikeys/:id"})
+			return
+		}
+
+		// tokens without exp are kept for the default lifetime
+		exp := time.Now().Add(DefaultJWTExpiry)
+		if value, ok := c.Get("tokenExpiry"); ok {
+			exp = value.(time.Time)
+		}
+		authmidlw.config.Blacklist.Revoke(token, exp)
+
+		c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})       // success response
+	}
+}
+
+// describes the token of the current request, must run after Handler
+func (authmidlw *AuthMiddleWare) Introspect() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		info := gin.H{
+			"user_id":  c.GetString("userID"),
+			"username": c.GetString("username"),
+			"role":     c.GetString("role"),
+		}
+		if exp, ok := c.Get("tokenExpiry"); ok {
+			info["expires_at"] = exp.(time.Time).UTC()
+		}
+		// tokens issued before iat was added have no age
+		if iat, ok := c.Get("tokenIssuedAt"); ok {
+			issuedAt := iat.(time.Time)
+			info["issued_at"] = issuedAt.UTC()
+			info["age_seconds"] = int64(time.Since(issuedAt).Seconds())
+		}
+
+		c.JSON(http.StatusOK, info)
+	}
+}
+
+// asks the token checker about the token's user and issue time, tokens without a user are not checked
+func (authmidlw *AuthMiddleWare) tokenStillValid(ctx context.Context, claims jwt.MapClaims) bool {
+
+	userID, _ := claims["userId"].(string)
+	if authmidlw.config.TokenChecker == nil || userID == "" {
+		return true
+	}
+
+	// tokens without iat count as issued before any password change
+	var issuedAt time.Time
+	if iat, ok := claims["iat"].(float64); ok {
+		issuedAt = time.Unix(int64(iat), 0)
+	}
+
+	return authmidlw.config.TokenChecker.CheckTokenIssuedAt(ctx, userID, issuedAt) == nil
+}
+
+// removes an optional, case-insensitive "Bearer " prefix from an authorization header
+func stripBearer(header string) string {
+
+	const prefix = "bearer "
+	header = strings.TrimSpace(header)
+	if strings.EqualFold(header, strings.TrimSpace(prefix)) {
+		return ""        // scheme only, the server trims the trailing space
+	}
+	if len(header) >= len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+
+	return header
+}
+
 func AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		
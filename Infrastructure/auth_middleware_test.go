@@ -2,16 +2,21 @@ package infrastructure
 
 // imports
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // test suite for AuthMiddleware
@@ -33,7 +38,7 @@ func (suite *AuthMiddlewareTestSuite) TestAuthHandler_ValidToken() {
 	
 	// setup test claims
 	claims := jwt.MapClaims{
-		"sub":      "user123",
+		"userId":   "user123",
 		"username": "testuser",
 		"role":     "admin",
 	}
@@ -79,6 +84,244 @@ func (suite *AuthMiddlewareTestSuite) TestAuthHandler_ValidToken() {
 	suite.Contains(w.Body.String(), "admin")              // check role in response
 }
 
+// tests the AuthHandler exposes the claims of a token generated by the real JWTService
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_RealTokenClaims() {
+
+	// real jwt service with a test secret
+	jwtService := &JWTService{secret: []byte("test-secret")}
+	tokenStr, err := jwtService.GenerateToken("64b7f0c2a1b2c3d4e5f60718", "testuser", "user")
+	require.NoError(suite.T(), err)
+
+	// setup router with auth middleware backed by the real service
+	auth := NewAuthMiddleware(jwtService)
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		username, _ := c.Get("username")
+		role, _ := c.Get("role")
+		c.JSON(http.StatusOK, gin.H{"userID": userID, "username": username, "role": role})
+	})
+
+	// create test request with the generated token
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", tokenStr)
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	// verify the downstream handler saw every claim
+	require.Equal(suite.T(), http.StatusOK, w.Code)                                                  // status should be 200
+	assert.JSONEq(suite.T(), `{"userID":"64b7f0c2a1b2c3d4e5f60718","username":"testuser","role":"user"}`, w.Body.String())
+}
+
+// tests a refresh token cannot open protected routes
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_RejectsRefreshToken() {
+
+	// real jwt service with a test secret
+	jwtService := &JWTService{secret: []byte("test-secret")}
+	refreshToken, err := jwtService.GenerateRefreshToken("64b7f0c2a1b2c3d4e5f60718")
+	require.NoError(suite.T(), err)
+
+	// setup router with auth middleware backed by the real service
+	auth := NewAuthMiddleware(jwtService)
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	// create test request with the refresh token
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+refreshToken)
+	w := httptest.NewRecorder()
+
+	// serve the request using the router
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)       // status should be 401
+}
+
+// tests a token issued before a password change is rejected and a newer one accepted
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_PasswordChange() {
+
+	changedAt := time.Unix(1750000000, 0)
+	checker := new(mock_infrastructure.MockTokenChecker)
+	checker.On("CheckTokenIssuedAt", mock.Anything, "user123", changedAt.Add(-time.Hour)).Return(domain.ErrUnauthorized)
+	checker.On("CheckTokenIssuedAt", mock.Anything, "user123", changedAt.Add(time.Minute)).Return(nil)
+
+	// tokens issued an hour before and a minute after the change
+	oldToken := &jwt.Token{Valid: true, Claims: jwt.MapClaims{"userId": "user123", "role": "user", "iat": float64(changedAt.Add(-time.Hour).Unix())}}
+	newToken := &jwt.Token{Valid: true, Claims: jwt.MapClaims{"userId": "user123", "role": "user", "iat": float64(changedAt.Add(time.Minute).Unix())}}
+	suite.mockJWTService.On("ValidateToken", "old.token").Return(oldToken, nil)
+	suite.mockJWTService.On("ValidateToken", "new.token").Return(newToken, nil)
+
+	// setup router with auth middleware consulting the checker
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{TokenChecker: checker})
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	// pre-change token
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "old.token")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)       // status should be 401
+
+	// post-change token
+	req = httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "new.token")
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                 // status should be 200
+	checker.AssertExpectations(suite.T())                          // both tokens were checked
+}
+
+// tests a logged out token is rejected on the next request
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_Logout() {
+
+	// real jwt service with a test secret
+	jwtService := &JWTService{secret: []byte("test-secret")}
+	tokenStr, err := jwtService.GenerateToken("64b7f0c2a1b2c3d4e5f60718", "testuser", "user")
+	require.NoError(suite.T(), err)
+
+	// setup router with auth middleware and an in-memory blacklist
+	auth := NewAuthMiddlewareWithConfig(jwtService, AuthConfig{Blacklist: NewMemoryBlacklist()})
+	suite.router.Use(auth.Handler())
+	suite.router.POST("/logout", auth.Logout())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	// token works before logout
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenStr)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	require.Equal(suite.T(), http.StatusOK, w.Code)                // status should be 200
+
+	// logout
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenStr)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	require.Equal(suite.T(), http.StatusOK, w.Code)                // status should be 200
+
+	// same token is rejected afterwards
+	req = httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", tokenStr)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)       // status should be 401
+}
+
+// tests an api key request cannot log out and revokes nothing
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_LogoutAPIKey() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Role: "user"}
+	keys := new(mock_infrastructure.MockAPIKeyAuthenticator)
+	keys.On("Authenticate", mock.Anything, "tmk_valid").Return(key, nil)
+
+	// setup router with auth middleware accepting api keys and an in-memory blacklist
+	blacklist := NewMemoryBlacklist()
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{APIKeys: keys, Blacklist: blacklist})
+	suite.router.POST("/logout", auth.Handler(), auth.Logout())
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("X-API-Key", "tmk_valid")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)                 // status should be 400
+	assert.Contains(suite.T(), w.Body.String(), "DELETE /apikeys/:id")     // points to key revocation
+	assert.False(suite.T(), blacklist.IsRevoked(""))                       // empty token not revoked
+}
+
+// tests the introspection handler reports the token age
+func (suite *AuthMiddlewareTestSuite) TestIntrospect() {
+
+	// token issued five minutes ago
+	issuedAt := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	claims := jwt.MapClaims{
+		"userId":   "user123",
+		"username": "testuser",
+		"role":     "user",
+		"iat":      float64(issuedAt.Unix()),
+		"exp":      float64(issuedAt.Add(time.Hour).Unix()),
+	}
+	suite.mockJWTService.On("ValidateToken", "valid.token").Return(&jwt.Token{Valid: true, Claims: claims}, nil)
+
+	// setup router with auth middleware and introspection
+	auth := NewAuthMiddleware(suite.mockJWTService)
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/token", auth.Introspect())
+
+	req := httptest.NewRequest(http.MethodGet, "/token", nil)
+	req.Header.Set("Authorization", "valid.token")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var info struct {
+		UserID     string    `json:"user_id"`
+		IssuedAt   time.Time `json:"issued_at"`
+		AgeSeconds int64     `json:"age_seconds"`
+	}
+	require.Equal(suite.T(), http.StatusOK, w.Code)                                   // status should be 200
+	require.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &info))                 // body should be json
+	assert.Equal(suite.T(), "user123", info.UserID)                                   // user from the token
+	assert.True(suite.T(), info.IssuedAt.Equal(issuedAt))                             // issue time from iat
+	assert.InDelta(suite.T(), 300, info.AgeSeconds, 5)                                // about five minutes old
+}
+
+// tests the AuthHandler accepts bearer prefixed and bare tokens alike
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_BearerPrefix() {
+
+	// mock the ValidateToken method for the bare token only
+	suite.mockJWTService.
+		On("ValidateToken", "abc").
+		Return(&jwt.Token{Valid: true, Claims: jwt.MapClaims{"role": "user"}}, nil)
+
+	// setup router with auth middleware
+	auth := NewAuthMiddleware(suite.mockJWTService)
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	for _, header := range []string{"Bearer abc", "bearer abc", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(w, req)
+		assert.Equal(suite.T(), http.StatusOK, w.Code, header)        // status should be 200
+	}
+	suite.mockJWTService.AssertNumberOfCalls(suite.T(), "ValidateToken", 3)      // every header validated the same token
+}
+
+// tests the AuthHandler rejects a bearer scheme without a token
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_MalformedHeader() {
+
+	// setup router with auth middleware
+	auth := NewAuthMiddleware(suite.mockJWTService)
+	suite.router.Use(auth.Handler())
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	for _, header := range []string{"Bearer", "bearer   ", "Bearer \t"} {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(w, req)
+		assert.Equal(suite.T(), http.StatusUnauthorized, w.Code, header)                       // status should be 401
+		assert.Contains(suite.T(), w.Body.String(), "malformed authorization header", header)   // check response body
+	}
+	suite.mockJWTService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything)      // nothing to validate
+}
+
 // tests the AuthHandler with missing token
 func (suite *AuthMiddlewareTestSuite) TestAuthHandler_MissingToken() {
 	
@@ -211,6 +454,116 @@ func (suite *AuthMiddlewareTestSuite) TestAdminOnly_NoRoleInContext() {
 	assert.Contains(suite.T(), w.Body.String(), "admin access required")      // check response body
 }
 
+// tests a valid api key authenticates with the key's role and owner
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_ValidAPIKey() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Role: "admin"}
+	keys := new(mock_infrastructure.MockAPIKeyAuthenticator)
+	keys.On("Authenticate", mock.Anything, "tmk_valid").Return(key, nil)
+
+	// setup router with auth middleware accepting api keys
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{APIKeys: keys})
+	suite.router.GET("/admin", auth.Handler(), AdminOnly(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"userID": c.GetString("userID"), "apiKeyID": c.GetString("apiKeyID")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("X-API-Key", "tmk_valid")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                                // admin key passes AdminOnly
+	assert.Contains(suite.T(), w.Body.String(), key.OwnerID.Hex())                // acts as the owner
+	assert.Contains(suite.T(), w.Body.String(), key.ID.Hex())                     // key is recorded
+	suite.mockJWTService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything)       // no token involved
+}
+
+// tests an invalid or expired api key is rejected
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_InvalidAPIKey() {
+
+	keys := new(mock_infrastructure.MockAPIKeyAuthenticator)
+	keys.On("Authenticate", mock.Anything, "tmk_expired").Return(nil, domain.ErrInvalidAPIKey)
+
+	// setup router with auth middleware accepting api keys
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{APIKeys: keys})
+	suite.router.GET("/protected", auth.Handler(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("X-API-Key", "tmk_expired")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)               // status should be 401
+	assert.Contains(suite.T(), w.Body.String(), "invalid API key")         // check response body
+}
+
+// tests a user role api key is refused on admin routes
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_UserAPIKeyNotAdmin() {
+
+	keys := new(mock_infrastructure.MockAPIKeyAuthenticator)
+	keys.On("Authenticate", mock.Anything, "tmk_user").Return(&domain.APIKey{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Role: "user"}, nil)
+
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{APIKeys: keys})
+	suite.router.GET("/admin", auth.Handler(), AdminOnly(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("X-API-Key", "tmk_user")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)        // status should be 403
+}
+
+// tests revoking a key takes effect on the next request
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_RevokedAPIKey() {
+
+	// the authenticator returns the stored key, so a revocation shows up on the next lookup
+	key := &domain.APIKey{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Role: "user"}
+	keys := new(mock_infrastructure.MockAPIKeyAuthenticator)
+	keys.On("Authenticate", mock.Anything, "tmk_valid").Return(key, nil)
+
+	auth := NewAuthMiddlewareWithConfig(suite.mockJWTService, AuthConfig{APIKeys: keys})
+	suite.router.GET("/protected", auth.Handler(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("X-API-Key", "tmk_valid")
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(suite.T(), http.StatusOK, request().Code)        // key works before revocation
+
+	key.RevokedAt = time.Now()       // an admin revokes the key
+	w := request()
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)                    // status should be 401
+	assert.Contains(suite.T(), w.Body.String(), "API key has been revoked")     // check response body
+}
+
+// tests the api key header is ignored when api keys are disabled
+func (suite *AuthMiddlewareTestSuite) TestAuthHandler_APIKeyDisabled() {
+
+	auth := NewAuthMiddleware(suite.mockJWTService)
+	suite.router.GET("/protected", auth.Handler(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("X-API-Key", "tmk_valid")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)                           // status should be 401
+	assert.Contains(suite.T(), w.Body.String(), "authorization header required")       // token still needed
+}
+
 // runs the test suite for AuthMiddleware
 func TestAuthMiddlewareTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthMiddlewareTestSuite))     // run the test suite
@@ -0,0 +1,50 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// how long shed clients are told to wait before retrying
+const AuthRetryAfter = time.Second
+
+// bounds concurrent requests on the authentication path, whose bcrypt work is CPU heavy
+type AuthThrottle struct {
+	slots chan struct{}        // semaphore of available auth slots
+}
+
+// creates a throttle allowing at most maxConcurrent auth requests at once
+func NewAuthThrottle(maxConcurrent int) *AuthThrottle {
+	if maxConcurrent <= 0 {
+		return nil        // no limit configured
+	}
+	return &AuthThrottle{slots: make(chan struct{}, maxConcurrent)}
+}
+
+// sheds requests with 503 and Retry-After while every slot is taken, a nil throttle lets everything through
+func (throttle *AuthThrottle) Handler() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		if throttle == nil {
+			c.Next()
+			return
+		}
+
+		// never queue - a stuffing attack would only grow the queue
+		select {
+		case throttle.slots <- struct{}{}:
+		default:
+			c.Header("Retry-After", strconv.Itoa(int(AuthRetryAfter.Seconds())))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "too many authentication requests, try again later"})
+			c.Abort()
+			return
+		}
+		defer func() { <-throttle.slots }()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for AuthThrottle
+type AuthThrottleTestSuite struct {
+	suite.Suite
+	router   *gin.Engine            // gin router for testing
+	entered  chan struct{}          // signals a login is holding a slot
+	release  chan struct{}          // lets held logins finish
+}
+
+// sets up a router with one auth slot and a protected route outside the throttle
+func (suite *AuthThrottleTestSuite) SetupTest() {
+
+	gin.SetMode(gin.TestMode)
+	suite.entered = make(chan struct{}, 1)
+	suite.release = make(chan struct{})
+
+	throttle := NewAuthThrottle(1)
+	suite.router = gin.New()
+	suite.router.POST("/login", throttle.Handler(), func(c *gin.Context) {
+		suite.entered <- struct{}{}
+		<-suite.release        // stands in for a slow bcrypt comparison
+		c.JSON(http.StatusOK, gin.H{"token": "token"})
+	})
+	suite.router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+}
+
+// tests excess auth requests are shed while protected routes still serve
+func (suite *AuthThrottleTestSuite) TestSaturated() {
+
+	// first login takes the only slot
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		done <- w.Code
+	}()
+	<-suite.entered
+
+	// second login is shed
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, w.Code)       // status should be 503
+	assert.Equal(suite.T(), "1", w.Header().Get("Retry-After"))          // retry hint set
+
+	// protected routes are not throttled
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protected", nil))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                       // status should be 200
+
+	// the held login completes and frees its slot
+	close(suite.release)
+	assert.Equal(suite.T(), http.StatusOK, <-done)                       // status should be 200
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	<-suite.entered
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                       // slot available again
+}
+
+// tests a zero bound disables the throttle
+func (suite *AuthThrottleTestSuite) TestUnlimited() {
+	assert.Nil(suite.T(), NewAuthThrottle(0))       // no throttle configured
+
+	router := gin.New()
+	router.POST("/login", NewAuthThrottle(0).Handler(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)       // status should be 200
+}
+
+// runs the test suite for AuthThrottle
+func TestAuthThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthThrottleTestSuite))
+}
@@ -0,0 +1,200 @@
+package infrastructure
+
+// imports
+import (
+	"log"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/spf13/viper"
+)
+
+// application configuration read from .env or environment variables
+type Config struct {
+	DBMaxConcurrentOps   int                  // maximum number of concurrent database operations
+	DBOpQueueTimeout     time.Duration        // how long an operation waits for a free slot (0 = fail fast)
+	DBReadTimeout        time.Duration        // how long a find, point read or count may take
+	DBWriteTimeout       time.Duration        // how long a single document write may take
+	DBAggregateTimeout   time.Duration        // how long an aggregation or a write over many documents may take
+	AuthMaxConcurrent    int                  // concurrent login, register and password change requests (0 = unlimited)
+	PasswordPolicyInErrors  bool              // include the password policy in weak password errors
+	PasswordMinLength    int                  // shortest accepted password
+	PasswordRequiredClasses []string          // character classes every new password needs
+	CORSOrigins          []string             // origins allowed to call the api from a browser ("*" = any)
+	HTTPSRedirect        bool                 // redirect plain http requests to https
+	HSTSMaxAge           time.Duration        // max-age of the HSTS header on https responses (0 = no header)
+	LoginMaxAttempts     int                  // failed logins per client before a lockout (0 = unlimited)
+	LoginWindow          time.Duration        // how long failed logins count, also the lockout length
+	LoginWarnPercent     int                  // warn clients once at most this percent of login attempts is left (0 = never)
+	APIKeysEnabled       bool                 // accept X-API-Key and let admins mint keys
+	TaskUpdatableFields  []string             // task fields clients may change on update
+	TaskStrictDueDate    bool                 // require a future due date on every update containing one
+	TaskEpochDueDates    bool                 // accept due dates sent as unix epoch seconds or milliseconds
+	TaskWorkdayDueDates  bool                 // reject due dates on a saturday or sunday
+	TaskRequireIfMatch   bool                 // reject task updates without an If-Match header
+	TaskSelfAssign       bool                 // let non-admins assign tasks to themselves
+	TaskLockInProgressAssignee bool           // only admins may reassign an in_progress task
+	TaskReopenCompleted        bool           // completed tasks may move back to pending or in_progress
+	TaskLockCompleted          bool           // only admins may reopen or, from internal callers, update completed tasks
+	TaskCreateRateLimit  int                  // tasks a non-admin may create per window (0 = unlimited)
+	TaskCreateRateWindow time.Duration        // window of the task creation limit
+	TaskMaxDescriptionBytes int               // largest task description in bytes
+	TaskMaxDependencies     int               // direct dependencies of one task
+	TaskMaxDependencyDepth  int               // longest dependency chain below a task
+	TaskMaxDocumentFraction float64           // share of the 16MB mongodb document limit one task write may use
+	TaskListOmitCompletedDescriptions bool    // leave descriptions of completed tasks out of list responses
+	SeedAdminUsername    string               // username that becomes admin on register (empty = first user)
+	PasswordChangeGrace  time.Duration        // how long tokens issued before a password change keep working
+	TaskArchiveEnabled   bool                 // periodically archive old completed tasks
+	TaskArchiveAfter     time.Duration        // how long after completion a task is archived
+	TaskArchiveInterval  time.Duration        // how often the auto-archive job runs
+	TaskRetentionRules   []domain.RetentionRule     // statuses whose old tasks are deleted for good (empty = keep everything)
+	TaskRetentionInterval time.Duration       // how often the retention job runs
+	AccessLogSampleRate  int                  // log 1 in N successful requests, errors are always logged
+}
+
+// initializes viper to read from environment and the .env file in project root
+func initViper() {
+
+	viper.AutomaticEnv()
+
+	_, filename, _, _ := runtime.Caller(0)
+	rootDir := filepath.Dir(filepath.Dir(filename))
+
+	// configure viper
+	viper.SetConfigName(".env")               // set config name
+	viper.SetConfigType("env")                // set config type
+	viper.AddConfigPath(".")                  // current directory
+	viper.AddConfigPath(rootDir)              // project root
+
+	err := viper.ReadInConfig()
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Printf("error reading config: %v", err)
+		}
+	}
+}
+
+// loads application configuration with sensible defaults
+func LoadConfig() *Config {
+
+	initViper()
+
+	// defaults used when variables are not set
+	viper.SetDefault("DB_MAX_CONCURRENT_OPS", 100)
+	viper.SetDefault("DB_OP_QUEUE_TIMEOUT", "500ms")
+	viper.SetDefault("DB_READ_TIMEOUT", "5s")
+	viper.SetDefault("DB_WRITE_TIMEOUT", "10s")
+	viper.SetDefault("DB_AGGREGATE_TIMEOUT", "30s")
+	viper.SetDefault("AUTH_MAX_CONCURRENT", 16)
+	viper.SetDefault("PASSWORD_POLICY_IN_ERRORS", false)
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_REQUIRED_CLASSES", "")
+	viper.SetDefault("CORS_ORIGINS", "*")
+	viper.SetDefault("HTTPS_REDIRECT", false)
+	viper.SetDefault("HSTS_MAX_AGE", "0s")
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_WINDOW", "15m")
+	viper.SetDefault("LOGIN_WARN_PERCENT", 20)
+	viper.SetDefault("ACCESS_LOG_SAMPLE_RATE", 1)
+	viper.SetDefault("API_KEYS_ENABLED", false)
+	viper.SetDefault("TASK_UPDATABLE_FIELDS", "title,description,due_date,due_date_tz,status,priority,depends_on,project_id")
+	viper.SetDefault("TASK_STRICT_DUE_DATE", false)
+	viper.SetDefault("TASK_EPOCH_DUE_DATES", true)
+	viper.SetDefault("TASK_WORKDAY_DUE_DATES", false)
+	viper.SetDefault("TASK_REQUIRE_IF_MATCH", false)
+	viper.SetDefault("TASK_SELF_ASSIGN", false)
+	viper.SetDefault("TASK_LOCK_IN_PROGRESS_ASSIGNEE", false)
+	viper.SetDefault("TASK_REOPEN_COMPLETED", false)
+	viper.SetDefault("TASK_LOCK_COMPLETED", false)
+	viper.SetDefault("TASK_CREATE_RATE_LIMIT", 0)
+	viper.SetDefault("TASK_CREATE_RATE_WINDOW", "1h")
+	viper.SetDefault("TASK_MAX_DESCRIPTION_BYTES", 10240)
+	viper.SetDefault("TASK_MAX_DEPENDENCIES", 20)
+	viper.SetDefault("TASK_MAX_DEPENDENCY_DEPTH", 10)
+	viper.SetDefault("TASK_MAX_DOCUMENT_FRACTION", 0.5)
+	viper.SetDefault("TASK_LIST_OMIT_COMPLETED_DESCRIPTIONS", false)
+	viper.SetDefault("PASSWORD_CHANGE_GRACE", "0s")
+	viper.SetDefault("TASK_ARCHIVE_ENABLED", false)
+	viper.SetDefault("TASK_ARCHIVE_AFTER", "720h")
+	viper.SetDefault("TASK_ARCHIVE_INTERVAL", "1h")
+	viper.SetDefault("TASK_RETENTION_RULES", "")
+	viper.SetDefault("TASK_RETENTION_INTERVAL", "24h")
+
+	return &Config{
+		DBMaxConcurrentOps: viper.GetInt("DB_MAX_CONCURRENT_OPS"),
+		DBOpQueueTimeout:   viper.GetDuration("DB_OP_QUEUE_TIMEOUT"),
+		DBReadTimeout:      viper.GetDuration("DB_READ_TIMEOUT"),
+		DBWriteTimeout:     viper.GetDuration("DB_WRITE_TIMEOUT"),
+		DBAggregateTimeout: viper.GetDuration("DB_AGGREGATE_TIMEOUT"),
+		AuthMaxConcurrent:  viper.GetInt("AUTH_MAX_CONCURRENT"),
+		PasswordPolicyInErrors: viper.GetBool("PASSWORD_POLICY_IN_ERRORS"),
+		PasswordMinLength:  viper.GetInt("PASSWORD_MIN_LENGTH"),
+		PasswordRequiredClasses: splitList(viper.GetString("PASSWORD_REQUIRED_CLASSES")),
+		CORSOrigins:        splitList(viper.GetString("CORS_ORIGINS")),
+		HTTPSRedirect:      viper.GetBool("HTTPS_REDIRECT"),
+		HSTSMaxAge:         viper.GetDuration("HSTS_MAX_AGE"),
+		LoginMaxAttempts:   viper.GetInt("LOGIN_MAX_ATTEMPTS"),
+		LoginWindow:        viper.GetDuration("LOGIN_WINDOW"),
+		LoginWarnPercent:   viper.GetInt("LOGIN_WARN_PERCENT"),
+		APIKeysEnabled:     viper.GetBool("API_KEYS_ENABLED"),
+		TaskUpdatableFields: splitList(viper.GetString("TASK_UPDATABLE_FIELDS")),
+		TaskStrictDueDate:  viper.GetBool("TASK_STRICT_DUE_DATE"),
+		TaskEpochDueDates:  viper.GetBool("TASK_EPOCH_DUE_DATES"),
+		TaskWorkdayDueDates: viper.GetBool("TASK_WORKDAY_DUE_DATES"),
+		TaskRequireIfMatch: viper.GetBool("TASK_REQUIRE_IF_MATCH"),
+		TaskSelfAssign:     viper.GetBool("TASK_SELF_ASSIGN"),
+		TaskLockInProgressAssignee: viper.GetBool("TASK_LOCK_IN_PROGRESS_ASSIGNEE"),
+		TaskReopenCompleted:        viper.GetBool("TASK_REOPEN_COMPLETED"),
+		TaskLockCompleted:          viper.GetBool("TASK_LOCK_COMPLETED"),
+		TaskCreateRateLimit: viper.GetInt("TASK_CREATE_RATE_LIMIT"),
+		TaskCreateRateWindow: viper.GetDuration("TASK_CREATE_RATE_WINDOW"),
+		TaskMaxDescriptionBytes: viper.GetInt("TASK_MAX_DESCRIPTION_BYTES"),
+		TaskMaxDependencies:     viper.GetInt("TASK_MAX_DEPENDENCIES"),
+		TaskMaxDependencyDepth:  viper.GetInt("TASK_MAX_DEPENDENCY_DEPTH"),
+		TaskMaxDocumentFraction: viper.GetFloat64("TASK_MAX_DOCUMENT_FRACTION"),
+		TaskListOmitCompletedDescriptions: viper.GetBool("TASK_LIST_OMIT_COMPLETED_DESCRIPTIONS"),
+		SeedAdminUsername:  viper.GetString("SEED_ADMIN_USERNAME"),
+		PasswordChangeGrace: viper.GetDuration("PASSWORD_CHANGE_GRACE"),
+		TaskArchiveEnabled: viper.GetBool("TASK_ARCHIVE_ENABLED"),
+		TaskArchiveAfter:   viper.GetDuration("TASK_ARCHIVE_AFTER"),
+		TaskArchiveInterval: viper.GetDuration("TASK_ARCHIVE_INTERVAL"),
+		TaskRetentionRules: parseRetentionRules(viper.GetString("TASK_RETENTION_RULES")),
+		TaskRetentionInterval: viper.GetDuration("TASK_RETENTION_INTERVAL"),
+		AccessLogSampleRate: viper.GetInt("ACCESS_LOG_SAMPLE_RATE"),
+	}
+}
+
+// parses comma separated status=duration retention rules, e.g. archived=8760h,
+// malformed rules are skipped with a warning so a typo never deletes more than configured
+func parseRetentionRules(value string) []domain.RetentionRule {
+
+	var rules []domain.RetentionRule
+	for _, item := range splitList(value) {
+		status, after, found := strings.Cut(item, "=")
+		age, err := time.ParseDuration(strings.TrimSpace(after))
+		if !found || strings.TrimSpace(status) == "" || err != nil || age <= 0 {
+			log.Printf("warning: invalid task retention rule %q ignored, expected status=duration", item)
+			continue
+		}
+		rules = append(rules, domain.RetentionRule{Status: strings.TrimSpace(status), After: age})
+	}
+
+	return rules
+}
+
+// splits a comma separated config value into trimmed, non-empty items
+func splitList(value string) []string {
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,53 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"strings"
+	"github.com/gin-gonic/gin"
+)
+
+// methods and headers browsers may use on cross origin requests
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, If-Modified-Since, If-None-Match, If-Match, X-API-Key"
+	corsExposeHeaders = "ETag, Last-Modified, Retry-After, Warning, X-Total-Count"
+)
+
+// sets the CORS response headers for allowed origins and answers preflight requests with 204,
+// "*" in allowedOrigins allows every origin
+func NewCORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(c *gin.Context) {
+
+		origin := c.GetHeader("Origin")
+		if origin != "" && (allowAll || allowed[origin]) {
+			if allowAll {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)        // reflect only listed origins
+				c.Header("Vary", "Origin")
+			}
+			c.Header("Access-Control-Allow-Methods", corsAllowMethods)
+			c.Header("Access-Control-Allow-Headers", corsAllowHeaders)
+			c.Header("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+
+		// preflight requests never reach the routes
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for the CORS middleware
+type CORSMiddlewareTestSuite struct {
+	suite.Suite
+}
+
+// builds a router with the CORS middleware and one route
+func (suite *CORSMiddlewareTestSuite) router(allowedOrigins []string) *gin.Engine {
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewCORSMiddleware(allowedOrigins))
+	router.GET("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	return router
+}
+
+// sends a request with the given origin
+func (suite *CORSMiddlewareTestSuite) serve(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+
+	req := httptest.NewRequest(method, "/tasks", nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+// tests a preflight from an allowed origin is answered with 204 and the CORS headers
+func (suite *CORSMiddlewareTestSuite) TestPreflight_AllowedOrigin() {
+
+	w := suite.serve(suite.router([]string{"https://app.example.com"}), http.MethodOptions, "https://app.example.com")
+
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)                                                // status should be 204
+	assert.Equal(suite.T(), "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))    // origin reflected
+	assert.Contains(suite.T(), w.Header().Get("Access-Control-Allow-Methods"), "DELETE")                 // methods listed
+	assert.Contains(suite.T(), w.Header().Get("Access-Control-Allow-Headers"), "Authorization")          // auth header allowed
+	assert.Equal(suite.T(), "Origin", w.Header().Get("Vary"))                                            // cache per origin
+}
+
+// tests a disallowed origin is not reflected back
+func (suite *CORSMiddlewareTestSuite) TestDisallowedOrigin() {
+
+	router := suite.router([]string{"https://app.example.com"})
+
+	w := suite.serve(router, http.MethodOptions, "https://evil.example.com")
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)                        // preflight still answered
+	assert.Empty(suite.T(), w.Header().Get("Access-Control-Allow-Origin"))       // origin not allowed
+
+	w = suite.serve(router, http.MethodGet, "https://evil.example.com")
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                               // request itself still served
+	assert.Empty(suite.T(), w.Header().Get("Access-Control-Allow-Origin"))       // browser will block the response
+}
+
+// tests the wildcard allows every origin and actual requests reach the route
+func (suite *CORSMiddlewareTestSuite) TestWildcard() {
+
+	w := suite.serve(suite.router([]string{"*"}), http.MethodGet, "https://any.example.com")
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                                    // status should be 200
+	assert.Equal(suite.T(), "*", w.Header().Get("Access-Control-Allow-Origin"))       // any origin
+	assert.Contains(suite.T(), w.Body.String(), "success")                            // handler ran
+}
+
+// runs the test suite for the CORS middleware
+func TestCORSMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(CORSMiddlewareTestSuite))
+}
@@ -0,0 +1,93 @@
+package infrastructure
+
+// imports
+import (
+	"context"
+	"net/http"
+	"time"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// dependency checked by the readiness probe
+type Pinger interface {
+	Ping(ctx context.Context) error        // nil when the dependency is reachable
+}
+
+// adapts a plain function to a Pinger
+type PingerFunc func(ctx context.Context) error
+
+func (f PingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+// creates a pinger for the mongodb server at uri, connecting lazily
+func NewMongoPinger(uri string) (Pinger, error) {
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return PingerFunc(func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}), nil
+}
+
+// serves the liveness and readiness probes
+type HealthHandler struct {
+	pinger   Pinger              // database checked for readiness (nil = always ready)
+	timeout  time.Duration       // longest a readiness check may take
+}
+
+// creates probes checking pinger within timeout
+func NewHealthHandler(pinger Pinger, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{pinger: pinger, timeout: timeout}
+}
+
+// answers 200 while the process can serve requests, regardless of dependencies
+func (health *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// pings the database within the timeout, reporting up, down or unchecked without a pinger
+func (health *HealthHandler) databaseState(c *gin.Context) string {
+
+	if health.pinger == nil {
+		return "unchecked"
+	}
+
+	contx, cancel := context.WithTimeout(c.Request.Context(), health.timeout)        // set timeout
+	defer cancel()
+
+	if err := health.pinger.Ping(contx); err != nil {
+		return "down"
+	}
+
+	return "up"
+}
+
+// answers 200 only when the database answers a ping, 503 otherwise
+func (health *HealthHandler) Readyz(c *gin.Context) {
+
+	if health.databaseState(c) == "down" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database unreachable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// like Readyz, but names the database state for monitors - {"status":"ok","db":"up"} or a 503 {"status":"degraded","db":"down"}
+func (health *HealthHandler) Health(c *gin.Context) {
+
+	db := health.databaseState(c)
+	if db == "down" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "degraded", "db": db})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "db": db})
+}
@@ -0,0 +1,96 @@
+package infrastructure
+
+// imports
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for HealthHandler
+type HealthHandlerTestSuite struct {
+	suite.Suite
+	pingErr  error              // error the fake database ping returns
+	router   *gin.Engine        // gin router for testing
+}
+
+// sets up the probes against a fake database
+func (suite *HealthHandlerTestSuite) SetupTest() {
+
+	gin.SetMode(gin.TestMode)
+	suite.pingErr = nil
+	health := NewHealthHandler(PingerFunc(func(ctx context.Context) error { return suite.pingErr }), time.Second)
+
+	suite.router = gin.New()
+	suite.router.GET("/livez", health.Livez)
+	suite.router.GET("/readyz", health.Readyz)
+	suite.router.GET("/health", health.Health)
+}
+
+// sends a probe request to path
+func (suite *HealthHandlerTestSuite) probe(path string) int {
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return w.Code
+}
+
+// tests both probes pass while the database answers
+func (suite *HealthHandlerTestSuite) TestHealthy() {
+	assert.Equal(suite.T(), http.StatusOK, suite.probe("/livez"))        // status should be 200
+	assert.Equal(suite.T(), http.StatusOK, suite.probe("/readyz"))       // status should be 200
+}
+
+// tests only readiness fails when the database is unreachable
+func (suite *HealthHandlerTestSuite) TestDatabaseDown() {
+
+	suite.pingErr = errors.New("connection refused")
+
+	assert.Equal(suite.T(), http.StatusOK, suite.probe("/livez"))                      // process is still alive
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, suite.probe("/readyz"))     // status should be 503
+}
+
+// tests the health endpoint names the database state
+func (suite *HealthHandlerTestSuite) TestHealth_DatabaseState() {
+
+	get := func() (int, map[string]string) {
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var body map[string]string
+		assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	code, body := get()
+	assert.Equal(suite.T(), http.StatusOK, code)                                           // status should be 200
+	assert.Equal(suite.T(), map[string]string{"status": "ok", "db": "up"}, body)           // database answers
+
+	suite.pingErr = errors.New("connection refused")
+	code, body = get()
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, code)                           // status should be 503
+	assert.Equal(suite.T(), map[string]string{"status": "degraded", "db": "down"}, body)   // database unreachable
+}
+
+// tests a handler without a pinger is always ready
+func (suite *HealthHandlerTestSuite) TestNoPinger() {
+
+	router := gin.New()
+	router.GET("/readyz", NewHealthHandler(nil, time.Second).Readyz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)       // status should be 200
+}
+
+// runs the test suite for HealthHandler
+func TestHealthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthHandlerTestSuite))
+}
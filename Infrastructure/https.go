@@ -0,0 +1,63 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// https enforcement for deployments behind a tls terminating proxy
+type HTTPSConfig struct {
+	Redirect     bool             // redirect plain http requests to the same url on https
+	HSTSMaxAge   time.Duration    // max-age of the Strict-Transport-Security header on https responses (0 = no header)
+	ExemptPaths  []string         // never redirected, e.g. probes called over plain http inside the cluster
+}
+
+// reports whether the client reached us over https, directly or through a proxy setting X-Forwarded-Proto
+// a spoofed header only lets that client skip its own redirect
+func isHTTPS(r *http.Request) bool {
+
+	if r.TLS != nil {
+		return true
+	}
+	proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")        // first hop when proxies are chained
+
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
+
+// redirects plain http requests to https and sets HSTS on https responses, both as configured
+func NewHTTPSMiddleware(config HTTPSConfig) gin.HandlerFunc {
+
+	exempt := make(map[string]bool, len(config.ExemptPaths))
+	for _, path := range config.ExemptPaths {
+		exempt[path] = true
+	}
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(config.HSTSMaxAge/time.Second), 10)
+	}
+
+	return func(c *gin.Context) {
+
+		if isHTTPS(c.Request) {
+			// browsers ignore HSTS received over plain http, so it is only sent on https
+			if hsts != "" {
+				c.Header("Strict-Transport-Security", hsts)
+			}
+			c.Next()
+			return
+		}
+
+		if config.Redirect && !exempt[c.Request.URL.Path] {
+			// 308 keeps the method and body, so a POST stays a POST
+			c.Redirect(http.StatusPermanentRedirect, "https://"+c.Request.Host+c.Request.URL.RequestURI())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+// imports
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for the https middleware
+type HTTPSMiddlewareTestSuite struct {
+	suite.Suite
+}
+
+// builds a router with the https middleware, a task route and a probe
+func (suite *HTTPSMiddlewareTestSuite) router(config HTTPSConfig) *gin.Engine {
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewHTTPSMiddleware(config))
+	router.POST("/tasks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	return router
+}
+
+// sends a request, forwardedProto is the X-Forwarded-Proto a proxy would set
+func (suite *HTTPSMiddlewareTestSuite) serve(router *gin.Engine, method, target, forwardedProto string) *httptest.ResponseRecorder {
+
+	req := httptest.NewRequest(method, target, nil)
+	if forwardedProto != "" {
+		req.Header.Set("X-Forwarded-Proto", forwardedProto)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+// tests plain http is redirected to the same url on https, keeping the method
+func (suite *HTTPSMiddlewareTestSuite) TestRedirect() {
+
+	router := suite.router(HTTPSConfig{Redirect: true})
+	w := suite.serve(router, http.MethodPost, "http://api.example.com/tasks?page=2", "http")
+
+	assert.Equal(suite.T(), http.StatusPermanentRedirect, w.Code)                                  // status should be 308
+	assert.Equal(suite.T(), "https://api.example.com/tasks?page=2", w.Header().Get("Location"))    // same host, path and query
+}
+
+// tests requests that already came over https are served
+func (suite *HTTPSMiddlewareTestSuite) TestNoRedirectOverHTTPS() {
+
+	router := suite.router(HTTPSConfig{Redirect: true})
+
+	w := suite.serve(router, http.MethodPost, "http://api.example.com/tasks", "https")
+	assert.Equal(suite.T(), http.StatusOK, w.Code)        // proxy terminated tls
+
+	req := httptest.NewRequest(http.MethodPost, "https://api.example.com/tasks", nil)
+	req.TLS = &tls.ConnectionState{}
+	direct := httptest.NewRecorder()
+	router.ServeHTTP(direct, req)
+	assert.Equal(suite.T(), http.StatusOK, direct.Code)   // tls served directly
+}
+
+// tests exempt paths such as health checks are never redirected
+func (suite *HTTPSMiddlewareTestSuite) TestExemptPath() {
+
+	router := suite.router(HTTPSConfig{Redirect: true, ExemptPaths: []string{"/health"}})
+	w := suite.serve(router, http.MethodGet, "http://10.0.0.5/health", "")
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)        // probe answered over plain http
+}
+
+// tests the HSTS header is sent on https responses only
+func (suite *HTTPSMiddlewareTestSuite) TestHSTS() {
+
+	router := suite.router(HTTPSConfig{HSTSMaxAge: 365 * 24 * time.Hour})
+
+	w := suite.serve(router, http.MethodPost, "http://api.example.com/tasks", "https")
+	assert.Equal(suite.T(), "max-age=31536000", w.Header().Get("Strict-Transport-Security"))      // one year in seconds
+
+	plain := suite.serve(router, http.MethodPost, "http://api.example.com/tasks", "")
+	assert.Equal(suite.T(), http.StatusOK, plain.Code)                                  // no redirect configured
+	assert.Empty(suite.T(), plain.Header().Get("Strict-Transport-Security"))            // ignored by browsers over http
+}
+
+// tests nothing changes when both features are disabled
+func (suite *HTTPSMiddlewareTestSuite) TestDisabled() {
+
+	w := suite.serve(suite.router(HTTPSConfig{}), http.MethodPost, "http://api.example.com/tasks", "https")
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)                                  // status should be 200
+	assert.Empty(suite.T(), w.Header().Get("Strict-Transport-Security"))            // no header
+}
+
+// runs the test suite for the https middleware
+func TestHTTPSMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPSMiddlewareTestSuite))
+}
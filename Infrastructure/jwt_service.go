@@ -2,48 +2,144 @@ package infrastructure
 
 // imports
 import (
+	"crypto/rsa"
 	"errors"
-	"log"			
-	"path/filepath"
-	"runtime"
+	"fmt"
+	"log"
+	"os"
+	"strings"
 	"time"							
 	"github.com/dgrijalva/jwt-go"
 	"github.com/spf13/viper"
 )
 
+// token lifetime used when JWT_EXPIRY is missing or invalid
+const DefaultJWTExpiry = 24 * time.Hour
+
+// refresh token lifetime used when JWT_REFRESH_EXPIRY is missing or invalid
+const DefaultJWTRefreshExpiry = 7 * 24 * time.Hour
+
+// clock skew tolerated when checking the iat claim
+const IssuedAtLeeway = 30 * time.Second
+
+// values of the "type" claim telling access and refresh tokens apart
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// signing algorithms accepted in JWT_ALG
+const (
+	AlgHS256 = "HS256"        // shared secret, the default
+	AlgRS256 = "RS256"        // rsa key pair, others can verify with the public key only
+)
+
 type JWTService struct {
-	secret []byte
+	secret         []byte
+	privateKey     *rsa.PrivateKey      // set under RS256, tokens are signed with it and checked with its public key
+	expiry         time.Duration        // lifetime of generated tokens
+	refreshExpiry  time.Duration        // lifetime of generated refresh tokens
 }
 
 func NewJWTService() (*JWTService, error) {
 	
 	// intialize viper
-	viper.AutomaticEnv() 
+	initViper()
 	viper.BindEnv("JWT_SECRET") 
-	
-	_, filename, _, _ := runtime.Caller(0)
-	rootDir := filepath.Dir(filepath.Dir(filename))
-	
-	// configure viper
-	viper.SetConfigName(".env")               // set config name
-	viper.SetConfigType("env")                // set config type
-	viper.AddConfigPath(".")                  // current directory
-	viper.AddConfigPath(rootDir)              // project root
-	
-	err := viper.ReadInConfig(); 
+	viper.BindEnv("JWT_EXPIRY")
+	viper.BindEnv("JWT_REFRESH_EXPIRY")
+	viper.BindEnv("JWT_ALG")
+	viper.BindEnv("JWT_PRIVATE_KEY_PATH")
+
+	service := &JWTService{
+		expiry:         parseJWTExpiry(viper.GetString("JWT_EXPIRY")),
+		refreshExpiry:  parseLifetime("JWT_REFRESH_EXPIRY", viper.GetString("JWT_REFRESH_EXPIRY"), DefaultJWTRefreshExpiry),
+	}
+
+	switch alg := strings.ToUpper(viper.GetString("JWT_ALG")); alg {
+	case "", AlgHS256:
+		// get from JWT_SECRET variable in .env
+		secret := viper.GetString("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("JWT_SECRET must be set in .env or environment variables")
+		}
+		service.secret = []byte(secret)
+	case AlgRS256:
+		key, err := loadRSAPrivateKey(viper.GetString("JWT_PRIVATE_KEY_PATH"))
+		if err != nil {
+			return nil, err
+		}
+		service.privateKey = key
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALG %q, use %s or %s", alg, AlgHS256, AlgRS256)
+	}
+
+	return service, nil        // success 
+}
+
+// reads the PEM encoded rsa private key used for RS256
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+
+	if path == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_PATH must be set when JWT_ALG is RS256")
+	}
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT_PRIVATE_KEY_PATH: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
 	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			log.Printf("error reading config: %v", err)
+		return nil, fmt.Errorf("parsing JWT_PRIVATE_KEY_PATH: %w", err)
+	}
+
+	return key, nil
+}
+
+// signs claims with the configured algorithm
+func (jwtServ *JWTService) sign(claims jwt.MapClaims) (string, error) {
+
+	if jwtServ.privateKey != nil {
+		return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(jwtServ.privateKey)
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtServ.secret)
+}
+
+// picks the verification key for a token, tokens signed with another algorithm family are rejected
+func (jwtServ *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+
+	if jwtServ.privateKey != nil {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, jwt.ErrSignatureInvalid      // e.g. an HS256 token signed with the public key
 		}
+		return &jwtServ.privateKey.PublicKey, nil
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, jwt.ErrSignatureInvalid      // block invalid signing
 	}
-    
-	// get from JWT_SECRET variable in .env
-	secret := viper.GetString("JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("JWT_SECRET must be set in .env or environment variables")
+	return jwtServ.secret, nil     // return secret to verify signature
+}
+
+// parses the JWT_EXPIRY duration, falling back to the default with a warning
+func parseJWTExpiry(raw string) time.Duration {
+	return parseLifetime("JWT_EXPIRY", raw, DefaultJWTExpiry)
+}
+
+// parses a token lifetime setting, falling back to the given default with a warning
+func parseLifetime(name, raw string, fallback time.Duration) time.Duration {
+
+	if raw == "" {
+		log.Printf("warning: %s not set, using %s", name, fallback)
+		return fallback
+	}
+	expiry, err := time.ParseDuration(raw)
+	if err != nil || expiry <= 0 {
+		log.Printf("warning: invalid %s %q, using %s", name, raw, fallback)
+		return fallback
 	}
 
-	return &JWTService{secret: []byte(secret)}, nil        // success 
+	return expiry
 }
 
 func (jwtServ *JWTService) GenerateToken(userID, username, role string) (string, error) {
@@ -59,32 +155,113 @@ func (jwtServ *JWTService) GenerateToken(userID, username, role string) (string,
 		return "", errors.New("role cannot be empty")
 	}
 
+	expiry := jwtServ.expiry
+	if expiry <= 0 {
+		expiry = DefaultJWTExpiry        // service built without configuration
+	}
+
 	// create token with claims 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	now := time.Now()
+	return jwtServ.sign(jwt.MapClaims{
 		"userId": userID,            // user id          
 		"username": username,        // username
 		"role": role,                // user role (admin/user)
-		"exp": time.Now().Add(time.Hour * 24).Unix(),      // expires in 24h
-	})
+		"type": accessTokenType,     // only access tokens open protected routes
+		"iat": now.Unix(),           // issue time, compared with the last password change
+		"exp": now.Add(expiry).Unix(),      // expires after the configured lifetime
+	})        // sign with the configured key
+}
 
-	// sign with secret key
-	return token.SignedString(jwtServ.secret)         // success 
+func (jwtServ *JWTService) GenerateRefreshToken(userID string) (string, error) {
+
+	// input validation
+	if userID == "" {
+		return "", errors.New("userID cannot be empty")
+	}
+
+	expiry := jwtServ.refreshExpiry
+	if expiry <= 0 {
+		expiry = DefaultJWTRefreshExpiry        // service built without configuration
+	}
+
+	// refresh tokens only carry the user, role and username are read again on refresh
+	now := time.Now()
+	return jwtServ.sign(jwt.MapClaims{
+		"userId": userID,
+		"type": refreshTokenType,
+		"iat": now.Unix(),           // issue time, compared with the last password change on refresh
+		"exp": now.Add(expiry).Unix(),
+	})
 }
 
 func (jwtServ *JWTService) ValidateToken(tokenStr string) (*jwt.Token, error) {
+
+	token, err := jwtServ.parseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// refresh tokens must not be used as access tokens
+	if tokenType(token) == refreshTokenType {
+		return nil, errors.New("refresh token cannot be used for access")
+	}
+
+	return token, nil       // success 
+}
+
+func (jwtServ *JWTService) ValidateRefreshToken(tokenStr string) (string, time.Time, error) {
+
+	token, err := jwtServ.parseToken(tokenStr)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// access tokens must not be used to refresh
+	if tokenType(token) != refreshTokenType {
+		return "", time.Time{}, errors.New("not a refresh token")
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	userID, ok := claims["userId"].(string)
+	if !ok || userID == "" {
+		return "", time.Time{}, errors.New("invalid user claim")
+	}
+
+	// tokens without iat come back with a zero time, issued before any password change
+	var issuedAt time.Time
+	if iat, ok := claims["iat"].(float64); ok {
+		issuedAt = time.Unix(int64(iat), 0)
+	}
+
+	return userID, issuedAt, nil
+}
+
+// reads the "type" claim, tokens without one are treated as access tokens
+func tokenType(token *jwt.Token) string {
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return accessTokenType
+	}
+	kind, ok := claims["type"].(string)
+	if !ok || kind == "" {
+		return accessTokenType
+	}
+
+	return kind
+}
+
+// checks signature and expiry of a token of either type
+func (jwtServ *JWTService) parseToken(tokenStr string) (*jwt.Token, error) {
 	
 	// input validation
 	if tokenStr == "" {
 		return nil, errors.New("token cannot be empty")
 	}
 
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {	
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)    // check if token uses HMAC signing  
-		if !ok {
-			return nil, jwt.ErrSignatureInvalid      // block invalid signing 
-		}
-		return jwtServ.secret, nil     // return secret to verify signature
-	})
+	// time based claims are checked below, so the iat check can allow for clock skew
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(tokenStr, jwtServ.verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -105,9 +282,18 @@ func (jwtServ *JWTService) ValidateToken(tokenStr string) (*jwt.Token, error) {
 		} else {
 			return nil, errors.New("invalid expiration claim")
 		}
+		// reject tokens issued in the future, allowing for clock skew
+		if iat, ok := claims["iat"].(float64); ok {
+			if int64(iat) > time.Now().Add(IssuedAtLeeway).Unix() {
+				return nil, errors.New("Token used before issued")
+			}
+		}
+		if !claims.VerifyNotBefore(time.Now().Unix(), false) {
+			return nil, errors.New("Token is not valid yet")
+		}
 	}
 
-	return token, nil       // success 
+	return token, nil
 } 
 
 func (jwtServ *JWTService) GetSecret() string {
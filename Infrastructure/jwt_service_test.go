@@ -2,6 +2,12 @@ package infrastructure
 
 // imports
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 	"github.com/dgrijalva/jwt-go"
@@ -267,6 +273,240 @@ func (suite *JWTServiceTestSuite) TestTokenExpiration() {
 	assert.Contains(suite.T(), err.Error(), "Token is expired")       // check for expiration error
 }
 
+// tests tokens generated with a configured 1 second lifetime expire
+func (suite *JWTServiceTestSuite) TestConfiguredExpiry() {
+
+	// configure a 1 second token lifetime
+	viper.Set("JWT_SECRET", "expiry-secret")
+	viper.Set("JWT_EXPIRY", "1s")
+	defer viper.Set("JWT_EXPIRY", "")
+
+	service, err := NewJWTService()
+	require.NoError(suite.T(), err)
+
+	token, err := service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+
+	// immediately validate - should work
+	_, err = service.ValidateToken(token)
+	require.NoError(suite.T(), err)
+
+	// after the lifetime elapses - it should fail
+	time.Sleep(2 * time.Second)
+	_, err = service.ValidateToken(token)
+	require.Error(suite.T(), err)                                     // check for error
+	assert.Contains(suite.T(), err.Error(), "Token is expired")       // check for expiration error
+}
+
+// tests a one hour lifetime puts the exp claim about an hour out
+func (suite *JWTServiceTestSuite) TestExpiryClaim() {
+
+	viper.Set("JWT_SECRET", "expiry-secret")
+	viper.Set("JWT_EXPIRY", "1h")
+	defer viper.Set("JWT_EXPIRY", "")
+
+	service, err := NewJWTService()
+	require.NoError(suite.T(), err)
+
+	tokenStr, err := service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+	token, err := service.ValidateToken(tokenStr)
+	require.NoError(suite.T(), err)
+
+	exp := int64(token.Claims.(jwt.MapClaims)["exp"].(float64))
+	assert.InDelta(suite.T(), time.Now().Add(time.Hour).Unix(), exp, 5)       // roughly one hour out
+}
+
+// tests an invalid lifetime falls back to the default without failing the service
+func (suite *JWTServiceTestSuite) TestInvalidExpiryFallsBack() {
+
+	viper.Set("JWT_SECRET", "expiry-secret")
+	viper.Set("JWT_EXPIRY", "one day")
+	defer viper.Set("JWT_EXPIRY", "")
+
+	service, err := NewJWTService()
+	require.NoError(suite.T(), err)                              // no error for a bad duration
+	assert.Equal(suite.T(), DefaultJWTExpiry, service.expiry)    // default lifetime used
+}
+
+// tests missing or invalid lifetimes fall back to the default
+func (suite *JWTServiceTestSuite) TestParseJWTExpiry() {
+	assert.Equal(suite.T(), 15*time.Minute, parseJWTExpiry("15m"))         // valid duration used
+	assert.Equal(suite.T(), DefaultJWTExpiry, parseJWTExpiry(""))          // missing falls back
+	assert.Equal(suite.T(), DefaultJWTExpiry, parseJWTExpiry("soon"))      // unparsable falls back
+	assert.Equal(suite.T(), DefaultJWTExpiry, parseJWTExpiry("-1h"))       // non positive falls back
+}
+
+// tests refresh tokens and access tokens are not interchangeable
+func (suite *JWTServiceTestSuite) TestRefreshToken() {
+
+	refreshToken, err := suite.service.GenerateRefreshToken("user123")
+	require.NoError(suite.T(), err)
+	accessToken, err := suite.service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+
+	// refresh token is accepted for refreshing
+	userID, issuedAt, err := suite.service.ValidateRefreshToken(refreshToken)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user123", userID)       // user id claim returned
+	assert.WithinDuration(suite.T(), time.Now(), issuedAt, 2*time.Second)      // issue time returned for the password change check
+
+	// refresh token is rejected as access token
+	_, err = suite.service.ValidateToken(refreshToken)
+	assert.Error(suite.T(), err)
+
+	// access token is rejected for refreshing
+	_, _, err = suite.service.ValidateRefreshToken(accessToken)
+	assert.Error(suite.T(), err)
+
+	// empty user id is rejected
+	_, err = suite.service.GenerateRefreshToken("")
+	assert.Error(suite.T(), err)
+}
+
+// tests a refresh token lives longer than an access token
+func (suite *JWTServiceTestSuite) TestRefreshTokenExpiry() {
+
+	service := &JWTService{secret: []byte("test-secret"), expiry: time.Hour, refreshExpiry: 48 * time.Hour}
+	refreshToken, err := service.GenerateRefreshToken("user123")
+	require.NoError(suite.T(), err)
+
+	token, _ := jwt.Parse(refreshToken, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(suite.T(), "refresh", claims["type"])                                                        // refresh type claim
+	assert.InDelta(suite.T(), time.Now().Add(48*time.Hour).Unix(), int64(claims["exp"].(float64)), 5)       // refresh lifetime used
+}
+
+// tests tokens issued in the future are rejected beyond the leeway
+func (suite *JWTServiceTestSuite) TestIssuedAtInFuture() {
+
+	sign := func(iat time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"userId":   "user123",
+			"username": "testuser",
+			"role":     "user",
+			"iat":      iat.Unix(),
+			"exp":      time.Now().Add(2 * time.Hour).Unix(),
+		}).SignedString([]byte(suite.service.GetSecret()))
+		require.NoError(suite.T(), err)
+		return token
+	}
+
+	// an hour ahead - rejected
+	_, err := suite.service.ValidateToken(sign(time.Now().Add(time.Hour)))
+	require.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "used before issued")      // check for iat error
+
+	// a few seconds of clock skew - accepted
+	_, err = suite.service.ValidateToken(sign(time.Now().Add(10 * time.Second)))
+	assert.NoError(suite.T(), err)
+
+	// generated tokens carry iat
+	tokenStr, err := suite.service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+	token, err := suite.service.ValidateToken(tokenStr)
+	require.NoError(suite.T(), err)
+	assert.Contains(suite.T(), token.Claims.(jwt.MapClaims), "iat")
+}
+
+// writes a fresh rsa private key as PEM and returns the key and its path
+func (suite *JWTServiceTestSuite) writeRSAKey() (*rsa.PrivateKey, string) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(suite.T(), err)
+
+	path := filepath.Join(suite.T().TempDir(), "jwt.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(suite.T(), os.WriteFile(path, pemBytes, 0600))
+
+	return key, path
+}
+
+// builds a service from RS256 settings, restoring HS256 afterwards
+func (suite *JWTServiceTestSuite) rs256Service(path string) (*JWTService, error) {
+
+	viper.Set("JWT_ALG", AlgRS256)
+	viper.Set("JWT_PRIVATE_KEY_PATH", path)
+	defer viper.Set("JWT_ALG", "")
+	defer viper.Set("JWT_PRIVATE_KEY_PATH", "")
+
+	return NewJWTService()
+}
+
+// tests RS256 tokens are signed with the private key and verify with the public key
+func (suite *JWTServiceTestSuite) TestRS256() {
+
+	key, path := suite.writeRSAKey()
+	service, err := suite.rs256Service(path)
+	require.NoError(suite.T(), err)
+
+	tokenStr, err := service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+
+	// another service holding only the public key can verify the token
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "RS256", token.Method.Alg())        // signed with RS256
+
+	token, err = service.ValidateToken(tokenStr)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user123", token.Claims.(jwt.MapClaims)["userId"])      // claims are kept
+
+	// refresh tokens use the same key
+	refresh, err := service.GenerateRefreshToken("user123")
+	require.NoError(suite.T(), err)
+	userID, _, err := service.ValidateRefreshToken(refresh)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user123", userID)
+}
+
+// tests HS256 tokens are rejected under RS256, even when signed with the public key
+func (suite *JWTServiceTestSuite) TestRS256RejectsHS256() {
+
+	key, path := suite.writeRSAKey()
+	service, err := suite.rs256Service(path)
+	require.NoError(suite.T(), err)
+
+	claims := jwt.MapClaims{"userId": "user123", "role": "admin", "exp": time.Now().Add(time.Hour).Unix()}
+
+	// a token from an HS256 deployment
+	hsToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	require.NoError(suite.T(), err)
+	_, err = service.ValidateToken(hsToken)
+	assert.Error(suite.T(), err)        // wrong algorithm
+
+	// algorithm confusion - the public key used as an HMAC secret
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(suite.T(), err)
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}))
+	require.NoError(suite.T(), err)
+	_, err = service.ValidateToken(forged)
+	assert.Error(suite.T(), err)        // forged token rejected
+
+	// and RS256 tokens are rejected by an HS256 service
+	rsToken, err := service.GenerateToken("user123", "testuser", "user")
+	require.NoError(suite.T(), err)
+	_, err = suite.service.ValidateToken(rsToken)
+	assert.Error(suite.T(), err)
+}
+
+// tests RS256 settings are checked when the service is built
+func (suite *JWTServiceTestSuite) TestRS256InvalidConfig() {
+
+	_, err := suite.rs256Service("")
+	assert.ErrorContains(suite.T(), err, "JWT_PRIVATE_KEY_PATH must be set")        // key path required
+
+	_, err = suite.rs256Service(filepath.Join(suite.T().TempDir(), "missing.pem"))
+	assert.ErrorContains(suite.T(), err, "reading JWT_PRIVATE_KEY_PATH")            // key file must exist
+
+	viper.Set("JWT_ALG", "none")
+	defer viper.Set("JWT_ALG", "")
+	_, err = NewJWTService()
+	assert.ErrorContains(suite.T(), err, "unsupported JWT_ALG")                     // unknown algorithm
+}
+
 // runs the test suite for JWTService
 func TestJWTServiceSuite(t *testing.T) {
 	suite.Run(t, new(JWTServiceTestSuite))     // run the test suite
@@ -0,0 +1,78 @@
+package infrastructure
+
+// imports
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// locks a client out of /login after too many failed attempts inside the window
+type LoginRateLimiter struct {
+	maxAttempts  int                       // failures allowed inside the window
+	window       time.Duration             // how long a failure counts, also the lockout
+	warnPercent  int                       // share of attempts left at which clients are warned (0 = never)
+	failures     *MemoryAttemptStore       // failed logins per client ip
+}
+
+// configurable limits of the login rate limiter
+type LoginLimitConfig struct {
+	MaxAttempts  int               // failures allowed inside the window (0 = unlimited)
+	Window       time.Duration     // how long a failure counts, also the lockout
+	WarnPercent  int               // add a Warning header once at most this percent of attempts is left (0 = never)
+}
+
+// creates a limiter allowing maxAttempts failed logins per client within window, a zero maxAttempts disables it
+func NewLoginRateLimiter(maxAttempts int, window time.Duration) *LoginRateLimiter {
+	return NewLoginRateLimiterWithConfig(LoginLimitConfig{MaxAttempts: maxAttempts, Window: window})
+}
+
+// creates a limiter with custom limits, nil when no limit is configured
+func NewLoginRateLimiterWithConfig(config LoginLimitConfig) *LoginRateLimiter {
+	if config.MaxAttempts <= 0 || config.Window <= 0 {
+		return nil        // no limit configured
+	}
+	return &LoginRateLimiter{
+		maxAttempts: config.MaxAttempts,
+		window:      config.Window,
+		warnPercent: config.WarnPercent,
+		failures:    NewMemoryAttemptStore(config.Window, nil),
+	}
+}
+
+// rejects locked out clients with 429, counts 401 responses as failures and clears them on success
+func (limiter *LoginRateLimiter) Handler() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		// keyed per ip, so guessing cannot lock a victim's account
+		key := c.ClientIP()
+		remaining := limiter.maxAttempts - limiter.failures.Count(key)
+		if remaining <= 0 {
+			c.Header("Retry-After", strconv.Itoa(int(limiter.window.Seconds())))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many attempts"})
+			c.Abort()
+			return
+		}
+		// set before the handler runs, headers cannot change once the response is written
+		if remaining*100 <= limiter.maxAttempts*limiter.warnPercent {
+			c.Header("Warning", fmt.Sprintf(`199 - "%d login attempts left before lockout"`, remaining))
+		}
+
+		c.Next()
+
+		switch c.Writer.Status() {
+		case http.StatusUnauthorized:
+			limiter.failures.Record(key)
+		case http.StatusOK:
+			limiter.failures.Reset(key)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package infrastructure
+
+// imports
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for LoginRateLimiter
+type LoginRateLimiterTestSuite struct {
+	suite.Suite
+	now      time.Time              // fixed clock for the tests
+	router   *gin.Engine            // gin router for testing
+	limiter  *LoginRateLimiter      // limiter being tested
+}
+
+// sets up a login route that fails unless the password header is right
+func (suite *LoginRateLimiterTestSuite) SetupTest() {
+
+	gin.SetMode(gin.TestMode)
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.limiter = NewLoginRateLimiter(5, 15*time.Minute)
+	suite.limiter.failures.now = func() time.Time { return suite.now }
+
+	suite.router = gin.New()
+	suite.router.POST("/login", suite.limiter.Handler(), func(c *gin.Context) {
+		if c.GetHeader("X-Password") != "correct" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"token": "token"})
+	})
+}
+
+// sends a login with the given password
+func (suite *LoginRateLimiterTestSuite) login(password string) *httptest.ResponseRecorder {
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set("X-Password", password)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	return w
+}
+
+// tests the sixth attempt after five failures within the window is blocked
+func (suite *LoginRateLimiterTestSuite) TestLockout() {
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(suite.T(), http.StatusUnauthorized, suite.login("wrong").Code)      // failures pass through
+	}
+
+	w := suite.login("correct")
+	assert.Equal(suite.T(), http.StatusTooManyRequests, w.Code)           // status should be 429
+	assert.Contains(suite.T(), w.Body.String(), "too many attempts")      // check response body
+	assert.Equal(suite.T(), "900", w.Header().Get("Retry-After"))         // lockout length
+
+	suite.now = suite.now.Add(16 * time.Minute)
+	assert.Equal(suite.T(), http.StatusOK, suite.login("correct").Code)   // window passed
+}
+
+// tests a successful login clears the failure count
+func (suite *LoginRateLimiterTestSuite) TestSuccessResets() {
+
+	for i := 0; i < 4; i++ {
+		suite.login("wrong")
+	}
+	assert.Equal(suite.T(), http.StatusOK, suite.login("correct").Code)       // status should be 200
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(suite.T(), http.StatusUnauthorized, suite.login("wrong").Code)     // count started over
+	}
+}
+
+// tests the Warning header appears only once few attempts are left
+func (suite *LoginRateLimiterTestSuite) TestWarningNearLimit() {
+
+	limiter := NewLoginRateLimiterWithConfig(LoginLimitConfig{MaxAttempts: 5, Window: 15 * time.Minute, WarnPercent: 40})
+	router := gin.New()
+	router.POST("/login", limiter.Handler(), func(c *gin.Context) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	})
+	login := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Empty(suite.T(), login().Header().Get("Warning"))         // plenty of attempts left
+	}
+	assert.Equal(suite.T(), `199 - "2 login attempts left before lockout"`, login().Header().Get("Warning"))     // 40% left
+	assert.Equal(suite.T(), `199 - "1 login attempts left before lockout"`, login().Header().Get("Warning"))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, login().Code)       // limit reached
+}
+
+// tests no Warning header is sent when warnings are off
+func (suite *LoginRateLimiterTestSuite) TestWarningDisabled() {
+
+	for i := 0; i < 4; i++ {
+		assert.Empty(suite.T(), suite.login("wrong").Header().Get("Warning"))      // default limiter never warns
+	}
+}
+
+// tests a zero limit disables the limiter
+func (suite *LoginRateLimiterTestSuite) TestDisabled() {
+	assert.Nil(suite.T(), NewLoginRateLimiter(0, time.Minute))       // no limiter configured
+}
+
+// runs the test suite for LoginRateLimiter
+func TestLoginRateLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginRateLimiterTestSuite))
+}
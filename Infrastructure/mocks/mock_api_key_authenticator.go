@@ -0,0 +1,25 @@
+package mock_infrastructure
+
+// imports
+import (
+	"context"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// mocks APIKeyAuthenticator for testing
+type MockAPIKeyAuthenticator struct {
+	mock.Mock
+}
+
+// mocks Authenticate method of APIKeyAuthenticator
+func (mcka *MockAPIKeyAuthenticator) Authenticate(contx context.Context, rawKey string) (*domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mcka.Called(contx, rawKey)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
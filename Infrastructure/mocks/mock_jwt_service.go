@@ -2,6 +2,7 @@ package mock_infrastructure
 
 // imports
 import (
+	"time"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/mock"
 )
@@ -30,6 +31,24 @@ func (mcjwts *MockJWTService) ValidateToken(token string) (*jwt.Token, error) {
 	return jwtToken, args.Error(1)
 }
 
+// mocks GenerateRefreshToken method of JWTService
+func (mcjwts *MockJWTService) GenerateRefreshToken(userID string) (string, error) {
+
+	// call the mocked method and return the results
+	args := mcjwts.Called(userID)
+
+	return args.String(0), args.Error(1)
+}
+
+// mocks ValidateRefreshToken method of JWTService
+func (mcjwts *MockJWTService) ValidateRefreshToken(token string) (string, time.Time, error) {
+
+	// call the mocked method and return the results
+	args := mcjwts.Called(token)
+
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
 // mocks GetSecret method of JWTService
 func (m *MockJWTService) GetSecret() string {
 
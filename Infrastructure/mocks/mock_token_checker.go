@@ -0,0 +1,22 @@
+package mock_infrastructure
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/stretchr/testify/mock"
+)
+
+// mocks TokenChecker for testing
+type MockTokenChecker struct {
+	mock.Mock
+}
+
+// mocks CheckTokenIssuedAt method of TokenChecker
+func (mctc *MockTokenChecker) CheckTokenIssuedAt(contx context.Context, userID string, issuedAt time.Time) error {
+
+	// call the mocked method and return the error if any
+	args := mctc.Called(contx, userID, issuedAt)
+
+	return args.Error(0)
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+// imports
+import (
+	"sync"
+	"time"
+)
+
+// revoked tokens, consulted by the auth middleware after a token is validated
+type TokenBlacklist interface {
+	Revoke(token string, exp time.Time)        // reject token until it expires on its own
+	IsRevoked(token string) bool               // report whether token was revoked
+}
+
+// in-memory blacklist - entries are dropped once their token has expired
+type MemoryBlacklist struct {
+	mu       sync.Mutex
+	revoked  map[string]time.Time        // token -> expiry
+	now      func() time.Time            // clock used for pruning
+}
+
+// creates an empty in-memory blacklist
+func NewMemoryBlacklist() *MemoryBlacklist {
+	return &MemoryBlacklist{revoked: make(map[string]time.Time), now: time.Now}
+}
+
+// revokes token until exp, already expired tokens are not stored
+func (bl *MemoryBlacklist) Revoke(token string, exp time.Time) {
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	bl.prune()
+	if exp.After(bl.now()) {
+		bl.revoked[token] = exp
+	}
+}
+
+// reports whether token is revoked and not yet expired
+func (bl *MemoryBlacklist) IsRevoked(token string) bool {
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	exp, ok := bl.revoked[token]
+	if !ok {
+		return false
+	}
+	if !exp.After(bl.now()) {
+		delete(bl.revoked, token)        // expired tokens are rejected by validation anyway
+		return false
+	}
+
+	return true
+}
+
+// number of tokens currently held
+func (bl *MemoryBlacklist) Len() int {
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	return len(bl.revoked)
+}
+
+// drops every expired entry, callers hold the lock
+func (bl *MemoryBlacklist) prune() {
+
+	now := bl.now()
+	for token, exp := range bl.revoked {
+		if !exp.After(now) {
+			delete(bl.revoked, token)
+		}
+	}
+}
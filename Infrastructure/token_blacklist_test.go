@@ -0,0 +1,53 @@
+package infrastructure
+
+// imports
+import (
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for MemoryBlacklist
+type MemoryBlacklistTestSuite struct {
+	suite.Suite
+	now        time.Time              // fixed clock for the tests
+	blacklist  *MemoryBlacklist       // blacklist being tested
+}
+
+// initializes the blacklist with a controllable clock before each test
+func (suite *MemoryBlacklistTestSuite) SetupTest() {
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.blacklist = NewMemoryBlacklist()
+	suite.blacklist.now = func() time.Time { return suite.now }
+}
+
+// tests a revoked token is reported until it expires
+func (suite *MemoryBlacklistTestSuite) TestRevoke() {
+
+	suite.blacklist.Revoke("token", suite.now.Add(time.Hour))
+	assert.True(suite.T(), suite.blacklist.IsRevoked("token"))       // revoked token
+	assert.False(suite.T(), suite.blacklist.IsRevoked("other"))      // unrelated token
+
+	suite.now = suite.now.Add(2 * time.Hour)
+	assert.False(suite.T(), suite.blacklist.IsRevoked("token"))      // expired entry dropped
+	assert.Equal(suite.T(), 0, suite.blacklist.Len())
+}
+
+// tests expired entries are pruned on the next revocation
+func (suite *MemoryBlacklistTestSuite) TestPrune() {
+
+	suite.blacklist.Revoke("short", suite.now.Add(time.Minute))
+	suite.blacklist.Revoke("long", suite.now.Add(time.Hour))
+	suite.blacklist.Revoke("expired", suite.now.Add(-time.Minute))      // never stored
+	assert.Equal(suite.T(), 2, suite.blacklist.Len())
+
+	suite.now = suite.now.Add(10 * time.Minute)
+	suite.blacklist.Revoke("new", suite.now.Add(time.Hour))
+	assert.Equal(suite.T(), 2, suite.blacklist.Len())                   // short lived entry pruned
+}
+
+// runs the test suite for MemoryBlacklist
+func TestMemoryBlacklistTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryBlacklistTestSuite))
+}
@@ -0,0 +1,176 @@
+package adapters
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// bounds the number of concurrent database operations shared by all repositories
+type OperationLimiter struct {
+	slots         chan struct{}        // semaphore of available operation slots
+	queueTimeout  time.Duration        // how long to wait for a slot before failing (0 = fail fast)
+}
+
+// creates a new limiter allowing at most maxOps concurrent operations
+func NewOperationLimiter(maxOps int, queueTimeout time.Duration) *OperationLimiter {
+	if maxOps <= 0 {
+		return nil        // no limit configured
+	}
+	return &OperationLimiter{slots: make(chan struct{}, maxOps), queueTimeout: queueTimeout}
+}
+
+// takes a slot, waiting up to the queue timeout, or returns ErrDatabaseBusy
+func (l *OperationLimiter) Acquire(ctx context.Context) error {
+
+	// take a free slot immediately if there is one
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	// fail fast when queueing is disabled
+	if l.queueTimeout <= 0 {
+		return domain.ErrDatabaseBusy
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	// wait for a slot, the queue timeout or the operation context
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return domain.ErrDatabaseBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// gives a slot back to the limiter
+func (l *OperationLimiter) Release() {
+	<-l.slots
+}
+
+// wraps a collection so every call goes through the shared limiter
+type LimitedCollection struct {
+	Collection  domain.MongoCollection
+	Limiter     *OperationLimiter
+}
+
+// wraps coll with the limiter, or returns coll unchanged when no limiter is set
+func NewLimitedCollection(coll domain.MongoCollection, limiter *OperationLimiter) domain.MongoCollection {
+	if limiter == nil {
+		return coll
+	}
+	return &LimitedCollection{Collection: coll, Limiter: limiter}
+}
+
+// a single result that only reports the limiter error
+type limitedSingleResult struct {
+	err error
+}
+
+// returns the limiter error instead of decoding
+func (r *limitedSingleResult) Decode(v interface{}) error {
+	return r.err
+}
+
+// gives the slot of a cursor back once ctx ends - the cursor fetches more batches after
+// Find or Aggregate return, and repositories cancel their operation context only after
+// closing it. A failed call or a context that never ends gives the slot back right away
+func (m *LimitedCollection) releaseAfterCursor(ctx context.Context, err error) {
+	if err != nil || ctx.Done() == nil {
+		m.Limiter.Release()
+		return
+	}
+	context.AfterFunc(ctx, m.Limiter.Release)
+}
+
+// inserts a single document when a slot is available
+func (m *LimitedCollection) InsertOne(ctx context.Context, doc interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer m.Limiter.Release()
+	return m.Collection.InsertOne(ctx, doc, opts...)
+}
+
+// finds documents when a slot is available, the slot is held while the cursor is read
+func (m *LimitedCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	cursor, err := m.Collection.Find(ctx, filter, opts...)
+	m.releaseAfterCursor(ctx, err)
+	return cursor, err
+}
+
+// finds a single document when a slot is available
+func (m *LimitedCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) domain.SingleResult {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return &limitedSingleResult{err: err}
+	}
+	defer m.Limiter.Release()
+	return m.Collection.FindOne(ctx, filter, opts...)
+}
+
+// updates a single document when a slot is available
+func (m *LimitedCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) domain.SingleResult {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return &limitedSingleResult{err: err}
+	}
+	defer m.Limiter.Release()
+	return m.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+// deletes a single document when a slot is available
+func (m *LimitedCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer m.Limiter.Release()
+	return m.Collection.DeleteOne(ctx, filter, opts...)
+}
+
+// counts documents when a slot is available
+func (m *LimitedCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return 0, err
+	}
+	defer m.Limiter.Release()
+	return m.Collection.CountDocuments(ctx, filter, opts...)
+}
+
+// updates matching documents when a slot is available
+func (m *LimitedCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer m.Limiter.Release()
+	return m.Collection.UpdateMany(ctx, filter, update, opts...)
+}
+
+// deletes matching documents when a slot is available
+func (m *LimitedCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer m.Limiter.Release()
+	return m.Collection.DeleteMany(ctx, filter, opts...)
+}
+
+// runs an aggregation when a slot is available, the slot is held while the cursor is read
+func (m *LimitedCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := m.Limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	cursor, err := m.Collection.Aggregate(ctx, pipeline, opts...)
+	m.releaseAfterCursor(ctx, err)
+	return cursor, err
+}
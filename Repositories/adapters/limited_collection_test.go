@@ -0,0 +1,142 @@
+package adapters
+
+// imports
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// test suite for the LimitedCollection
+type LimitedCollectionTestSuite struct {
+	suite.Suite
+	mockCollection  *mock_repositories.MockCollection      // mock collection being wrapped
+	release         chan struct{}                          // closed to let blocked calls finish
+	started         chan struct{}                          // signals that a blocked call holds a slot
+	holders         sync.WaitGroup                         // background calls holding a slot
+}
+
+// initializes the test suite before each test
+func (suite *LimitedCollectionTestSuite) SetupTest() {
+	suite.mockCollection = new(mock_repositories.MockCollection)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	suite.release = release
+	suite.started = started
+
+	// first count blocks until released so it keeps its slot
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, bson.M{}).
+		Run(func(args mock.Arguments) {
+			started <- struct{}{}
+			<-release
+		}).
+		Return(int64(1), nil)
+}
+
+// waits for the background calls of the test before the next test replaces the channels
+func (suite *LimitedCollectionTestSuite) TearDownTest() {
+	suite.holders.Wait()
+}
+
+// holds the only slot of the limited collection in the background
+func (suite *LimitedCollectionTestSuite) holdSlot(coll domain.MongoCollection) {
+	suite.holders.Add(1)
+	go func() {
+		defer suite.holders.Done()
+		coll.CountDocuments(context.Background(), bson.M{})
+	}()
+	<-suite.started
+}
+
+// tests operations within the limit pass through to the collection
+func (suite *LimitedCollectionTestSuite) TestWithinLimit() {
+
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(1, 0))
+	close(suite.release)
+
+	count, err := coll.CountDocuments(context.Background(), bson.M{})
+	assert.NoError(suite.T(), err)                 // no error expected
+	assert.Equal(suite.T(), int64(1), count)       // result comes from the collection
+}
+
+// tests operations beyond the limit fail fast when queueing is disabled
+func (suite *LimitedCollectionTestSuite) TestBeyondLimit_FailFast() {
+
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(1, 0))
+	suite.holdSlot(coll)
+	defer close(suite.release)
+
+	_, err := coll.CountDocuments(context.Background(), bson.M{})
+	assert.ErrorIs(suite.T(), err, domain.ErrDatabaseBusy)                  // rejected immediately
+	suite.mockCollection.AssertNumberOfCalls(suite.T(), "CountDocuments", 1)      // rejected call never reached mongo
+}
+
+// tests operations beyond the limit are rejected after the queue timeout
+func (suite *LimitedCollectionTestSuite) TestBeyondLimit_QueueTimeout() {
+
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(1, 20*time.Millisecond))
+	suite.holdSlot(coll)
+	defer close(suite.release)
+
+	result := coll.FindOne(context.Background(), bson.M{})
+	assert.ErrorIs(suite.T(), result.Decode(&domain.Task{}), domain.ErrDatabaseBusy)      // rejected after waiting
+}
+
+// tests queued operations run once a slot frees up
+func (suite *LimitedCollectionTestSuite) TestBeyondLimit_Queued() {
+
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(1, time.Second))
+	suite.holdSlot(coll)
+
+	// free the slot shortly after the second call starts waiting
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(suite.release)
+	}()
+
+	count, err := coll.CountDocuments(context.Background(), bson.M{})
+	assert.NoError(suite.T(), err)                 // queued call succeeds
+	assert.Equal(suite.T(), int64(1), count)       // result comes from the collection
+}
+
+// tests a cursor keeps its slot until the operation context ends
+func (suite *LimitedCollectionTestSuite) TestFind_HoldsSlotWhileCursorIsRead() {
+
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(1, 0))
+	cursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+	suite.mockCollection.On("Find", mock.Anything, bson.M{}, mock.Anything).Return(cursor, nil)
+	close(suite.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := coll.Find(ctx, bson.M{})
+	assert.NoError(suite.T(), err)                                          // cursor returned
+
+	_, err = coll.CountDocuments(context.Background(), bson.M{})
+	assert.ErrorIs(suite.T(), err, domain.ErrDatabaseBusy)                  // slot still held by the cursor
+
+	cancel()        // the repository is done with the cursor
+	assert.Eventually(suite.T(), func() bool {
+		_, err := coll.CountDocuments(context.Background(), bson.M{})
+		return err == nil
+	}, time.Second, 5*time.Millisecond)                                     // slot given back
+}
+
+// tests no wrapping happens without a configured limit
+func (suite *LimitedCollectionTestSuite) TestNoLimit() {
+	coll := NewLimitedCollection(suite.mockCollection, NewOperationLimiter(0, 0))
+	assert.Same(suite.T(), suite.mockCollection, coll)        // collection returned unchanged
+}
+
+// runs the test suite for LimitedCollection
+func TestLimitedCollectionTestSuite(t *testing.T) {
+	suite.Run(t, new(LimitedCollectionTestSuite))
+}
@@ -45,6 +45,17 @@ func (a *MongoCollectionAdapter) CountDocuments(ctx context.Context, filter inte
 	return a.Collection.CountDocuments(ctx, filter, opts...)
 }
 
+// this updates every document in the collection that matches the filter
+func (m *MongoCollectionAdapter) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return m.Collection.UpdateMany(ctx, filter, update, opts...)
+}
 
+// this deletes every document in the collection that matches the filter
+func (m *MongoCollectionAdapter) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return m.Collection.DeleteMany(ctx, filter, opts...)
+}
 
-
+// this runs an aggregation pipeline and returns a cursor over its output
+func (m *MongoCollectionAdapter) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return m.Collection.Aggregate(ctx, pipeline, opts...)
+}
@@ -0,0 +1,119 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type apiKeyRepository struct {
+	collection  domain.MongoCollection
+	timeouts    OperationTimeouts        // per kind of operation, zero values use the defaults
+}
+
+// creates a new api key repository instance
+func NewAPIKeyRepository(limiter *adapters.OperationLimiter, timeouts OperationTimeouts) (domain.APIKeyRepository, error) {
+
+	// connect to the configured database
+	db, err := connectDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	keyCol := db.Collection("api_keys")         // initialize api key collection
+	coll := adapters.NewLimitedCollection(&adapters.MongoCollectionAdapter{Collection: keyCol}, limiter)      // share the operation limiter
+	return &apiKeyRepository{collection: coll, timeouts: timeouts}, nil
+}
+
+// this is used for testing purposes to inject a mock collection
+func NewAPIKeyRepositoryWithCollection(coll domain.MongoCollection) domain.APIKeyRepository {
+	return &apiKeyRepository{collection: coll}
+}
+
+// store api key in database
+func (keyRepo *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+
+	contx, cancel := keyRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+
+	_, err := keyRepo.collection.InsertOne(contx, key)
+
+	return err
+}
+
+// find api key from database by id
+func (keyRepo *apiKeyRepository) GetAPIKeyByID(ctx context.Context, id primitive.ObjectID) (*domain.APIKey, error) {
+
+	var key domain.APIKey
+	contx, cancel := keyRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	err := keyRepo.collection.FindOne(contx, bson.M{"_id": id}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidAPIKey        // unknown keys look the same as wrong ones
+		}
+		return nil, err
+	}
+
+	return &key, nil        // success
+}
+
+// find every api key minted by an admin, newest first
+func (keyRepo *apiKeyRepository) GetAPIKeysByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]domain.APIKey, error) {
+
+	var keys []domain.APIKey
+	contx, cancel := keyRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+	cursor, err := keyRepo.collection.Find(contx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &keys); err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return []domain.APIKey{}, nil
+	}
+
+	return keys, nil        // success
+}
+
+// mark api key revoked in database
+func (keyRepo *apiKeyRepository) RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+
+	contx, cancel := keyRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	result := keyRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+
+	var revoked domain.APIKey
+
+	if err := result.Decode(&revoked); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil        // success
+}
@@ -0,0 +1,127 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	mock_repositories "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// test suite for the APIKeyRepository
+type APIKeyRepositoryTestSuite struct {
+	suite.Suite                                      // embed the suite.Suite type
+	mockCollection *mock_repositories.MockCollection // mock collection for testing
+	repo           domain.APIKeyRepository           // api key repository to be tested
+}
+
+// initializes the test suite
+func (suite *APIKeyRepositoryTestSuite) SetupTest() {
+	suite.mockCollection = new(mock_repositories.MockCollection)          // create a new mock collection
+	suite.repo = NewAPIKeyRepositoryWithCollection(suite.mockCollection)  // create a new api key repository with mock collection
+}
+
+// tests CreateAPIKey method of the APIKeyRepository assigns an id
+func (suite *APIKeyRepositoryTestSuite) TestCreateAPIKey_Success() {
+
+	key := &domain.APIKey{Hash: "hashed", Role: "user"}
+
+	// mock the InsertOne method of the collection
+	suite.mockCollection.
+		On("InsertOne", mock.Anything, key).
+		Return(&mongo.InsertOneResult{}, nil)
+
+	err := suite.repo.CreateAPIKey(context.Background(), key)          // call CreateAPIKey method
+	assert.NoError(suite.T(), err)               // assert no error
+	assert.False(suite.T(), key.ID.IsZero())     // assert ID was generated
+}
+
+// tests GetAPIKeyByID method of the APIKeyRepository for existing key
+func (suite *APIKeyRepositoryTestSuite) TestGetAPIKeyByID_Success() {
+
+	id := primitive.NewObjectID()
+
+	// mock the FindOne method of the collection
+	suite.mockCollection.
+		On("FindOne", mock.Anything, bson.M{"_id": id}).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.APIKey{ID: id, Role: "admin"}})
+
+	key, err := suite.repo.GetAPIKeyByID(context.Background(), id)          // call GetAPIKeyByID method
+	assert.NoError(suite.T(), err)                    // assert no error
+	assert.Equal(suite.T(), "admin", key.Role)        // assert key decoded
+}
+
+// tests GetAPIKeyByID method of the APIKeyRepository for unknown key
+func (suite *APIKeyRepositoryTestSuite) TestGetAPIKeyByID_NotFound() {
+
+	// mock the FindOne method of the collection
+	suite.mockCollection.
+		On("FindOne", mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	key, err := suite.repo.GetAPIKeyByID(context.Background(), primitive.NewObjectID())      // call GetAPIKeyByID method
+	assert.Nil(suite.T(), key)                                         // assert key is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)            // assert error is ErrInvalidAPIKey
+}
+
+// tests GetAPIKeysByOwner method of the APIKeyRepository
+func (suite *APIKeyRepositoryTestSuite) TestGetAPIKeysByOwner_Success() {
+
+	owner := primitive.NewObjectID()
+
+	// create a cursor with two keys
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.APIKey{ID: primitive.NewObjectID(), Prefix: "0a1b2c3d", OwnerID: owner},
+		domain.APIKey{ID: primitive.NewObjectID(), Prefix: "4e5f6a7b", OwnerID: owner},
+	}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"owner_id": owner}, mock.Anything).
+		Return(cursor, nil)
+
+	keys, err := suite.repo.GetAPIKeysByOwner(context.Background(), owner)      // call GetAPIKeysByOwner method
+	assert.NoError(suite.T(), err)                        // assert no error
+	assert.Len(suite.T(), keys, 2)                        // assert both keys decoded
+	assert.Equal(suite.T(), "0a1b2c3d", keys[0].Prefix)   // assert prefix decoded
+}
+
+// tests RevokeAPIKey method of the APIKeyRepository for existing key
+func (suite *APIKeyRepositoryTestSuite) TestRevokeAPIKey_Success() {
+
+	id := primitive.NewObjectID()
+	at := time.Now()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"revoked_at": at}}).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.APIKey{ID: id, RevokedAt: at}})
+
+	err := suite.repo.RevokeAPIKey(context.Background(), id, at)        // call RevokeAPIKey method
+	assert.NoError(suite.T(), err)                // assert no error
+}
+
+// tests RevokeAPIKey method of the APIKeyRepository for unknown key
+func (suite *APIKeyRepositoryTestSuite) TestRevokeAPIKey_NotFound() {
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	err := suite.repo.RevokeAPIKey(context.Background(), primitive.NewObjectID(), time.Now())       // call RevokeAPIKey method
+	assert.ErrorIs(suite.T(), err, domain.ErrAPIKeyNotFound)                  // assert error is ErrAPIKeyNotFound
+}
+
+// suite entry point for running the tests
+func TestAPIKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositoryTestSuite)) // run the test suite
+}
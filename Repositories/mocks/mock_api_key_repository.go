@@ -0,0 +1,57 @@
+package mock_repositories
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mocks the APIKeyRepository interface for testing
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+// mocks CreateAPIKey method
+func (mckr *MockAPIKeyRepository) CreateAPIKey(contx context.Context, key *domain.APIKey) error {
+
+	// call the mocked method and return the result
+	args := mckr.Called(contx, key)
+
+	return args.Error(0)
+}
+
+// mocks GetAPIKeyByID method
+func (mckr *MockAPIKeyRepository) GetAPIKeyByID(contx context.Context, id primitive.ObjectID) (*domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mckr.Called(contx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetAPIKeysByOwner method
+func (mckr *MockAPIKeyRepository) GetAPIKeysByOwner(contx context.Context, ownerID primitive.ObjectID) ([]domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mckr.Called(contx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks RevokeAPIKey method
+func (mckr *MockAPIKeyRepository) RevokeAPIKey(contx context.Context, id primitive.ObjectID, at time.Time) error {
+
+	// call the mocked method and return the result
+	args := mckr.Called(contx, id, at)
+
+	return args.Error(0)
+}
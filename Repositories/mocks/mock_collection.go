@@ -26,8 +26,12 @@ func (m *MockCollection) InsertOne(contx context.Context, doc interface{}, opts
 
 // mocks Find method of the collection
 func (m *MockCollection) Find(contx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
-    args := m.Called(contx, filter)
-    return args.Get(0).(*mongo.Cursor), args.Error(1)
+    args := m.Called(contx, filter, opts)
+    res := args.Get(0)
+    if res == nil {
+        return nil, args.Error(1)
+    }
+    return res.(*mongo.Cursor), args.Error(1)
 }
 
 // mocks FindOne method of the collection
@@ -52,4 +56,33 @@ func (m *MockCollection) DeleteOne(contx context.Context, filter interface{}, op
 func (m *MockCollection) CountDocuments(contx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
     args := m.Called(contx, filter)
     return args.Get(0).(int64), args.Error(1)
-}
\ No newline at end of file
+}
+
+// mocks DeleteMany method of the collection
+func (m *MockCollection) DeleteMany(contx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+    args := m.Called(contx, filter)
+    res := args.Get(0)
+    if res == nil {
+        return nil, args.Error(1)
+    }
+    return res.(*mongo.DeleteResult), args.Error(1)
+}
+
+// mocks UpdateMany method of the collection
+func (m *MockCollection) UpdateMany(contx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+    args := m.Called(contx, filter, update)
+    res := args.Get(0)
+    if res == nil {
+        return nil, args.Error(1)
+    }
+    return res.(*mongo.UpdateResult), args.Error(1)
+}
+// mocks Aggregate method of the collection
+func (m *MockCollection) Aggregate(contx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+    args := m.Called(contx, pipeline)
+    res := args.Get(0)
+    if res == nil {
+        return nil, args.Error(1)
+    }
+    return res.(*mongo.Cursor), args.Error(1)
+}
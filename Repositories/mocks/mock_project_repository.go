@@ -0,0 +1,68 @@
+package mock_repositories
+
+// imports
+import (
+	"context"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mocks the ProjectRepository interface for testing
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+// mocks CreateProject method
+func (mcpr *MockProjectRepository) CreateProject(contx context.Context, project *domain.Project) error {
+
+	// call the mocked method and return the result
+	args := mcpr.Called(contx, project)
+
+	return args.Error(0)
+}
+
+// mocks GetProjectByID method
+func (mcpr *MockProjectRepository) GetProjectByID(contx context.Context, id primitive.ObjectID) (*domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpr.Called(contx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetProjectsByOwner method
+func (mcpr *MockProjectRepository) GetProjectsByOwner(contx context.Context, ownerID primitive.ObjectID) ([]domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpr.Called(contx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks RenameProject method
+func (mcpr *MockProjectRepository) RenameProject(contx context.Context, id primitive.ObjectID, name string) (*domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpr.Called(contx, id, name)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks DeleteProject method
+func (mcpr *MockProjectRepository) DeleteProject(contx context.Context, id primitive.ObjectID) error {
+
+	// call the mocked method and return the result
+	args := mcpr.Called(contx, id)
+
+	return args.Error(0)
+}
@@ -34,6 +34,10 @@ func (m *MockSingleResult) Decode(v interface{}) error {
 			*out.(*domain.User) = *typed
 		case *domain.Task:
 			*out.(*domain.Task) = *typed
+		case *domain.APIKey:
+			*out.(*domain.APIKey) = *typed
+		case *domain.Project:
+			*out.(*domain.Project) = *typed
 		}
 	}
 
@@ -2,8 +2,11 @@ package mock_repositories
 
 // imports
 import (
+	"context"
+	"time"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // mocks the TaskRepository interface for testing
@@ -12,10 +15,10 @@ type MockTaskRepository struct {
 }
 
 // mocks CreateTask method
-func (mctr *MockTaskRepository) CreateTask(task *domain.Task) (*domain.Task, error) {
+func (mctr *MockTaskRepository) CreateTask(contx context.Context, task *domain.Task) (*domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(task)
+	args := mctr.Called(contx, task)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.Task), args.Error(1)
 	}
@@ -23,18 +26,59 @@ func (mctr *MockTaskRepository) CreateTask(task *domain.Task) (*domain.Task, err
 	return nil, args.Error(1)
 }
 
-func (mctr *MockTaskRepository) DeleteTask(id string) error {
+func (mctr *MockTaskRepository) DeleteTask(contx context.Context, id string) error {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(id)
+	args := mctr.Called(contx, id)
 
 	return args.Error(0)
 }
 
-func (mctr *MockTaskRepository) GetAllTasks() ([]domain.Task, error) {
+func (mctr *MockTaskRepository) RestoreTask(contx context.Context, id string) error {
 
 	// call the mocked method and return the result
-	args := mctr.Called()
+	args := mctr.Called(contx, id)
+
+	return args.Error(0)
+}
+
+func (mctr *MockTaskRepository) GetAllTasks(contx context.Context) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetTasksSorted(contx context.Context, field string, ascending bool) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, field, ascending)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetRecentlyUpdated(contx context.Context, ownerID string, limit int64) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ownerID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetUpcomingTasks(contx context.Context, ownerID string, from, to time.Time) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ownerID, from, to)
 	if args.Get(0) != nil {
 		return args.Get(0).([]domain.Task), args.Error(1)
 	}
@@ -42,10 +86,65 @@ func (mctr *MockTaskRepository) GetAllTasks() ([]domain.Task, error) {
 	return nil, args.Error(1)
 }
 
-func (mctr *MockTaskRepository) GetTaskByID(id string) (*domain.Task, error) {
+func (mctr *MockTaskRepository) GetTasksByOwner(contx context.Context, ownerID string, page, pageSize int64) ([]domain.Task, int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ownerID, page, pageSize)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Get(1).(int64), args.Error(2)
+	}
+
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (mctr *MockTaskRepository) GetTasksByUser(contx context.Context, userID string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) CountByOwnerStatus(contx context.Context, ownerIDs []string) (map[string]map[string]int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ownerIDs)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]map[string]int64), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) CountByStatus(contx context.Context) (map[string]int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]int64), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GroupByAssignee(contx context.Context, includeTasks bool) ([]domain.AssigneeTasks, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, includeTasks)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.AssigneeTasks), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetTaskByID(contx context.Context, id string) (*domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(id)
+	args := mctr.Called(contx, id)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.Task), args.Error(1)
 	}
@@ -53,13 +152,149 @@ func (mctr *MockTaskRepository) GetTaskByID(id string) (*domain.Task, error) {
 	return nil, args.Error(1)
 }
 
-func (mctr *MockTaskRepository) UpdateTask(id string, task *domain.Task) (*domain.Task, error) {
+func (mctr *MockTaskRepository) UpdateTask(contx context.Context, id string, task *domain.Task) (*domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(id, task)
+	args := mctr.Called(contx, id, task)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) SearchTasks(contx context.Context, keyword string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, keyword)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetTasksByDueDateRange(contx context.Context, from, to time.Time) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, from, to)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetTasksByIDs(contx context.Context, ids []primitive.ObjectID) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ids)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetOverdueTasks(contx context.Context, ownerID string, now time.Time) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, ownerID, now)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) ArchiveCompletedBefore(contx context.Context, cutoff time.Time) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, cutoff)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mctr *MockTaskRepository) DeleteStatusBefore(contx context.Context, status string, cutoff time.Time) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, status, cutoff)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mctr *MockTaskRepository) ReassignOwner(contx context.Context, fromOwnerID, toOwnerID string) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, fromOwnerID, toOwnerID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mctr *MockTaskRepository) GetTasksByProject(contx context.Context, projectID string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, projectID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) DetachProject(contx context.Context, projectID string) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, projectID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mctr *MockTaskRepository) CountByProject(contx context.Context, projectID string) (*domain.ProjectStats, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, projectID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.ProjectStats), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) SetTaskProject(contx context.Context, taskID string, projectID primitive.ObjectID) (*domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, taskID, projectID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (mctr *MockTaskRepository) ReopenTask(contx context.Context, taskID string) (*domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, taskID)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.Task), args.Error(1)
 	}
 
 	return nil, args.Error(1)
 }
+
+func (mctr *MockTaskRepository) DeleteTasksByProject(contx context.Context, projectID string) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, projectID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mctr *MockTaskRepository) CompleteTasks(contx context.Context, taskIDs, fromStatuses []string) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mctr.Called(contx, taskIDs, fromStatuses)
+
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -2,6 +2,7 @@ package mock_repositories
 
 // imports
 import (
+	"context"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,19 +14,31 @@ type MockUserRepository struct {
 }
 
 // mocks CreateUser method
-func (mctr *MockUserRepository) CreateUser(user *domain.User) error {
+func (mctr *MockUserRepository) CreateUser(contx context.Context, user *domain.User) error {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(user)
+	args := mctr.Called(contx, user)
 
 	return args.Error(0)
 }
 
 // mocks GetByUsername method
-func (mctr *MockUserRepository) GetByUsername(username string) (*domain.User, error) {
+func (mctr *MockUserRepository) GetByUsername(contx context.Context, username string) (*domain.User, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(username)
+	args := mctr.Called(contx, username)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.User), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetByEmail method
+func (mctr *MockUserRepository) GetByEmail(contx context.Context, email string) (*domain.User, error) {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx, email)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.User), args.Error(1)
 	}
@@ -34,19 +47,40 @@ func (mctr *MockUserRepository) GetByUsername(username string) (*domain.User, er
 }
 
 // mocks GetUserCount method
-func (mctr *MockUserRepository) GetUserCount() (int64, error) {
+func (mctr *MockUserRepository) GetUserCount(contx context.Context) (int64, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called()
+	args := mctr.Called(contx)
 
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// mocks GetAdminCount method
+func (mctr *MockUserRepository) GetAdminCount(contx context.Context) (int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// mocks GetAllUsers method
+func (mctr *MockUserRepository) GetAllUsers(contx context.Context, skip, limit int64) ([]domain.User, error) {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx, skip, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.User), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 // mocks GetUserById method
-func (mctr *MockUserRepository) GetUserById(id primitive.ObjectID) (*domain.User, error) {
+func (mctr *MockUserRepository) GetUserById(contx context.Context, id primitive.ObjectID) (*domain.User, error) {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(id)
+	args := mctr.Called(contx, id)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.User), args.Error(1)
 	}
@@ -55,10 +89,37 @@ func (mctr *MockUserRepository) GetUserById(id primitive.ObjectID) (*domain.User
 }
 
 // mocks UpdateRole method
-func (mctr *MockUserRepository) UpdateRole(id primitive.ObjectID, role string) error {
+func (mctr *MockUserRepository) UpdateRole(contx context.Context, id primitive.ObjectID, role string) error {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx, id, role)
+	
+	return args.Error(0)
+}
+
+// mocks SoftDeleteUser method
+func (mctr *MockUserRepository) SoftDeleteUser(contx context.Context, id primitive.ObjectID) error {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx, id)
+	
+	return args.Error(0)
+}
+
+// mocks UpdatePreferences method
+func (mctr *MockUserRepository) UpdatePreferences(contx context.Context, id primitive.ObjectID, prefs domain.Preferences) error {
+	
+	// call the mocked method and return the result
+	args := mctr.Called(contx, id, prefs)
+	
+	return args.Error(0)
+}
+
+// mocks UpdatePassword method
+func (mctr *MockUserRepository) UpdatePassword(contx context.Context, id primitive.ObjectID, hashed string) error {
 	
 	// call the mocked method and return the result
-	args := mctr.Called(id, role)
+	args := mctr.Called(contx, id, hashed)
 	
 	return args.Error(0)
 }
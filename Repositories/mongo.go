@@ -0,0 +1,87 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// database used when MONGO_URI and MONGO_DB are not set
+const (
+	DefaultMongoURI = "mongodb://localhost:27017"
+	DefaultMongoDB  = "taskmanager"
+)
+
+// timeouts of repository operations when none are configured
+const (
+	DefaultReadTimeout       = 5 * time.Second
+	DefaultWriteTimeout      = 10 * time.Second
+	DefaultAggregateTimeout  = 30 * time.Second
+)
+
+// how long repository operations may take by kind of operation, zero uses the default of the kind
+type OperationTimeouts struct {
+	Read       time.Duration        // finds, point reads and counts
+	Write      time.Duration        // inserts, updates and deletes of a single document
+	Aggregate  time.Duration        // aggregations and writes that may touch many documents
+}
+
+// context for a read operation within the caller's context
+func (timeouts OperationTimeouts) read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, timeouts.Read, DefaultReadTimeout)
+}
+
+// context for a single document write within the caller's context
+func (timeouts OperationTimeouts) write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, timeouts.Write, DefaultWriteTimeout)
+}
+
+// context for an aggregation or a write over many documents within the caller's context
+func (timeouts OperationTimeouts) aggregate(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, timeouts.Aggregate, DefaultAggregateTimeout)
+}
+
+// context ending after timeout, or after fallback when no timeout is set, or as soon as parent ends,
+// e.g. when the client of the request went away
+func withTimeout(parent context.Context, timeout, fallback time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = fallback
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// returns the configured MONGO_URI and MONGO_DB, falling back to the defaults
+func MongoSettings() (string, string) {
+
+	viper.BindEnv("MONGO_URI")
+	viper.BindEnv("MONGO_DB")
+
+	uri := viper.GetString("MONGO_URI")
+	if uri == "" {
+		uri = DefaultMongoURI
+	}
+	database := viper.GetString("MONGO_DB")
+	if database == "" {
+		database = DefaultMongoDB
+	}
+
+	return uri, database
+}
+
+// connects to the configured database, a malformed uri is returned as an error
+func connectDatabase() (*mongo.Database, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)       // set timeout
+	defer cancel()
+
+	uri, database := MongoSettings()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Database(database), nil
+}
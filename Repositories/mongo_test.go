@@ -0,0 +1,88 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"testing"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for the database settings
+type MongoSettingsTestSuite struct {
+	suite.Suite
+}
+
+// clears the settings after each test
+func (suite *MongoSettingsTestSuite) TearDownTest() {
+	viper.Reset()
+}
+
+// tests the defaults are used without configuration
+func (suite *MongoSettingsTestSuite) TestMongoSettings_Defaults() {
+
+	viper.Set("MONGO_URI", "")
+	viper.Set("MONGO_DB", "")
+
+	uri, database := MongoSettings()
+	assert.Equal(suite.T(), DefaultMongoURI, uri)           // local server by default
+	assert.Equal(suite.T(), DefaultMongoDB, database)       // taskmanager by default
+}
+
+// tests configured values are used
+func (suite *MongoSettingsTestSuite) TestMongoSettings_Configured() {
+
+	viper.Set("MONGO_URI", "mongodb://db.internal:27017")
+	viper.Set("MONGO_DB", "tasks_test")
+
+	uri, database := MongoSettings()
+	assert.Equal(suite.T(), "mongodb://db.internal:27017", uri)
+	assert.Equal(suite.T(), "tasks_test", database)
+}
+
+// tests unset operation timeouts fall back to the default of their kind
+func (suite *MongoSettingsTestSuite) TestOperationTimeouts_Defaults() {
+
+	timeouts := OperationTimeouts{Write: time.Minute}
+	for _, tc := range []struct {
+		open     func(context.Context) (context.Context, context.CancelFunc)
+		expected time.Duration
+	}{
+		{timeouts.read, DefaultReadTimeout},             // unset read timeout
+		{timeouts.write, time.Minute},                   // configured write timeout
+		{timeouts.aggregate, DefaultAggregateTimeout},   // unset aggregate timeout
+	} {
+		contx, cancel := tc.open(context.Background())
+		deadline, ok := contx.Deadline()
+		cancel()
+		assert.True(suite.T(), ok)                                                         // every operation has a deadline
+		assert.InDelta(suite.T(), tc.expected, time.Until(deadline), float64(time.Second))     // deadline of its kind
+	}
+}
+
+// tests an invalid uri is returned as an error by every constructor instead of exiting
+func (suite *MongoSettingsTestSuite) TestInvalidURI_ReturnsError() {
+
+	viper.Set("MONGO_URI", "not-a-mongo-uri")
+	limiter := adapters.NewOperationLimiter(0, 0)
+
+	taskRepo, err := NewTaskRepository(limiter)
+	assert.Error(suite.T(), err)            // task repository reports the uri
+	assert.Nil(suite.T(), taskRepo)
+
+	userRepo, err := NewUserRepository(limiter, OperationTimeouts{})
+	assert.Error(suite.T(), err)            // user repository reports the uri
+	assert.Nil(suite.T(), userRepo)
+
+	keyRepo, err := NewAPIKeyRepository(limiter, OperationTimeouts{})
+	assert.Error(suite.T(), err)            // api key repository reports the uri
+	assert.Nil(suite.T(), keyRepo)
+}
+
+// runs the test suite for the database settings
+func TestMongoSettingsTestSuite(t *testing.T) {
+	suite.Run(t, new(MongoSettingsTestSuite))
+}
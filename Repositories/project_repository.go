@@ -0,0 +1,142 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type projectRepository struct {
+	collection  domain.MongoCollection
+	timeouts    OperationTimeouts        // per kind of operation, zero values use the defaults
+}
+
+// creates a new project repository instance
+func NewProjectRepository(limiter *adapters.OperationLimiter, timeouts OperationTimeouts) (domain.ProjectRepository, error) {
+
+	// connect to the configured database
+	db, err := connectDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	projectCol := db.Collection("projects")         // initialize project collection
+	coll := adapters.NewLimitedCollection(&adapters.MongoCollectionAdapter{Collection: projectCol}, limiter)      // share the operation limiter
+	return &projectRepository{collection: coll, timeouts: timeouts}, nil
+}
+
+// this is used for testing purposes to inject a mock collection
+func NewProjectRepositoryWithCollection(coll domain.MongoCollection) domain.ProjectRepository {
+	return &projectRepository{collection: coll}
+}
+
+// store project in database
+func (projectRepo *projectRepository) CreateProject(ctx context.Context, project *domain.Project) error {
+
+	contx, cancel := projectRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	// generate new ObjectID if not set
+	if project.ID.IsZero() {
+		project.ID = primitive.NewObjectID()
+	}
+	project.CreatedAt = time.Now()        // record creation time
+
+	_, err := projectRepo.collection.InsertOne(contx, project)
+
+	return err
+}
+
+// find project from database by id
+func (projectRepo *projectRepository) GetProjectByID(ctx context.Context, id primitive.ObjectID) (*domain.Project, error) {
+
+	var project domain.Project
+	contx, cancel := projectRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	err := projectRepo.collection.FindOne(contx, bson.M{"_id": id}).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &project, nil        // success
+}
+
+// find every project of an owner sorted by name, a zero owner finds all projects
+func (projectRepo *projectRepository) GetProjectsByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Project, error) {
+
+	var projects []domain.Project
+	contx, cancel := projectRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	filter := bson.M{}
+	if !ownerID.IsZero() {
+		filter["owner_id"] = ownerID
+	}
+	opts := options.Find().SetSort(stableSort("name", 1))
+	cursor, err := projectRepo.collection.Find(contx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &projects); err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		return []domain.Project{}, nil
+	}
+
+	return projects, nil        // success
+}
+
+// change project name in database and return the renamed project
+func (projectRepo *projectRepository) RenameProject(ctx context.Context, id primitive.ObjectID, name string) (*domain.Project, error) {
+
+	var renamed domain.Project
+	contx, cancel := projectRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)        // to get updated document back
+	err := projectRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"name": name}},
+		opts,
+	).Decode(&renamed)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	return &renamed, nil        // success
+}
+
+// remove project from database
+func (projectRepo *projectRepository) DeleteProject(ctx context.Context, id primitive.ObjectID) error {
+
+	contx, cancel := projectRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	result, err := projectRepo.collection.DeleteOne(contx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrProjectNotFound
+	}
+
+	return nil        // success
+}
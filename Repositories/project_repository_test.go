@@ -0,0 +1,114 @@
+package repositories
+
+// imports
+import (
+	"context"
+	"testing"
+
+	domain "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	mock_repositories "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// test suite for the ProjectRepository
+type ProjectRepositoryTestSuite struct {
+	suite.Suite                                      // embed the suite.Suite type
+	mockCollection *mock_repositories.MockCollection // mock collection for testing
+	repo           domain.ProjectRepository          // project repository to be tested
+}
+
+// initializes the test suite
+func (suite *ProjectRepositoryTestSuite) SetupTest() {
+	suite.mockCollection = new(mock_repositories.MockCollection)           // create a new mock collection
+	suite.repo = NewProjectRepositoryWithCollection(suite.mockCollection)  // create a new project repository with mock collection
+}
+
+// tests CreateProject method of the ProjectRepository assigns an id and creation time
+func (suite *ProjectRepositoryTestSuite) TestCreateProject_Success() {
+
+	project := &domain.Project{Name: "Launch", OwnerID: primitive.NewObjectID()}
+
+	// mock the InsertOne method of the collection
+	suite.mockCollection.
+		On("InsertOne", mock.Anything, project).
+		Return(&mongo.InsertOneResult{}, nil)
+
+	err := suite.repo.CreateProject(context.Background(), project)           // call CreateProject method
+	assert.NoError(suite.T(), err)                     // assert no error
+	assert.False(suite.T(), project.ID.IsZero())       // assert ID was generated
+	assert.False(suite.T(), project.CreatedAt.IsZero())      // assert creation time was set
+}
+
+// tests GetProjectByID method of the ProjectRepository for unknown project
+func (suite *ProjectRepositoryTestSuite) TestGetProjectByID_NotFound() {
+
+	id := primitive.NewObjectID()
+
+	// mock the FindOne method of the collection
+	suite.mockCollection.
+		On("FindOne", mock.Anything, bson.M{"_id": id}).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	project, err := suite.repo.GetProjectByID(context.Background(), id)               // call GetProjectByID method
+	assert.Nil(suite.T(), project)                              // assert project is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)   // assert error is ErrProjectNotFound
+}
+
+// tests GetProjectsByOwner method of the ProjectRepository filters on the owner only when one is given
+func (suite *ProjectRepositoryTestSuite) TestGetProjectsByOwner_Filter() {
+
+	owner := primitive.NewObjectID()
+	mine, _ := mongo.NewCursorFromDocuments([]interface{}{domain.Project{Name: "Launch", OwnerID: owner}}, nil, nil)
+	all, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	// mock the Find method of the collection for both filters
+	suite.mockCollection.On("Find", mock.Anything, bson.M{"owner_id": owner}, mock.Anything).Return(mine, nil)
+	suite.mockCollection.On("Find", mock.Anything, bson.M{}, mock.Anything).Return(all, nil)
+
+	projects, err := suite.repo.GetProjectsByOwner(context.Background(), owner)       // call GetProjectsByOwner method
+	assert.NoError(suite.T(), err)                              // assert no error
+	assert.Len(suite.T(), projects, 1)                          // assert owner's project decoded
+
+	projects, err = suite.repo.GetProjectsByOwner(context.Background(), primitive.NilObjectID)
+	assert.NoError(suite.T(), err)                              // assert no error
+	assert.NotNil(suite.T(), projects)                          // assert empty, not nil
+}
+
+// tests RenameProject method of the ProjectRepository returns the renamed project
+func (suite *ProjectRepositoryTestSuite) TestRenameProject_Success() {
+
+	id := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"name": "Relaunch"}}).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Project{ID: id, Name: "Relaunch"}})
+
+	project, err := suite.repo.RenameProject(context.Background(), id, "Relaunch")    // call RenameProject method
+	assert.NoError(suite.T(), err)                              // assert no error
+	assert.Equal(suite.T(), "Relaunch", project.Name)           // assert renamed project decoded
+}
+
+// tests DeleteProject method of the ProjectRepository for unknown project
+func (suite *ProjectRepositoryTestSuite) TestDeleteProject_NotFound() {
+
+	id := primitive.NewObjectID()
+
+	// mock the DeleteOne method of the collection
+	suite.mockCollection.
+		On("DeleteOne", mock.Anything, bson.M{"_id": id}).
+		Return(&mongo.DeleteResult{DeletedCount: 0}, nil)
+
+	err := suite.repo.DeleteProject(context.Background(), id)                         // call DeleteProject method
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)   // assert error is ErrProjectNotFound
+}
+
+// suite entry point for running the tests
+func TestProjectRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectRepositoryTestSuite)) // run the test suite
+}
@@ -4,7 +4,9 @@ package repositories
 import (
 	"context"
 	"errors"
-	"log"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
@@ -12,40 +14,147 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/errgroup"
 )
 
 type taskRepository struct {
 	collection domain.MongoCollection
+	config     TaskRepositoryConfig
 }
 
-// creates a new user repository instance
-func NewTaskRepository() domain.TaskRepository {
-	// setup mongodb
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)       // set timeout
-	defer cancel()
+// configurable query behaviour of the task repository
+type TaskRepositoryConfig struct {
+	OmitCompletedDescriptions  bool        // leave descriptions of completed tasks out of list results, detail fetches keep them
+	MaxDocumentFraction        float64     // share of MaxBSONDocumentBytes a task write may use (0 = DefaultMaxDocumentFraction)
+	Timeouts                   OperationTimeouts      // per kind of operation, zero values use the defaults
+}
+
+// largest document mongodb stores
+const MaxBSONDocumentBytes = 16 * 1024 * 1024
+
+// share of the mongodb document limit a task may use when none is configured,
+// leaving room for data embedded in the task later
+const DefaultMaxDocumentFraction = 0.5
+
+// largest serialized task write the repository sends to the database
+func (config TaskRepositoryConfig) maxDocumentBytes() int {
+
+	fraction := config.MaxDocumentFraction
+	if fraction <= 0 || fraction > 1 {
+		fraction = DefaultMaxDocumentFraction
+	}
+
+	return int(fraction * MaxBSONDocumentBytes)
+}
+
+// rejects a document whose serialized size is over the configured limit,
+// so oversized writes fail with a domain error instead of a driver failure
+func (taskRepo *taskRepository) checkDocumentSize(doc interface{}) error {
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if len(raw) > taskRepo.config.maxDocumentBytes() {
+		return domain.ErrTaskTooLarge
+	}
+
+	return nil
+}
+
+// task fields clients may sort by, mapped to their stored keys
+var taskSortFields = map[string]string{
+	"due_date":    "due_date",
+	"created_at":  "created_at",
+	"status":      "status",
+	"title":       "title",
+}
+
+// limits a task filter to tasks that are not soft deleted
+func activeTasks(filter bson.M) bson.M {
+	filter["deleted"] = bson.M{"$ne": true}        // also matches tasks stored before soft delete existed
+	return filter
+}
+
+// sort on key with _id as tie breaker, so tasks sharing a value keep the same order across pages
+func stableSort(key string, direction int) bson.D {
+	return bson.D{{Key: key, Value: direction}, {Key: "_id", Value: direction}}
+}
 
-	// connect
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+// stored keys of every task field, so a projection can include them all
+func taskFieldKeys() []string {
+
+	var keys []string
+	t := reflect.TypeOf(domain.Task{})
+	for i := 0; i < t.NumField(); i++ {
+		if key := strings.Split(t.Field(i).Tag.Get("bson"), ",")[0]; key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// keeps every field but drops the description of completed tasks (needs mongodb 4.4+)
+// find projections cannot mix exclusions with expressions, so every other field is included by name
+func listProjection() bson.M {
+
+	projection := bson.M{}
+	for _, key := range taskFieldKeys() {
+		projection[key] = 1
+	}
+	projection["description"] = bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$status", "completed"}},
+		"$$REMOVE",
+		"$description",
+	}}
+
+	return projection
+}
+
+// creates a new task repository instance
+func NewTaskRepository(limiter *adapters.OperationLimiter) (domain.TaskRepository, error) {
+	return NewTaskRepositoryWithConfig(limiter, TaskRepositoryConfig{})
+}
+
+// creates a new task repository instance with custom query behaviour
+func NewTaskRepositoryWithConfig(limiter *adapters.OperationLimiter, config TaskRepositoryConfig) (domain.TaskRepository, error) {
+
+	// connect to the configured database
+	db, err := connectDatabase()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	db := client.Database("taskmanager")
 	taskCol := db.Collection("tasks")         // initialize task collection
-	return &taskRepository{&adapters.MongoCollectionAdapter{Collection: taskCol}}
+	coll := adapters.NewLimitedCollection(&adapters.MongoCollectionAdapter{Collection: taskCol}, limiter)      // share the operation limiter
+	return &taskRepository{collection: coll, config: config}, nil
 }
 
 // this is used for testing purposes to inject a mock collection
 func NewTaskRepositoryWithCollection(coll domain.MongoCollection) domain.TaskRepository {
-	return &taskRepository{coll}
+	return NewTaskRepositoryWithCollectionAndConfig(coll, TaskRepositoryConfig{})
 }
 
-func (taskRepo *taskRepository) CreateTask(task *domain.Task) (*domain.Task, error) {
+// this is used for testing purposes to inject a mock collection with custom query behaviour
+func NewTaskRepositoryWithCollectionAndConfig(coll domain.MongoCollection, config TaskRepositoryConfig) domain.TaskRepository {
+	return &taskRepository{collection: coll, config: config}
+}
+
+func (taskRepo *taskRepository) CreateTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)     // set timeout
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)     // set timeout
 	defer cancel()
 
 	task.ID = primitive.NewObjectID()                         // create a unique id for the new task
+	task.CreatedAt = time.Now()                               // record creation time
+	task.UpdatedAt = task.CreatedAt                           // new task counts as just updated
+	if task.Status == "completed" {
+		task.CompletedAt = task.CreatedAt                     // created already done
+	}
+	if err := taskRepo.checkDocumentSize(task); err != nil {
+		return nil, err
+	}
 	_, err := taskRepo.collection.InsertOne(contx, task)      // create the new task with error handling
 	if err != nil {
         return nil, err
@@ -54,9 +163,9 @@ func (taskRepo *taskRepository) CreateTask(task *domain.Task) (*domain.Task, err
 	return task, nil       // return the new created task and nil
 }
 
-func (taskRepo *taskRepository) DeleteTask(taskID string) error {
+func (taskRepo *taskRepository) DeleteTask(ctx context.Context, taskID string) error {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)        // set timeout
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)       // convert string id to mongodb's id format with error handling 
@@ -64,30 +173,379 @@ func (taskRepo *taskRepository) DeleteTask(taskID string) error {
 		return domain.ErrInvalidTaskID
 	}
 
-	result, err := taskRepo.collection.DeleteOne(contx, bson.M{"_id": objID})       // delete the task with error handling
-	if err != nil {
+	// tombstone the task, the document stays for audit history
+	result := taskRepo.collection.FindOneAndUpdate(
+		contx,
+		activeTasks(bson.M{"_id": objID}),
+		bson.M{"$set": bson.M{"deleted": true, "deleted_at": time.Now()}},
+	)
+
+	var deleted domain.Task
+
+	if err := result.Decode(&deleted); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrTaskNotFound        // missing or already deleted
+		}
 		return err
 	}
 
-	if result == nil {
-    	return errors.New("delete error")
+	return nil
+}
+
+func (taskRepo *taskRepository) RestoreTask(ctx context.Context, taskID string) error {
+
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(taskID)       // convert string id to mongodb's id format with error handling
+	if err != nil {
+		return domain.ErrInvalidTaskID
 	}
 
-	// verify task deleted
-	if result.DeletedCount == 0 {
-		return domain.ErrTaskNotFound
+	// clear the tombstone, restoring a task that is not deleted changes nothing
+	result := taskRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": objID},
+		bson.M{"$set": bson.M{"deleted": false}, "$unset": bson.M{"deleted_at": ""}},
+	)
+
+	var restored domain.Task
+
+	if err := result.Decode(&restored); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrTaskNotFound
+		}
+		return err
 	}
 
 	return nil
 }
 
-func (taskRepo *taskRepository) GetAllTasks() ([]domain.Task, error) {
+func (taskRepo *taskRepository) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	
+	// newest tasks first by default
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+
+	return taskRepo.findTasks(ctx, activeTasks(bson.M{}), opts)
+}
+
+func (taskRepo *taskRepository) GetTasksSorted(ctx context.Context, field string, ascending bool) ([]domain.Task, error) {
+
+	// only whitelisted fields are ever put into the sort document
+	key, ok := taskSortFields[field]
+	if !ok {
+		return nil, domain.ErrInvalidSortField
+	}
+
+	direction := -1
+	if ascending {
+		direction = 1
+	}
+	opts := options.Find().SetSort(stableSort(key, direction))
+
+	return taskRepo.findTasks(ctx, activeTasks(bson.M{}), opts)
+}
+
+func (taskRepo *taskRepository) SearchTasks(ctx context.Context, keyword string) ([]domain.Task, error) {
+
+	// the keyword is matched literally, so "." or "*" only match themselves
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(keyword), Options: "i"}
+	filter := activeTasks(bson.M{"$or": bson.A{
+		bson.M{"title": pattern},
+		bson.M{"description": pattern},
+	}})
+
+	// newest tasks first, like the full listing
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+
+	return taskRepo.findTasks(ctx, filter, opts)
+}
+
+func (taskRepo *taskRepository) GetTasksByDueDateRange(ctx context.Context, from, to time.Time) ([]domain.Task, error) {
+
+	// a zero bound leaves that side of the window open
+	window := bson.M{}
+	if !from.IsZero() {
+		window["$gte"] = from
+	}
+	if !to.IsZero() {
+		window["$lte"] = to
+	}
+	filter := bson.M{}
+	if len(window) > 0 {
+		filter["due_date"] = window
+	}
+
+	// soonest first, like the upcoming tasks listing
+	opts := options.Find().SetSort(stableSort("due_date", 1))
+
+	return taskRepo.findTasks(ctx, activeTasks(filter), opts)
+}
+
+func (taskRepo *taskRepository) GetRecentlyUpdated(ctx context.Context, ownerID string, limit int64) ([]domain.Task, error) {
+
+	filter, err := ownerFilter(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// newest modifications first, at most limit tasks
+	opts := options.Find().
+		SetSort(stableSort("updated_at", -1)).
+		SetLimit(limit)
+
+	return taskRepo.findTasks(ctx, activeTasks(filter), opts)
+}
+
+func (taskRepo *taskRepository) GetUpcomingTasks(ctx context.Context, ownerID string, from, to time.Time) ([]domain.Task, error) {
+
+	filter, err := ownerFilter(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// due within the window and not completed or archived, soonest first
+	filter["due_date"] = bson.M{"$gte": from, "$lte": to}
+	filter["status"] = bson.M{"$nin": []string{"completed", "archived"}}
+	opts := options.Find().SetSort(stableSort("due_date", 1))
+
+	return taskRepo.findTasks(ctx, activeTasks(filter), opts)
+}
+
+func (taskRepo *taskRepository) GetOverdueTasks(ctx context.Context, ownerID string, now time.Time) ([]domain.Task, error) {
+
+	filter, err := ownerFilter(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// past due and not completed or archived, longest overdue first
+	filter["due_date"] = bson.M{"$lt": now}
+	filter["status"] = bson.M{"$nin": []string{"completed", "archived"}}
+	opts := options.Find().SetSort(stableSort("due_date", 1))
+
+	return taskRepo.findTasks(ctx, activeTasks(filter), opts)
+}
+
+// get one page of a user's tasks together with how many tasks they have
+func (taskRepo *taskRepository) GetTasksByOwner(ctx context.Context, ownerID string, page, pageSize int64) ([]domain.Task, int64, error) {
+
+	objID, err := primitive.ObjectIDFromHex(ownerID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, 0, domain.ErrInvalidUserID
+	}
+	filter := activeTasks(bson.M{"owner_id": objID})        // shared by the page and the count
+
+	// newest tasks first, like the full listing
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+	if pageSize > 0 {
+		if page < 1 {
+			page = 1
+		}
+		opts.SetSkip((page - 1) * pageSize).SetLimit(pageSize)        // only the requested page
+	}
+
+	// the page and the total are independent, so run both queries at once
+	var tasks []domain.Task
+	var total int64
+	var group errgroup.Group
+	group.Go(func() error {
+		var err error
+		tasks, err = taskRepo.findTasks(ctx, filter, opts)
+		return err
+	})
+	group.Go(func() error {
+		var err error
+		total, err = taskRepo.countTasks(ctx, filter)
+		return err
+	})
+	if err := group.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// count documents matching a filter
+func (taskRepo *taskRepository) countTasks(ctx context.Context, filter interface{}) (int64, error) {
+
+	contx, cancel := taskRepo.config.Timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	return taskRepo.collection.CountDocuments(contx, filter)
+}
+
+// get tasks assigned to a user
+func (taskRepo *taskRepository) GetTasksByUser(ctx context.Context, userID string) ([]domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	// newest tasks first, like the full listing
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+
+	return taskRepo.findTasks(ctx, activeTasks(bson.M{"assigned_to": objID}), opts)
+}
+
+// statuses counted by CountByStatus, each one is listed even without tasks
+var countedStatuses = []string{"pending", "in_progress", "completed"}
+
+// one row of the status aggregation
+type statusCount struct {
+	Status  string  `bson:"_id"`
+	Count   int64   `bson:"count"`
+}
+
+// count active tasks per status in a single aggregation, statuses without tasks count 0
+func (taskRepo *taskRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout
+	defer cancel()
+
+	// only documents' statuses are grouped, no task is fetched
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: activeTasks(bson.M{"status": bson.M{"$in": countedStatuses}})}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := taskRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)
+
+	var rows []statusCount
+	if err := cursor.All(contx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(countedStatuses))
+	for _, status := range countedStatuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// one row of the owner and status aggregation
+type ownerStatusCount struct {
+	ID struct {
+		OwnerID  primitive.ObjectID  `bson:"owner_id"`
+		Status   string              `bson:"status"`
+	} `bson:"_id"`
+	Count  int64  `bson:"count"`
+}
+
+// count tasks per owner and status in a single aggregation
+func (taskRepo *taskRepository) CountByOwnerStatus(ctx context.Context, ownerIDs []string) (map[string]map[string]int64, error) {
+
+	objIDs := make([]primitive.ObjectID, 0, len(ownerIDs))
+	for _, ownerID := range ownerIDs {
+		objID, err := primitive.ObjectIDFromHex(ownerID)      // convert string id to mongodb's format with error handling
+		if err != nil {
+			return nil, domain.ErrInvalidUserID
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout
+	defer cancel()
+
+	// group the requested owners' tasks by owner and status
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: activeTasks(bson.M{"owner_id": bson.M{"$in": objIDs}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"owner_id": "$owner_id", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := taskRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)
+
+	var rows []ownerStatusCount
+	if err := cursor.All(contx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]map[string]int64)
+	for _, row := range rows {
+		owner := row.ID.OwnerID.Hex()
+		if counts[owner] == nil {
+			counts[owner] = make(map[string]int64)
+		}
+		counts[owner][row.ID.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// count assigned tasks per assignee in a single aggregation, unassigned tasks are left out
+func (taskRepo *taskRepository) GroupByAssignee(ctx context.Context, includeTasks bool) ([]domain.AssigneeTasks, error) {
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout
+	defer cancel()
+
+	group := bson.M{"_id": "$assigned_to", "count": bson.M{"$sum": 1}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: activeTasks(bson.M{"assigned_to": bson.M{"$nin": bson.A{nil, primitive.NilObjectID}}})}},
+	}
+	if includeTasks {
+		// newest tasks first inside every group, like the other listings
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: stableSort("created_at", -1)}})
+		group["tasks"] = bson.M{"$push": "$$ROOT"}
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: group}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},      // busiest first, ties in a stable order
+	)
+	cursor, err := taskRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)
+
+	groups := []domain.AssigneeTasks{}
+	if err := cursor.All(contx, &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// builds the filter limiting a listing to one owner, an empty owner id matches every task
+func ownerFilter(ownerID string) (bson.M, error) {
+
+	if ownerID == "" {
+		return bson.M{}, nil
+	}
+	objID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	return bson.M{"owner_id": objID}, nil
+}
+
+// finds all tasks matching the filter and decodes them
+func (taskRepo *taskRepository) findTasks(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]domain.Task, error) {
 	
 	var allTasks []domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := taskRepo.config.Timeouts.read(ctx)        // set timeout
 	defer cancel()
 
-	cursor, err := taskRepo.collection.Find(contx, bson.M{})      // find all documents in the collection
+	// list views rarely need the details of finished tasks
+	if taskRepo.config.OmitCompletedDescriptions {
+		opts = append(opts, options.Find().SetProjection(listProjection()))
+	}
+
+	cursor, err := taskRepo.collection.Find(contx, filter, opts...)      // find matching documents in the collection
 	if err != nil {
 		return nil, err
 	}
@@ -110,10 +568,10 @@ func (taskRepo *taskRepository) GetAllTasks() ([]domain.Task, error) {
 	return allTasks, nil
 }
 
-func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error) {
+func (taskRepo *taskRepository) GetTaskByID(ctx context.Context, taskID string) (*domain.Task, error) {
 	
 	var task domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := taskRepo.config.Timeouts.read(ctx)        // set timeout
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
@@ -121,7 +579,7 @@ func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error)
 		return nil, domain.ErrInvalidTaskID
 	}
 
-	err = taskRepo.collection.FindOne(contx, bson.M{"_id": objID}).Decode(&task)       // check if task exists
+	err = taskRepo.collection.FindOne(contx, activeTasks(bson.M{"_id": objID})).Decode(&task)       // check if task exists
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrTaskNotFound
@@ -132,10 +590,19 @@ func (taskRepo *taskRepository) GetTaskByID(taskID string) (*domain.Task, error)
 	return &task, nil
 }
 
-func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Task) (*domain.Task, error) {
+func (taskRepo *taskRepository) GetTasksByIDs(ctx context.Context, ids []primitive.ObjectID) ([]domain.Task, error) {
+
+	if len(ids) == 0 {
+		return []domain.Task{}, nil
+	}
+
+	return taskRepo.findTasks(ctx, activeTasks(bson.M{"_id": bson.M{"$in": ids}}))
+}
+
+func (taskRepo *taskRepository) UpdateTask(ctx context.Context, taskID string, taskUpdate *domain.Task) (*domain.Task, error) {
 	
 	var updatedTask domain.Task
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)        // set timeout
 	defer cancel()
 
 	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling 
@@ -156,14 +623,38 @@ func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Tas
 	if !taskUpdate.DueDate.IsZero() {
 		setFields["due_date"] = taskUpdate.DueDate
 	}
+	if taskUpdate.DueDateTZ != "" {
+		setFields["due_date_tz"] = taskUpdate.DueDateTZ
+	}
 	if taskUpdate.Status != "" {
 		setFields["status"] = taskUpdate.Status
 	}
+	if taskUpdate.Priority != "" {
+		setFields["priority"] = taskUpdate.Priority
+	}
+	if !taskUpdate.AssignedTo.IsZero() {
+		setFields["assigned_to"] = taskUpdate.AssignedTo
+	}
+	if taskUpdate.DependsOn != nil {
+		setFields["depends_on"] = taskUpdate.DependsOn        // an empty list clears the dependencies
+	}
+	if !taskUpdate.ProjectID.IsZero() {
+		setFields["project_id"] = taskUpdate.ProjectID
+	}
 
 	// stop if nothing valid to update
 	if len(setFields) == 0 {
 		return nil, errors.New("no valid fields provided for update")
 	}
+	now := time.Now()
+	setFields["updated_at"] = now        // record modification time
+	if err := taskRepo.checkDocumentSize(setFields); err != nil {        // the changed fields alone must fit
+		return nil, err
+	}
+	var changes interface{} = update
+	if taskUpdate.Status == "completed" {
+		changes = completingUpdate(setFields, now)        // completed_at depends on the stored status
+	}
  
 	opts := options.FindOneAndUpdate().         // to get updated document back
 		SetReturnDocument(options.After)
@@ -171,8 +662,8 @@ func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Tas
 	// perform update and get the updated task
 	err = taskRepo.collection.FindOneAndUpdate(
 		contx,
-		bson.M{"_id": objID},
-		update,
+		activeTasks(bson.M{"_id": objID}),        // deleted tasks cannot be edited
+		changes,
 		opts,
 	).Decode(&updatedTask)
 
@@ -186,3 +677,310 @@ func (taskRepo *taskRepository) UpdateTask(taskID string, taskUpdate *domain.Tas
 	return &updatedTask, nil       // return the updated task and nil
 }
 
+func (taskRepo *taskRepository) ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	// completed tasks whose completion is older than the cutoff
+	filter := bson.M{
+		"status":       "completed",
+		"completed_at": bson.M{"$lt": cutoff},
+	}
+	update := bson.M{"$set": bson.M{"status": "archived", "updated_at": time.Now()}}
+
+	result, err := taskRepo.collection.UpdateMany(contx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("update error")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// permanently delete tasks in a status whose last change is older than the cutoff, soft deleted ones included
+func (taskRepo *taskRepository) DeleteStatusBefore(ctx context.Context, status string, cutoff time.Time) (int64, error) {
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	filter := bson.M{
+		"status":     status,
+		"updated_at": bson.M{"$lt": cutoff},
+	}
+
+	result, err := taskRepo.collection.DeleteMany(contx, filter)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("delete error")
+	}
+
+	return result.DeletedCount, nil
+}
+
+// move every task of one owner to another
+func (taskRepo *taskRepository) ReassignOwner(ctx context.Context, fromOwnerID, toOwnerID string) (int64, error) {
+
+	fromObjID, err := primitive.ObjectIDFromHex(fromOwnerID)      // convert string ids to mongodb's format with error handling
+	if err != nil {
+		return 0, domain.ErrInvalidUserID
+	}
+	toObjID, err := primitive.ObjectIDFromHex(toOwnerID)
+	if err != nil {
+		return 0, domain.ErrInvalidUserID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	filter := bson.M{"owner_id": fromObjID}        // deleted tasks move too, so a restore gives them to the new owner
+	update := bson.M{
+		"$set":   bson.M{"owner_id": toObjID, "updated_at": time.Now()},
+		"$unset": bson.M{"project_id": ""},        // projects belong to the previous owner
+	}
+
+	result, err := taskRepo.collection.UpdateMany(contx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("update error")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// get the tasks of a project
+func (taskRepo *taskRepository) GetTasksByProject(ctx context.Context, projectID string) ([]domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	// newest tasks first, like the full listing
+	opts := options.Find().SetSort(stableSort("created_at", -1))
+
+	return taskRepo.findTasks(ctx, activeTasks(bson.M{"project_id": objID}), opts)
+}
+
+// take every task out of a project, e.g. before the project is deleted
+func (taskRepo *taskRepository) DetachProject(ctx context.Context, projectID string) (int64, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return 0, domain.ErrInvalidProjectID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	filter := bson.M{"project_id": objID}        // deleted tasks are detached too, so a restore does not bring back a missing project
+	update := bson.M{"$unset": bson.M{"project_id": ""}, "$set": bson.M{"updated_at": time.Now()}}
+
+	result, err := taskRepo.collection.UpdateMany(contx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("update error")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// one row of the status and priority aggregation
+type statusPriorityCount struct {
+	ID struct {
+		Status    string  `bson:"status"`
+		Priority  string  `bson:"priority"`
+	} `bson:"_id"`
+	Count  int64  `bson:"count"`
+}
+
+// count the active tasks of a project per status and per priority in a single aggregation
+func (taskRepo *taskRepository) CountByProject(ctx context.Context, projectID string) (*domain.ProjectStats, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout
+	defer cancel()
+
+	// group the project's tasks by status and priority, both breakdowns are summed from these rows
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: activeTasks(bson.M{"project_id": objID})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"status": "$status", "priority": "$priority"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := taskRepo.collection.Aggregate(contx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)
+
+	var rows []statusPriorityCount
+	if err := cursor.All(contx, &rows); err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ProjectStats{ByStatus: map[string]int64{}, ByPriority: map[string]int64{}}
+	for _, row := range rows {
+		stats.ByStatus[row.ID.Status] += row.Count
+		stats.ByPriority[row.ID.Priority] += row.Count
+	}
+
+	return stats, nil
+}
+
+// put a task in a project, a zero project takes it out of its project
+func (taskRepo *taskRepository) SetTaskProject(ctx context.Context, taskID string, projectID primitive.ObjectID) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"project_id": projectID, "updated_at": time.Now()}}
+	if projectID.IsZero() {
+		update = bson.M{"$unset": bson.M{"project_id": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	opts := options.FindOneAndUpdate().         // to get updated document back
+		SetReturnDocument(options.After)
+
+	var moved domain.Task
+	err = taskRepo.collection.FindOneAndUpdate(
+		contx,
+		activeTasks(bson.M{"_id": objID}),        // deleted tasks cannot be moved
+		update,
+		opts,
+	).Decode(&moved)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &moved, nil
+}
+
+// move a completed task back to in_progress, its completion time is cleared for the next completion
+func (taskRepo *taskRepository) ReopenTask(ctx context.Context, taskID string) (*domain.Task, error) {
+
+	objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().         // to get updated document back
+		SetReturnDocument(options.After)
+
+	now := time.Now()
+	update := bson.M{
+		"$set":   bson.M{"status": "in_progress", "updated_at": now},
+		"$unset": bson.M{"completed_at": ""},
+		"$push":  bson.M{"status_history": domain.StatusChange{From: "completed", To: "in_progress", At: now}},      // keep the reopening on record
+	}
+
+	var reopened domain.Task
+	err = taskRepo.collection.FindOneAndUpdate(
+		contx,
+		activeTasks(bson.M{"_id": objID, "status": "completed"}),        // a task changed meanwhile is not reopened
+		update,
+		opts,
+	).Decode(&reopened)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTaskNotCompleted
+		}
+		return nil, err
+	}
+
+	return &reopened, nil
+}
+
+// update pipeline setting the fields and starting the auto-archive clock at now, unless the
+// stored task is already completed - the pipeline sees the stored status, and the values are
+// wrapped in $literal so one like "$title" is not read as a field path
+func completingUpdate(setFields bson.M, now time.Time) []bson.M {
+
+	stage := bson.M{}
+	for key, value := range setFields {
+		stage[key] = bson.M{"$literal": value}
+	}
+	stage["completed_at"] = bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "completed"}}, "$completed_at", now}}
+
+	return []bson.M{{"$set": stage}}
+}
+
+// complete the listed active tasks that are in one of the statuses, others are left untouched
+func (taskRepo *taskRepository) CompleteTasks(ctx context.Context, taskIDs, fromStatuses []string) (int64, error) {
+
+	objIDs := make([]primitive.ObjectID, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		objID, err := primitive.ObjectIDFromHex(taskID)      // convert string id to mongodb's format with error handling
+		if err != nil {
+			return 0, domain.ErrInvalidTaskID
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	now := time.Now()
+	filter := activeTasks(bson.M{"_id": bson.M{"$in": objIDs}, "status": bson.M{"$in": fromStatuses}})
+	update := bson.M{"$set": bson.M{"status": "completed", "completed_at": now, "updated_at": now}}      // start of the auto-archive clock
+
+	result, err := taskRepo.collection.UpdateMany(contx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("update error")
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// soft delete every active task of a project, restored tasks come back without the project
+func (taskRepo *taskRepository) DeleteTasksByProject(ctx context.Context, projectID string) (int64, error) {
+
+	objID, err := primitive.ObjectIDFromHex(projectID)      // convert string id to mongodb's format with error handling
+	if err != nil {
+		return 0, domain.ErrInvalidProjectID
+	}
+
+	contx, cancel := taskRepo.config.Timeouts.aggregate(ctx)        // set timeout, may touch many tasks
+	defer cancel()
+
+	filter := activeTasks(bson.M{"project_id": objID})        // already deleted tasks keep their deletion time
+	update := bson.M{"$unset": bson.M{"project_id": ""}, "$set": bson.M{"deleted": true, "deleted_at": time.Now()}}
+
+	result, err := taskRepo.collection.UpdateMany(contx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil {
+		return 0, errors.New("update error")
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -4,10 +4,12 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	domain "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/adapters"
 	mock_repositories "github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,6 +17,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // test suite for the TaskRepository
@@ -30,6 +33,99 @@ func (suite *TaskRepositoryTestSuite) SetupTest() {
 	suite.repo = NewTaskRepositoryWithCollection(suite.mockCollection) // create a new task repository with mock collection
 }
 
+// matches an operation context that ends about timeout from now
+func deadlineIn(timeout time.Duration) interface{} {
+	return mock.MatchedBy(func(contx context.Context) bool {
+		deadline, ok := contx.Deadline()
+		left := time.Until(deadline)
+		return ok && left <= timeout && left > timeout-time.Second
+	})
+}
+
+// tests reads, single writes and aggregations each run with their own configured timeout
+func (suite *TaskRepositoryTestSuite) TestTimeouts_PerOperationKind() {
+
+	repo := NewTaskRepositoryWithCollectionAndConfig(suite.mockCollection, TaskRepositoryConfig{
+		Timeouts: OperationTimeouts{Read: time.Minute, Write: 2 * time.Minute, Aggregate: 3 * time.Minute},
+	})
+	taskID := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	// each call only matches when its context has the timeout of its kind
+	suite.mockCollection.
+		On("FindOne", deadlineIn(time.Minute), mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: taskID}})
+	suite.mockCollection.
+		On("FindOneAndUpdate", deadlineIn(2*time.Minute), mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: taskID}})
+	suite.mockCollection.
+		On("UpdateMany", deadlineIn(3*time.Minute), mock.Anything, mock.Anything).
+		Return(&mongo.UpdateResult{ModifiedCount: 1}, nil)
+	suite.mockCollection.
+		On("Aggregate", deadlineIn(3*time.Minute), mock.Anything).
+		Return(cursor, nil)
+
+	_, err := repo.GetTaskByID(context.Background(), taskID.Hex())                        // point read
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), repo.DeleteTask(context.Background(), taskID.Hex()))        // single document write
+	_, err = repo.ArchiveCompletedBefore(context.Background(), time.Now())                // write over many documents
+	assert.NoError(suite.T(), err)
+	_, err = repo.CountByProject(context.Background(), primitive.NewObjectID().Hex())     // aggregation
+	assert.NoError(suite.T(), err)
+	suite.mockCollection.AssertExpectations(suite.T())              // every kind used its own timeout
+}
+
+// tests a call whose caller went away is aborted instead of waiting for the database
+func (suite *TaskRepositoryTestSuite) TestCancelledContext_AbortsCall() {
+
+	// every operation slot is taken and waiting for one may take a minute
+	limiter := adapters.NewOperationLimiter(1, time.Minute)
+	assert.NoError(suite.T(), limiter.Acquire(context.Background()))
+	defer limiter.Release()
+	repo := NewTaskRepositoryWithCollection(adapters.NewLimitedCollection(suite.mockCollection, limiter))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()        // e.g. the client disconnected
+
+	start := time.Now()
+	task, err := repo.GetTaskByID(ctx, primitive.NewObjectID().Hex())
+	assert.Nil(suite.T(), task)                                       // assert task is nil
+	assert.ErrorIs(suite.T(), err, context.Canceled)                  // assert the cancellation is reported
+	assert.Less(suite.T(), time.Since(start), time.Second)            // assert the call did not wait for a slot
+	suite.mockCollection.AssertNotCalled(suite.T(), "FindOne", mock.Anything, mock.Anything)
+}
+
+// tests CreateTask method of the TaskRepository rejects a task over the document size limit before inserting it
+func (suite *TaskRepositoryTestSuite) TestCreateTask_TooLarge() {
+
+	// about 1.6kB may be stored
+	repo := NewTaskRepositoryWithCollectionAndConfig(suite.mockCollection, TaskRepositoryConfig{MaxDocumentFraction: 0.0001})
+
+	task, err := repo.CreateTask(context.Background(), &domain.Task{Title: "huge", Description: strings.Repeat("ü", 1000)})      // 2000 bytes of description
+	assert.Nil(suite.T(), task)                                     // assert task is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskTooLarge)          // assert error is ErrTaskTooLarge
+	suite.mockCollection.AssertNotCalled(suite.T(), "InsertOne", mock.Anything, mock.Anything)
+}
+
+// tests UpdateTask method of the TaskRepository rejects changed fields over the document size limit
+func (suite *TaskRepositoryTestSuite) TestUpdateTask_TooLarge() {
+
+	repo := NewTaskRepositoryWithCollectionAndConfig(suite.mockCollection, TaskRepositoryConfig{MaxDocumentFraction: 0.0001})
+
+	task, err := repo.UpdateTask(context.Background(), primitive.NewObjectID().Hex(), &domain.Task{Description: strings.Repeat("ü", 1000)})
+	assert.Nil(suite.T(), task)                                     // assert task is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskTooLarge)          // assert error is ErrTaskTooLarge
+	suite.mockCollection.AssertNotCalled(suite.T(), "FindOneAndUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests the document size limit falls back to the default share of the mongodb limit
+func (suite *TaskRepositoryTestSuite) TestMaxDocumentBytes_Default() {
+
+	assert.Equal(suite.T(), MaxBSONDocumentBytes/2, TaskRepositoryConfig{}.maxDocumentBytes())                        // unset
+	assert.Equal(suite.T(), MaxBSONDocumentBytes/2, TaskRepositoryConfig{MaxDocumentFraction: 2}.maxDocumentBytes())  // out of range
+	assert.Equal(suite.T(), MaxBSONDocumentBytes/4, TaskRepositoryConfig{MaxDocumentFraction: 0.25}.maxDocumentBytes())
+}
+
 // tests CreateTask method of the TaskRepository
 func (suite *TaskRepositoryTestSuite) TestCreateTask_Success() {
 
@@ -48,7 +144,7 @@ func (suite *TaskRepositoryTestSuite) TestCreateTask_Success() {
 			return ok
 		})).Return(&mongo.InsertOneResult{}, nil)
 
-	result, err := suite.repo.CreateTask(task) // call CreateTask method
+	result, err := suite.repo.CreateTask(context.Background(), task) // call CreateTask method
 	assert.NoError(suite.T(), err)             // assert no error
 	assert.NotNil(suite.T(), result)           // assert result is not nil
 	assert.NotEmpty(suite.T(), result.ID)      // assert ID is not empty
@@ -68,7 +164,7 @@ func (suite *TaskRepositoryTestSuite) TestCreateTask_Error() {
 		On("InsertOne", mock.Anything, mock.Anything).
 		Return(nil, errors.New("insert error"))
 
-	result, err := suite.repo.CreateTask(task)        // call CreateTask method
+	result, err := suite.repo.CreateTask(context.Background(), task)        // call CreateTask method
 	assert.Nil(suite.T(), result)                     // assert result is nil
 	assert.EqualError(suite.T(), err, "insert error") // assert error message
 }
@@ -88,11 +184,565 @@ func (suite *TaskRepositoryTestSuite) TestCreateTask_ContextTimeout() {
         On("InsertOne", mock.Anything, task).
         Return(nil, context.DeadlineExceeded)
 
-    result, err := suite.repo.CreateTask(task)       // call CreateTask method
+    result, err := suite.repo.CreateTask(context.Background(), task)       // call CreateTask method
 	assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)      // assert error is context deadline exceeded
     assert.Nil(suite.T(), result)                    // assert result is nil
 }
 
+// matches find options whose merged sort document equals the expected one
+func sortedBy(expected bson.D) interface{} {
+	return mock.MatchedBy(func(opts []*options.FindOptions) bool {
+		return assert.ObjectsAreEqual(expected, options.MergeFindOptions(opts...).Sort)
+	})
+}
+
+// tests GetAllTasks method of the TaskRepository sorts newest first by default
+func (suite *TaskRepositoryTestSuite) TestGetAllTasks_DefaultSort() {
+
+	// create a cursor with two tasks
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.Task{ID: primitive.NewObjectID(), Title: "second"},
+		domain.Task{ID: primitive.NewObjectID(), Title: "first"},
+	}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, sortedBy(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetAllTasks(context.Background())          // call GetAllTasks method
+	assert.NoError(suite.T(), err)                  // assert no error
+	assert.Len(suite.T(), tasks, 2)                 // assert both tasks decoded
+	assert.Equal(suite.T(), "second", tasks[0].Title)       // assert cursor order kept
+}
+
+// tests list queries drop descriptions of completed tasks only when configured
+func (suite *TaskRepositoryTestSuite) TestGetAllTasks_OmitCompletedDescriptions() {
+
+	repo := NewTaskRepositoryWithCollectionAndConfig(suite.mockCollection, TaskRepositoryConfig{OmitCompletedDescriptions: true})
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{domain.Task{ID: primitive.NewObjectID()}}, nil, nil)
+
+	// mock the Find method of the collection expecting the conditional projection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(opts []*options.FindOptions) bool {
+			projection, ok := options.MergeFindOptions(opts...).Projection.(bson.M)
+			if !ok {
+				return false
+			}
+			expected := bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "completed"}}, "$$REMOVE", "$description"}}
+			return assert.ObjectsAreEqual(expected, projection["description"]) &&
+				projection["title"] == 1 && projection["status"] == 1 && projection["_id"] == 1        // other fields are kept
+		})).
+		Return(cursor, nil)
+
+	_, err := repo.GetAllTasks(context.Background())          // call GetAllTasks method
+	assert.NoError(suite.T(), err)        // assert no error
+}
+
+// tests list queries have no projection by default
+func (suite *TaskRepositoryTestSuite) TestGetAllTasks_NoProjectionByDefault() {
+
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection expecting no projection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(opts []*options.FindOptions) bool {
+			return options.MergeFindOptions(opts...).Projection == nil
+		})).
+		Return(cursor, nil)
+
+	_, err := suite.repo.GetAllTasks(context.Background())      // call GetAllTasks method
+	assert.NoError(suite.T(), err)          // assert no error
+}
+
+// tests GetTasksSorted method of the TaskRepository with an allowed field
+func (suite *TaskRepositoryTestSuite) TestGetTasksSorted_Success() {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, sortedBy(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetTasksSorted(context.Background(), "due_date", true)      // call GetTasksSorted method
+	assert.NoError(suite.T(), err)                                 // assert no error
+	assert.Empty(suite.T(), tasks)                                 // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())             // assert sort was applied
+}
+
+// tests GetTasksSorted method of the TaskRepository breaks due date ties by _id in the same direction
+func (suite *TaskRepositoryTestSuite) TestGetTasksSorted_DueDateTieBreaker() {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection expecting the compound sort
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, sortedBy(bson.D{{Key: "due_date", Value: -1}, {Key: "_id", Value: -1}})).
+		Return(cursor, nil)
+
+	_, err := suite.repo.GetTasksSorted(context.Background(), "due_date", false)      // call GetTasksSorted method
+	assert.NoError(suite.T(), err)                              // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())          // assert (due_date, _id) was applied
+}
+
+// tests SearchTasks method of the TaskRepository matches title or description ignoring case
+func (suite *TaskRepositoryTestSuite) TestSearchTasks_Filter() {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+	pattern := primitive.Regex{Pattern: "report", Options: "i"}
+
+	// mock the Find method of the collection with the expected $or filter
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{
+			"$or": bson.A{
+				bson.M{"title": pattern},
+				bson.M{"description": pattern},
+			},
+			"deleted": bson.M{"$ne": true},
+		}, sortedBy(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.SearchTasks(context.Background(), "report")      // call SearchTasks method
+	assert.NoError(suite.T(), err)                      // assert no error
+	assert.Empty(suite.T(), tasks)                      // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())  // assert filter was applied
+}
+
+// tests SearchTasks method of the TaskRepository escapes regex metacharacters in the keyword
+func (suite *TaskRepositoryTestSuite) TestSearchTasks_EscapesKeyword() {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	var filter bson.M
+	suite.mockCollection.
+		On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { filter = args.Get(1).(bson.M) }).
+		Return(cursor, nil)
+
+	_, err := suite.repo.SearchTasks(context.Background(), "v1.2 (draft)*")      // call SearchTasks with metacharacters
+	assert.NoError(suite.T(), err)                         // assert no error
+
+	clauses := filter["$or"].(bson.A)
+	for _, clause := range clauses {
+		for _, value := range clause.(bson.M) {
+			assert.Equal(suite.T(), primitive.Regex{Pattern: `v1\.2 \(draft\)\*`, Options: "i"}, value)      // matched literally
+		}
+	}
+	assert.Len(suite.T(), clauses, 2)        // title and description
+}
+
+// expects a soonest first Find with the given due date window
+func (suite *TaskRepositoryTestSuite) expectDueDateWindow(window bson.M) {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{
+			"due_date": window,
+			"deleted":  bson.M{"$ne": true},
+		}, sortedBy(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})).
+		Return(cursor, nil)
+}
+
+// tests GetTasksByDueDateRange method of the TaskRepository with both bounds
+func (suite *TaskRepositoryTestSuite) TestGetTasksByDueDateRange_BothBounds() {
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 31, 23, 59, 59, 0, time.UTC)
+	suite.expectDueDateWindow(bson.M{"$gte": from, "$lte": to})
+
+	tasks, err := suite.repo.GetTasksByDueDateRange(context.Background(), from, to)      // call GetTasksByDueDateRange method
+	assert.NoError(suite.T(), err)                                 // assert no error
+	assert.Empty(suite.T(), tasks)                                 // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())             // assert window was applied
+}
+
+// tests GetTasksByDueDateRange method of the TaskRepository with only a lower bound
+func (suite *TaskRepositoryTestSuite) TestGetTasksByDueDateRange_FromOnly() {
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	suite.expectDueDateWindow(bson.M{"$gte": from})
+
+	_, err := suite.repo.GetTasksByDueDateRange(context.Background(), from, time.Time{})      // open ended into the future
+	assert.NoError(suite.T(), err)                                      // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())                  // assert no upper bound
+}
+
+// tests GetTasksByDueDateRange method of the TaskRepository with only an upper bound
+func (suite *TaskRepositoryTestSuite) TestGetTasksByDueDateRange_ToOnly() {
+
+	to := time.Date(2025, 7, 31, 23, 59, 59, 0, time.UTC)
+	suite.expectDueDateWindow(bson.M{"$lte": to})
+
+	_, err := suite.repo.GetTasksByDueDateRange(context.Background(), time.Time{}, to)        // everything due up to the bound
+	assert.NoError(suite.T(), err)                                      // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())                  // assert no lower bound
+}
+
+// tests GetTasksSorted method of the TaskRepository rejects fields outside the whitelist
+func (suite *TaskRepositoryTestSuite) TestGetTasksSorted_InvalidField() {
+
+	tasks, err := suite.repo.GetTasksSorted(context.Background(), `{"$where":"1"}`, true)        // call GetTasksSorted with raw input
+	assert.Nil(suite.T(), tasks)                                           // assert tasks is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidSortField)             // assert error is ErrInvalidSortField
+	suite.mockCollection.AssertNotCalled(suite.T(), "Find", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests GetRecentlyUpdated method of the TaskRepository applies sort and limit
+func (suite *TaskRepositoryTestSuite) TestGetRecentlyUpdated_SortAndLimit() {
+
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection expecting newest first with a limit of 5
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(opts []*options.FindOptions) bool {
+			merged := options.MergeFindOptions(opts...)
+			return assert.ObjectsAreEqual(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}, merged.Sort) &&
+				merged.Limit != nil && *merged.Limit == 5
+		})).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetRecentlyUpdated(context.Background(), "", 5)      // call GetRecentlyUpdated method
+	assert.NoError(suite.T(), err)                          // assert no error
+	assert.Empty(suite.T(), tasks)                          // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())      // assert sort and limit were applied
+}
+
+// tests GetUpcomingTasks method of the TaskRepository filters by window and excludes completed
+func (suite *TaskRepositoryTestSuite) TestGetUpcomingTasks_Filter() {
+
+	// window bounds
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection with the expected filter
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{
+			"due_date": bson.M{"$gte": from, "$lte": to},
+			"status":   bson.M{"$nin": []string{"completed", "archived"}},
+			"deleted":  bson.M{"$ne": true},
+		}, mock.Anything).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetUpcomingTasks(context.Background(), "", from, to)      // call GetUpcomingTasks method
+	assert.NoError(suite.T(), err)                               // assert no error
+	assert.Empty(suite.T(), tasks)                               // assert empty result
+}
+
+// tests GetOverdueTasks method of the TaskRepository filters past due, unfinished tasks
+func (suite *TaskRepositoryTestSuite) TestGetOverdueTasks_Filter() {
+
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection with the expected filter and sort
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{
+			"due_date": bson.M{"$lt": now},
+			"status":   bson.M{"$nin": []string{"completed", "archived"}},
+			"deleted":  bson.M{"$ne": true},
+		}, sortedBy(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetOverdueTasks(context.Background(), "", now)       // call GetOverdueTasks method
+	assert.NoError(suite.T(), err)                          // assert no error
+	assert.Empty(suite.T(), tasks)                          // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())      // assert filter was applied
+}
+
+// tests GetUpcomingTasks method of the TaskRepository limits the window to one owner
+func (suite *TaskRepositoryTestSuite) TestGetUpcomingTasks_Owner() {
+
+	// owner and window bounds
+	owner := primitive.NewObjectID()
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection with the owner in the filter
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{
+			"owner_id": owner,
+			"due_date": bson.M{"$gte": from, "$lte": to},
+			"status":   bson.M{"$nin": []string{"completed", "archived"}},
+			"deleted":  bson.M{"$ne": true},
+		}, mock.Anything).
+		Return(cursor, nil)
+
+	_, err := suite.repo.GetUpcomingTasks(context.Background(), owner.Hex(), from, to)      // call GetUpcomingTasks method
+	assert.NoError(suite.T(), err)                                    // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())                // assert owner filter was applied
+}
+
+// tests GetTasksByOwner method of the TaskRepository filters on owner_id
+func (suite *TaskRepositoryTestSuite) TestGetTasksByOwner_Success() {
+
+	// owner and a cursor with one of their tasks
+	owner := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.Task{ID: primitive.NewObjectID(), Title: "mine", OwnerID: owner},
+	}, nil, nil)
+
+	// mock the Find and CountDocuments methods of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}).
+		Return(int64(1), nil)
+
+	tasks, total, err := suite.repo.GetTasksByOwner(context.Background(), owner.Hex(), 0, 0)      // call GetTasksByOwner method
+	assert.NoError(suite.T(), err)                             // assert no error
+	assert.Len(suite.T(), tasks, 1)                            // assert one task decoded
+	assert.Equal(suite.T(), owner, tasks[0].OwnerID)           // assert owner kept
+	assert.Equal(suite.T(), int64(1), total)                   // assert total counted
+}
+
+// tests GetTasksByOwner method of the TaskRepository runs the page and count queries concurrently with the same filter
+func (suite *TaskRepositoryTestSuite) TestGetTasksByOwner_ConcurrentCount() {
+
+	owner := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.Task{ID: primitive.NewObjectID(), Title: "first", OwnerID: owner},
+		domain.Task{ID: primitive.NewObjectID(), Title: "second", OwnerID: owner},
+	}, nil, nil)
+
+	// the page query is slow, the count finishes first
+	var findFilter, countFilter interface{}
+	suite.mockCollection.
+		On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { findFilter = args.Get(1) }).
+		After(50*time.Millisecond).
+		Return(cursor, nil)
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { countFilter = args.Get(1) }).
+		Return(int64(42), nil)
+
+	start := time.Now()
+	tasks, total, err := suite.repo.GetTasksByOwner(context.Background(), owner.Hex(), 1, 2)      // call GetTasksByOwner method
+	assert.NoError(suite.T(), err)                                         // assert no error
+	assert.Len(suite.T(), tasks, 2)                                        // assert page assembled
+	assert.Equal(suite.T(), int64(42), total)                              // assert count assembled
+	assert.Equal(suite.T(), bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}, findFilter)         // assert owner filter on the page
+	assert.Equal(suite.T(), findFilter, countFilter)                       // assert both share the filter
+	assert.Less(suite.T(), time.Since(start), time.Second)                 // assert the slow query did not block
+}
+
+// tests GetTasksByOwner method of the TaskRepository fails when the count fails
+func (suite *TaskRepositoryTestSuite) TestGetTasksByOwner_CountError() {
+
+	owner := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}).
+		Return(int64(0), errors.New("count error"))
+
+	tasks, total, err := suite.repo.GetTasksByOwner(context.Background(), owner.Hex(), 1, 20)      // call GetTasksByOwner method
+	assert.Nil(suite.T(), tasks)                                            // assert no partial page
+	assert.Zero(suite.T(), total)                                           // assert no total
+	assert.EqualError(suite.T(), err, "count error")                        // assert count error returned
+}
+
+// tests GetTasksByOwner method of the TaskRepository skips to the requested page
+func (suite *TaskRepositoryTestSuite) TestGetTasksByOwner_Pagination() {
+
+	// owner and an empty cursor
+	owner := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection with the owner filter and page options
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(opts []*options.FindOptions) bool {
+			return len(opts) == 1 &&
+				opts[0].Skip != nil && *opts[0].Skip == 40 &&
+				opts[0].Limit != nil && *opts[0].Limit == 20
+		})).
+		Return(cursor, nil)
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, bson.M{"owner_id": owner, "deleted": bson.M{"$ne": true}}).
+		Return(int64(40), nil)
+
+	_, _, err := suite.repo.GetTasksByOwner(context.Background(), owner.Hex(), 3, 20)      // third page of 20
+	assert.NoError(suite.T(), err)                                // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())            // assert page options were applied
+}
+
+// tests GetTasksByOwner method of the TaskRepository with invalid owner ID
+func (suite *TaskRepositoryTestSuite) TestGetTasksByOwner_InvalidID() {
+
+	tasks, _, err := suite.repo.GetTasksByOwner(context.Background(), "invalid-id", 1, 20)      // call GetTasksByOwner method
+	assert.Nil(suite.T(), tasks)                                   // assert tasks is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)        // assert error is ErrInvalidUserID
+	suite.mockCollection.AssertNotCalled(suite.T(), "Find", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests GetTasksByUser method of the TaskRepository filters on assigned_to
+func (suite *TaskRepositoryTestSuite) TestGetTasksByUser_Success() {
+
+	// assignee and a cursor with one of their tasks
+	user := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.Task{ID: primitive.NewObjectID(), Title: "assigned", AssignedTo: user},
+	}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"assigned_to": user, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetTasksByUser(context.Background(), user.Hex())      // call GetTasksByUser method
+	assert.NoError(suite.T(), err)                           // assert no error
+	assert.Len(suite.T(), tasks, 1)                          // assert one task decoded
+	assert.Equal(suite.T(), user, tasks[0].AssignedTo)       // assert assignee kept
+}
+
+// tests GetTasksByUser method of the TaskRepository with invalid user ID
+func (suite *TaskRepositoryTestSuite) TestGetTasksByUser_InvalidID() {
+
+	tasks, err := suite.repo.GetTasksByUser(context.Background(), "invalid-id")      // call GetTasksByUser method
+	assert.Nil(suite.T(), tasks)                               // assert tasks is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)    // assert error is ErrInvalidUserID
+	suite.mockCollection.AssertNotCalled(suite.T(), "Find", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests CountByOwnerStatus method of the TaskRepository folds the aggregation rows per owner
+func (suite *TaskRepositoryTestSuite) TestCountByOwnerStatus_Success() {
+
+	// fake aggregation output for two owners
+	alice, bob := primitive.NewObjectID(), primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": bson.M{"owner_id": alice, "status": "pending"}, "count": int64(2)},
+		bson.M{"_id": bson.M{"owner_id": alice, "status": "completed"}, "count": int64(1)},
+		bson.M{"_id": bson.M{"owner_id": bob, "status": "in_progress"}, "count": int64(4)},
+	}, nil, nil)
+
+	// mock the Aggregate method of the collection
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, mock.Anything).
+		Return(cursor, nil)
+
+	counts, err := suite.repo.CountByOwnerStatus(context.Background(), []string{alice.Hex(), bob.Hex()})      // call CountByOwnerStatus method
+	assert.NoError(suite.T(), err)                                                        // assert no error
+	assert.Equal(suite.T(), map[string]map[string]int64{
+		alice.Hex(): {"pending": 2, "completed": 1},
+		bob.Hex():   {"in_progress": 4},
+	}, counts)                                                                             // assert rows grouped per owner
+}
+
+// tests CountByOwnerStatus method of the TaskRepository with invalid owner ID
+func (suite *TaskRepositoryTestSuite) TestCountByOwnerStatus_InvalidID() {
+
+	counts, err := suite.repo.CountByOwnerStatus(context.Background(), []string{"invalid-id"})      // call CountByOwnerStatus method
+	assert.Nil(suite.T(), counts)                                             // assert counts is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)                   // assert error is ErrInvalidUserID
+	suite.mockCollection.AssertNotCalled(suite.T(), "Aggregate", mock.Anything, mock.Anything)
+}
+
+// tests CountByProject method of the TaskRepository groups the project's tasks and sums both breakdowns
+func (suite *TaskRepositoryTestSuite) TestCountByProject_Pipeline() {
+
+	// fake aggregation output, one row per status and priority pair
+	project := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": bson.M{"status": "pending", "priority": "high"}, "count": int64(2)},
+		bson.M{"_id": bson.M{"status": "pending", "priority": "low"}, "count": int64(1)},
+		bson.M{"_id": bson.M{"status": "completed", "priority": "high"}, "count": int64(4)},
+	}, nil, nil)
+	expected := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": project, "deleted": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"status": "$status", "priority": "$priority"}, "count": bson.M{"$sum": 1}}}},
+	}
+
+	// mock the Aggregate method of the collection with the expected pipeline
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, expected).
+		Return(cursor, nil)
+
+	stats, err := suite.repo.CountByProject(context.Background(), project.Hex())      // call CountByProject method
+	assert.NoError(suite.T(), err)                              // assert no error
+	assert.Equal(suite.T(), map[string]int64{"pending": 3, "completed": 4}, stats.ByStatus)       // assert rows summed per status
+	assert.Equal(suite.T(), map[string]int64{"high": 6, "low": 1}, stats.ByPriority)              // assert rows summed per priority
+}
+
+// tests CountByProject method of the TaskRepository with invalid project ID
+func (suite *TaskRepositoryTestSuite) TestCountByProject_InvalidID() {
+
+	stats, err := suite.repo.CountByProject(context.Background(), "invalid-id")      // call CountByProject method
+	assert.Nil(suite.T(), stats)                               // assert stats is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidProjectID) // assert error is ErrInvalidProjectID
+	suite.mockCollection.AssertNotCalled(suite.T(), "Aggregate", mock.Anything, mock.Anything)
+}
+
+// tests GroupByAssignee method of the TaskRepository groups assigned tasks by assignee, busiest first
+func (suite *TaskRepositoryTestSuite) TestGroupByAssignee_Pipeline() {
+
+	cursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+	expected := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"assigned_to": bson.M{"$nin": bson.A{nil, primitive.NilObjectID}}, "deleted": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$assigned_to", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	// mock the Aggregate method of the collection
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, expected).
+		Return(cursor, nil)
+
+	groups, err := suite.repo.GroupByAssignee(context.Background(), false)      // call GroupByAssignee method
+	assert.NoError(suite.T(), err)                        // assert no error
+	assert.Empty(suite.T(), groups)                       // assert no groups
+	assert.NotNil(suite.T(), groups)                      // assert empty, not nil
+}
+
+// tests GroupByAssignee method of the TaskRepository pushes the newest tasks first when requested
+func (suite *TaskRepositoryTestSuite) TestGroupByAssignee_IncludeTasks() {
+
+	// fake aggregation output for two assignees
+	alice, bob := primitive.NewObjectID(), primitive.NewObjectID()
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": alice, "count": int64(2), "tasks": bson.A{
+			domain.Task{ID: first, Title: "newer", AssignedTo: alice},
+			domain.Task{ID: second, Title: "older", AssignedTo: alice},
+		}},
+		bson.M{"_id": bob, "count": int64(1), "tasks": bson.A{domain.Task{Title: "only", AssignedTo: bob}}},
+	}, nil, nil)
+
+	// mock the Aggregate method of the collection, checking the sort and push stages
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, mock.MatchedBy(func(pipeline mongo.Pipeline) bool {
+			return len(pipeline) == 4 &&
+				assert.ObjectsAreEqual(bson.D{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}}}, pipeline[1]) &&
+				assert.ObjectsAreEqual(bson.M{"$push": "$$ROOT"}, pipeline[2][0].Value.(bson.M)["tasks"])
+		})).
+		Return(cursor, nil)
+
+	groups, err := suite.repo.GroupByAssignee(context.Background(), true)       // call GroupByAssignee method
+	assert.NoError(suite.T(), err)                        // assert no error
+	assert.Len(suite.T(), groups, 2)                      // assert one group per assignee
+	assert.Equal(suite.T(), alice, groups[0].AssigneeID)  // assert assignee decoded from _id
+	assert.Equal(suite.T(), int64(2), groups[0].Count)    // assert count kept
+	assert.Equal(suite.T(), []primitive.ObjectID{first, second}, []primitive.ObjectID{groups[0].Tasks[0].ID, groups[0].Tasks[1].ID})     // assert task order kept
+	assert.Equal(suite.T(), bob, groups[1].AssigneeID)    // assert second assignee
+	assert.Len(suite.T(), groups[1].Tasks, 1)             // assert their task decoded
+}
+
 // tests GetTaskByID method of the TaskRepository for non-existing task
 func (suite *TaskRepositoryTestSuite) TestGetTaskByID_NotFound() {
 
@@ -105,10 +755,10 @@ func (suite *TaskRepositoryTestSuite) TestGetTaskByID_NotFound() {
 
 	// mock the FindOne method of the collection
 	suite.mockCollection.
-		On("FindOne", mock.Anything, bson.M{"_id": objID}).
+		On("FindOne", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}).
 		Return(mockResult)
 
-	task, err := suite.repo.GetTaskByID(objID.Hex())       // call GetTaskByID method
+	task, err := suite.repo.GetTaskByID(context.Background(), objID.Hex())       // call GetTaskByID method
 	assert.Nil(suite.T(), task)                            // assert task is nil
 	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound) // assert error is ErrTaskNotFound
 }
@@ -116,7 +766,7 @@ func (suite *TaskRepositoryTestSuite) TestGetTaskByID_NotFound() {
 // tests GetTaskByID method of the TaskRepository for invalid ID
 func (suite *TaskRepositoryTestSuite) TestGetTaskByID_InvalidID() {
 
-	task, err := suite.repo.GetTaskByID("invalid-id")       // call GetTaskByID with invalid ID
+	task, err := suite.repo.GetTaskByID(context.Background(), "invalid-id")       // call GetTaskByID with invalid ID
 	assert.Nil(suite.T(), task)                             // assert task is nil
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID) // assert error is ErrInvalidTaskID
 }
@@ -133,10 +783,10 @@ func (suite *TaskRepositoryTestSuite) TestGetTaskByID_Error() {
 
 	// mock the FindOne method of the collection to return error
 	suite.mockCollection.
-		On("FindOne", mock.Anything, bson.M{"_id": objID}).
+		On("FindOne", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}).
 		Return(mockResult)
 
-	task, err := suite.repo.GetTaskByID(objID.Hex()) // call GetTaskByID method
+	task, err := suite.repo.GetTaskByID(context.Background(), objID.Hex()) // call GetTaskByID method
 	assert.Nil(suite.T(), task)                      // assert task is nil
 	assert.EqualError(suite.T(), err, "find error")  // assert error message
 }
@@ -144,14 +794,10 @@ func (suite *TaskRepositoryTestSuite) TestGetTaskByID_Error() {
 // tests DeleteTask method of the TaskRepository with invalid ID
 func (suite *TaskRepositoryTestSuite) TestDeleteTask_InvalidID() {
 
-	// mock the DeleteOne method of the collection
-	suite.mockCollection.
-		On("DeleteOne", mock.Anything, bson.M{"_id": mock.AnythingOfType("primitive.ObjectID")}).
-		Return(&mongo.DeleteResult{}, nil)
-
-	err := suite.repo.DeleteTask("invalid-id")              // call DeleteTask with an invalid ID
+	err := suite.repo.DeleteTask(context.Background(), "invalid-id")              // call DeleteTask with an invalid ID
 	assert.Error(suite.T(), err)                            // assert error is returned
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID) // assert error is ErrInvalidTaskID
+	suite.mockCollection.AssertNotCalled(suite.T(), "FindOneAndUpdate", mock.Anything, mock.Anything, mock.Anything)
 }
 
 // tests DeleteTask method of the TaskRepository for non-existing task
@@ -160,12 +806,12 @@ func (suite *TaskRepositoryTestSuite) TestDeleteTask_NotFound() {
 	// create a new object ID
 	objID := primitive.NewObjectID()
 
-	// mock the DeleteOne method of the collection
+	// mock the FindOneAndUpdate method of the collection, missing or already deleted
 	suite.mockCollection.
-		On("DeleteOne", mock.Anything, bson.M{"_id": objID}).
-		Return(&mongo.DeleteResult{DeletedCount: 0}, nil)
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
 
-	err := suite.repo.DeleteTask(objID.Hex())              // call DeleteTask method
+	err := suite.repo.DeleteTask(context.Background(), objID.Hex())              // call DeleteTask method
 	assert.Error(suite.T(), err)                           // assert error is returned
 	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound) // assert error is ErrTaskNotFound
 }
@@ -176,13 +822,82 @@ func (suite *TaskRepositoryTestSuite) TestDeleteTask_Success() {
 	// create a new object ID
 	objID := primitive.NewObjectID()
 
-	// mock the DeleteOne method of collection
+	// mock the FindOneAndUpdate method of collection
 	suite.mockCollection.
-		On("DeleteOne", mock.Anything, bson.M{"_id": objID}).
-		Return(&mongo.DeleteResult{DeletedCount: 1}, nil)
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["deleted"] == true && !set["deleted_at"].(time.Time).IsZero()
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: objID}})
 
-	err := suite.repo.DeleteTask(objID.Hex()) // call DeleteTask method
+	err := suite.repo.DeleteTask(context.Background(), objID.Hex()) // call DeleteTask method
 	assert.NoError(suite.T(), err)            // assert no error
+	suite.mockCollection.AssertNotCalled(suite.T(), "DeleteOne", mock.Anything, mock.Anything)      // document is kept
+}
+
+// tests a soft deleted task is no longer found by GetTaskByID
+func (suite *TaskRepositoryTestSuite) TestDeleteTask_ThenGetNotFound() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+	active := bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}
+
+	// the delete tombstones the task, after which the active filter matches nothing
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, active, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: objID}})
+	suite.mockCollection.
+		On("FindOne", mock.Anything, active).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	assert.NoError(suite.T(), suite.repo.DeleteTask(context.Background(), objID.Hex()))      // delete succeeds
+
+	task, err := suite.repo.GetTaskByID(context.Background(), objID.Hex())          // call GetTaskByID method
+	assert.Nil(suite.T(), task)                               // assert task is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)    // deleted task is not found
+	suite.mockCollection.AssertExpectations(suite.T())        // lookup excluded deleted tasks
+}
+
+// tests RestoreTask method of the TaskRepository clears the tombstone
+func (suite *TaskRepositoryTestSuite) TestRestoreTask_Success() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of collection, deleted tasks must match
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			unset := update["$unset"].(bson.M)
+			_, clearsDeletedAt := unset["deleted_at"]
+			return set["deleted"] == false && clearsDeletedAt
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: objID}})
+
+	err := suite.repo.RestoreTask(context.Background(), objID.Hex())      // call RestoreTask method
+	assert.NoError(suite.T(), err)                  // assert no error
+}
+
+// tests RestoreTask method of the TaskRepository for non-existing task
+func (suite *TaskRepositoryTestSuite) TestRestoreTask_NotFound() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID}, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	err := suite.repo.RestoreTask(context.Background(), objID.Hex())              // call RestoreTask method
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)  // assert error is ErrTaskNotFound
+}
+
+// tests RestoreTask method of the TaskRepository with invalid ID
+func (suite *TaskRepositoryTestSuite) TestRestoreTask_InvalidID() {
+
+	err := suite.repo.RestoreTask(context.Background(), "invalid-id")              // call RestoreTask with an invalid ID
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID)  // assert error is ErrInvalidTaskID
 }
 
 // tests UpdateTask method of the TaskRepository with no fields provided
@@ -198,18 +913,83 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask_NoFieldsProvided() {
 		On("UpdateOne", mock.Anything, bson.M{"_id": objID}, mock.Anything).
 		Return(&mongo.UpdateResult{}, nil)
 
-	updated, err := suite.repo.UpdateTask(objID.Hex(), task)                    // call UpdateTask method with no fields provided
+	updated, err := suite.repo.UpdateTask(context.Background(), objID.Hex(), task)                    // call UpdateTask method with no fields provided
 	assert.Nil(suite.T(), updated)                                              // assert updated task is nil
 	assert.Error(suite.T(), err)                                                // assert error is returned
 	assert.Equal(suite.T(), "no valid fields provided for update", err.Error()) // assert error message
 }
 
+// tests UpdateTask method of the TaskRepository stores an empty dependency list, clearing it
+func (suite *TaskRepositoryTestSuite) TestUpdateTask_ClearDependencies() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection expecting depends_on to be set
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update bson.M) bool {
+			deps, ok := update["$set"].(bson.M)["depends_on"].([]primitive.ObjectID)
+			return ok && len(deps) == 0
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: objID}})
+
+	_, err := suite.repo.UpdateTask(context.Background(), objID.Hex(), &domain.Task{DependsOn: []primitive.ObjectID{}})      // call UpdateTask with only an empty list
+	assert.NoError(suite.T(), err)                                                                      // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())
+}
+
+// tests UpdateTask method of the TaskRepository keeps the completion time of a task already completed
+func (suite *TaskRepositoryTestSuite) TestUpdateTask_CompletedKeepsCompletedAt() {
+
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection expecting a pipeline that checks the stored status
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update []bson.M) bool {
+			stage := update[0]["$set"].(bson.M)
+			cond := stage["completed_at"].(bson.M)["$cond"].(bson.A)
+			return len(update) == 1 &&
+				assert.ObjectsAreEqual(bson.M{"$literal": "completed"}, stage["status"]) &&
+				assert.ObjectsAreEqual(bson.M{"$literal": "$title"}, stage["title"]) &&        // values are not read as field paths
+				assert.ObjectsAreEqual(bson.M{"$eq": bson.A{"$status", "completed"}}, cond[0]) &&
+				cond[1] == "$completed_at"                                                    // already completed keeps its time
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: objID, Status: "completed"}})
+
+	_, err := suite.repo.UpdateTask(context.Background(), objID.Hex(), &domain.Task{Title: "$title", Status: "completed"})
+	assert.NoError(suite.T(), err)                                                                      // assert no error
+	suite.mockCollection.AssertExpectations(suite.T())
+}
+
+// tests GetTasksByIDs method of the TaskRepository looks up live tasks among the ids
+func (suite *TaskRepositoryTestSuite) TestGetTasksByIDs() {
+
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	// create an empty cursor
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{}, nil, nil)
+
+	// mock the Find method of the collection with the id set
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"_id": bson.M{"$in": ids}, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetTasksByIDs(context.Background(), ids)         // call GetTasksByIDs method
+	assert.NoError(suite.T(), err)                      // assert no error
+	assert.Empty(suite.T(), tasks)                      // assert empty result
+	suite.mockCollection.AssertExpectations(suite.T())
+
+	none, err := suite.repo.GetTasksByIDs(context.Background(), nil)          // no ids, no query
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), none)
+	suite.mockCollection.AssertNumberOfCalls(suite.T(), "Find", 1)
+}
+
 // tests UpdateTask method of the TaskRepository for invalid ID
 func (suite *TaskRepositoryTestSuite) TestUpdateTask_InvalidID() {
 
 	// create test task
 	task := &domain.Task{Title: "Update"}
-	updated, err := suite.repo.UpdateTask("invalid-id", task) // call UpdateTask with invalid ID
+	updated, err := suite.repo.UpdateTask(context.Background(), "invalid-id", task) // call UpdateTask with invalid ID
 	assert.Nil(suite.T(), updated)                            // assert updated task is nil
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID)   // assert error is ErrInvalidTaskID
 }
@@ -228,10 +1008,10 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask_NotFound() {
 
 	// mock the FindOneAndUpdate method of the collection
 	suite.mockCollection.
-		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID}, mock.Anything).
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.Anything).
 		Return(mockResult)
 
-	updated, err := suite.repo.UpdateTask(objID.Hex(), task)
+	updated, err := suite.repo.UpdateTask(context.Background(), objID.Hex(), task)
 	assert.Nil(suite.T(), updated)                         // assert updated task is nil
 	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound) // assert error is ErrTaskNotFound
 }
@@ -250,14 +1030,285 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask_Error() {
 
 	// mock the FindOneAndUpdate method of the collection
 	suite.mockCollection.
-		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID}, mock.Anything).
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.Anything).
 		Return(mockResult)
 
-	updated, err := suite.repo.UpdateTask(objID.Hex(), task) // call UpdateTask method
+	updated, err := suite.repo.UpdateTask(context.Background(), objID.Hex(), task) // call UpdateTask method
 	assert.Nil(suite.T(), updated)                           // assert updated task is nil
 	assert.EqualError(suite.T(), err, "update error")        // assert error message
 }
 
+// tests ArchiveCompletedBefore method of the TaskRepository filters on the cutoff and archives matches
+func (suite *TaskRepositoryTestSuite) TestArchiveCompletedBefore_Filter() {
+
+	// completed before this time should be archived
+	cutoff := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// mock the UpdateMany method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, bson.M{
+			"status":       "completed",
+			"completed_at": bson.M{"$lt": cutoff},
+		}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["status"] == "archived"
+		})).
+		Return(&mongo.UpdateResult{MatchedCount: 3, ModifiedCount: 3}, nil)
+
+	archived, err := suite.repo.ArchiveCompletedBefore(context.Background(), cutoff)      // call ArchiveCompletedBefore method
+	assert.NoError(suite.T(), err)                                  // assert no error
+	assert.Equal(suite.T(), int64(3), archived)                     // assert modified count is returned
+	suite.mockCollection.AssertExpectations(suite.T())              // assert cutoff filter was applied
+}
+
+// tests CountByStatus method of the TaskRepository counts each status once and assembles the map
+func (suite *TaskRepositoryTestSuite) TestCountByStatus() {
+
+	// fake aggregation output, in_progress has no tasks
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"_id": "pending", "count": int64(5)},
+		bson.M{"_id": "completed", "count": int64(10)},
+	}, nil, nil)
+	expected := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": bson.M{"$in": []string{"pending", "in_progress", "completed"}}, "deleted": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	// mock the Aggregate method of the collection with the expected pipeline
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, expected).
+		Return(cursor, nil)
+
+	counts, err := suite.repo.CountByStatus(context.Background())      // call CountByStatus method
+	assert.NoError(suite.T(), err)                 // assert no error
+	assert.Equal(suite.T(), map[string]int64{"pending": 5, "in_progress": 0, "completed": 10}, counts)     // assert every status is listed
+	suite.mockCollection.AssertNotCalled(suite.T(), "CountDocuments", mock.Anything, mock.Anything)      // no document is counted one by one
+}
+
+// tests CountByStatus method of the TaskRepository for error case
+func (suite *TaskRepositoryTestSuite) TestCountByStatus_Error() {
+
+	// mock the Aggregate method of the collection to return an error
+	suite.mockCollection.
+		On("Aggregate", mock.Anything, mock.Anything).
+		Return(nil, errors.New("aggregate error"))
+
+	counts, err := suite.repo.CountByStatus(context.Background())      // call CountByStatus method
+	assert.Nil(suite.T(), counts)                  // assert counts are nil
+	assert.EqualError(suite.T(), err, "aggregate error")      // assert error is passed through
+}
+
+// tests DeleteStatusBefore method of the TaskRepository filters on status and age and deletes matches
+func (suite *TaskRepositoryTestSuite) TestDeleteStatusBefore_Filter() {
+
+	// archived tasks last changed before this time should be deleted
+	cutoff := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// mock the DeleteMany method of the collection with the expected filter
+	suite.mockCollection.
+		On("DeleteMany", mock.Anything, bson.M{
+			"status":     "archived",
+			"updated_at": bson.M{"$lt": cutoff},
+		}).
+		Return(&mongo.DeleteResult{DeletedCount: 5}, nil)
+
+	deleted, err := suite.repo.DeleteStatusBefore(context.Background(), "archived", cutoff)      // call DeleteStatusBefore method
+	assert.NoError(suite.T(), err)                                         // assert no error
+	assert.Equal(suite.T(), int64(5), deleted)                             // assert deleted count is returned
+	suite.mockCollection.AssertExpectations(suite.T())                     // assert status and age filter was applied
+}
+
+// tests ArchiveCompletedBefore method of the TaskRepository for error case
+func (suite *TaskRepositoryTestSuite) TestArchiveCompletedBefore_Error() {
+
+	// mock the UpdateMany method of the collection to fail
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("update error"))
+
+	archived, err := suite.repo.ArchiveCompletedBefore(context.Background(), time.Now())      // call ArchiveCompletedBefore method
+	assert.Zero(suite.T(), archived)                                    // assert nothing archived
+	assert.EqualError(suite.T(), err, "update error")                   // assert error message
+}
+
+// tests ReassignOwner method of the TaskRepository moves tasks from one owner to the other
+func (suite *TaskRepositoryTestSuite) TestReassignOwner_Filter() {
+
+	from := primitive.NewObjectID()
+	to := primitive.NewObjectID()
+
+	// mock the UpdateMany method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, bson.M{"owner_id": from}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			_, detached := update["$unset"].(bson.M)["project_id"]        // old owner's project is dropped
+			return set["owner_id"] == to && !set["updated_at"].(time.Time).IsZero() && detached
+		})).
+		Return(&mongo.UpdateResult{MatchedCount: 4, ModifiedCount: 4}, nil)
+
+	moved, err := suite.repo.ReassignOwner(context.Background(), from.Hex(), to.Hex())      // call ReassignOwner method
+	assert.NoError(suite.T(), err)                                    // assert no error
+	assert.Equal(suite.T(), int64(4), moved)                          // assert modified count is returned
+	suite.mockCollection.AssertExpectations(suite.T())                // assert owner filter was applied
+}
+
+// tests ReassignOwner method of the TaskRepository with an invalid id
+func (suite *TaskRepositoryTestSuite) TestReassignOwner_InvalidID() {
+
+	_, err := suite.repo.ReassignOwner(context.Background(), "invalid", primitive.NewObjectID().Hex())      // call ReassignOwner method
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)                          // assert invalid user id
+	suite.mockCollection.AssertNotCalled(suite.T(), "UpdateMany", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests CompleteTasks method of the TaskRepository only completes listed active tasks in the given statuses
+func (suite *TaskRepositoryTestSuite) TestCompleteTasks_Filter() {
+
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	filter := bson.M{
+		"_id":     bson.M{"$in": []primitive.ObjectID{first, second}},
+		"status":  bson.M{"$in": []string{"in_progress", "pending"}},
+		"deleted": bson.M{"$ne": true},
+	}
+
+	// mock the UpdateMany method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, filter, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["status"] == "completed" && !set["completed_at"].(time.Time).IsZero()
+		})).
+		Return(&mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil)
+
+	completed, err := suite.repo.CompleteTasks(context.Background(), []string{first.Hex(), second.Hex()}, []string{"in_progress", "pending"})      // call CompleteTasks method
+	assert.NoError(suite.T(), err)                                    // assert no error
+	assert.Equal(suite.T(), int64(1), completed)                      // assert modified count is returned
+	suite.mockCollection.AssertExpectations(suite.T())                // assert filter and update were applied
+}
+
+// tests CompleteTasks method of the TaskRepository with an invalid id
+func (suite *TaskRepositoryTestSuite) TestCompleteTasks_InvalidID() {
+
+	_, err := suite.repo.CompleteTasks(context.Background(), []string{primitive.NewObjectID().Hex(), "invalid"}, []string{"pending"})      // call CompleteTasks method
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID)                                                         // assert invalid task id
+	suite.mockCollection.AssertNotCalled(suite.T(), "UpdateMany", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests GetTasksByProject method of the TaskRepository filters on the project
+func (suite *TaskRepositoryTestSuite) TestGetTasksByProject_Filter() {
+
+	project := primitive.NewObjectID()
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{domain.Task{Title: "grouped", ProjectID: project}}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"project_id": project, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+
+	tasks, err := suite.repo.GetTasksByProject(context.Background(), project.Hex())      // call GetTasksByProject method
+	assert.NoError(suite.T(), err)                                 // assert no error
+	assert.Len(suite.T(), tasks, 1)                                // assert one task decoded
+
+	_, err = suite.repo.GetTasksByProject(context.Background(), "invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidProjectID)     // assert invalid project id
+}
+
+// tests DetachProject method of the TaskRepository unsets the project of every task, deleted ones included
+func (suite *TaskRepositoryTestSuite) TestDetachProject_Filter() {
+
+	project := primitive.NewObjectID()
+
+	// mock the UpdateMany method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, bson.M{"project_id": project}, mock.MatchedBy(func(update bson.M) bool {
+			return assert.ObjectsAreEqual(bson.M{"project_id": ""}, update["$unset"])
+		})).
+		Return(&mongo.UpdateResult{MatchedCount: 3, ModifiedCount: 3}, nil)
+
+	detached, err := suite.repo.DetachProject(context.Background(), project.Hex())      // call DetachProject method
+	assert.NoError(suite.T(), err)                                // assert no error
+	assert.Equal(suite.T(), int64(3), detached)                   // assert modified count is returned
+}
+
+// tests SetTaskProject method of the TaskRepository sets the project or unsets it for a zero project
+func (suite *TaskRepositoryTestSuite) TestSetTaskProject() {
+
+	taskID := primitive.NewObjectID()
+	project := primitive.NewObjectID()
+	filter := bson.M{"_id": taskID, "deleted": bson.M{"$ne": true}}
+
+	// mock the FindOneAndUpdate method of the collection for both updates
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, filter, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["project_id"] == project && update["$unset"] == nil
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: taskID, ProjectID: project}})
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, filter, mock.MatchedBy(func(update bson.M) bool {
+			_, set := update["$set"].(bson.M)["project_id"]
+			return !set && assert.ObjectsAreEqual(bson.M{"project_id": ""}, update["$unset"])
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: taskID}})
+
+	moved, err := suite.repo.SetTaskProject(context.Background(), taskID.Hex(), project)      // move into the project
+	assert.NoError(suite.T(), err)                                       // assert no error
+	assert.Equal(suite.T(), project, moved.ProjectID)                    // assert task is in the project
+
+	moved, err = suite.repo.SetTaskProject(context.Background(), taskID.Hex(), primitive.NilObjectID)     // move out of the project
+	assert.NoError(suite.T(), err)                                       // assert no error
+	assert.True(suite.T(), moved.ProjectID.IsZero())                     // assert task has no project
+}
+
+// tests ReopenTask method of the TaskRepository only reopens completed tasks, clears the completion time and records the change
+func (suite *TaskRepositoryTestSuite) TestReopenTask() {
+
+	taskID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": taskID, "status": "completed", "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			change, ok := update["$push"].(bson.M)["status_history"].(domain.StatusChange)
+			return set["status"] == "in_progress" && assert.ObjectsAreEqual(bson.M{"completed_at": ""}, update["$unset"]) &&
+				ok && change.From == "completed" && change.To == "in_progress" && change.At.Equal(set["updated_at"].(time.Time))
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.Task{ID: taskID, Status: "in_progress"}})
+
+	task, err := suite.repo.ReopenTask(context.Background(), taskID.Hex())      // call ReopenTask method
+	assert.NoError(suite.T(), err)                        // assert no error
+	assert.Equal(suite.T(), "in_progress", task.Status)   // assert task is back in progress
+}
+
+// tests ReopenTask method of the TaskRepository when the task is not completed
+func (suite *TaskRepositoryTestSuite) TestReopenTask_NotCompleted() {
+
+	// mock the FindOneAndUpdate method of the collection to match nothing
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	task, err := suite.repo.ReopenTask(context.Background(), primitive.NewObjectID().Hex())      // call ReopenTask method
+	assert.Nil(suite.T(), task)                                            // assert task is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotCompleted)             // assert error is ErrTaskNotCompleted
+}
+
+// tests DeleteTasksByProject method of the TaskRepository soft deletes the active tasks and drops their project
+func (suite *TaskRepositoryTestSuite) TestDeleteTasksByProject_Filter() {
+
+	project := primitive.NewObjectID()
+
+	// mock the UpdateMany method of the collection with the expected filter and update
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, bson.M{"project_id": project, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["deleted"] == true && assert.ObjectsAreEqual(bson.M{"project_id": ""}, update["$unset"])
+		})).
+		Return(&mongo.UpdateResult{MatchedCount: 2, ModifiedCount: 2}, nil)
+
+	deleted, err := suite.repo.DeleteTasksByProject(context.Background(), project.Hex())      // call DeleteTasksByProject method
+	assert.NoError(suite.T(), err)                                      // assert no error
+	assert.Equal(suite.T(), int64(2), deleted)                          // assert modified count is returned
+}
+
 // suite entry point for running the tests
 func TestTaskRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(TaskRepositoryTestSuite)) // run the test suite
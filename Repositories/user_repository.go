@@ -4,7 +4,7 @@ package repositories
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"time"
 
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
@@ -16,35 +16,102 @@ import (
 )
 
 type userRepository struct {
-	collection domain.MongoCollection
+	collection  domain.MongoCollection
+	timeouts    OperationTimeouts        // per kind of operation, zero values use the defaults
 }
 
 // creates a new user repository instance
-func NewUserRepository() domain.UserRepository {
-	// setup mongodb
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)       // set timeout
-	defer cancel()
+func NewUserRepository(limiter *adapters.OperationLimiter, timeouts OperationTimeouts) (domain.UserRepository, error) {
 
-	// connect
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	// connect to the configured database
+	db, err := connectDatabase()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	db := client.Database("taskmanager")
 	userCol := db.Collection("users")         // initialize user collection
-	return &userRepository{&adapters.MongoCollectionAdapter{Collection: userCol}}
+	coll := adapters.NewLimitedCollection(&adapters.MongoCollectionAdapter{Collection: userCol}, limiter)      // share the operation limiter
+	if err := ensureUserIndexes(coll, userCol.Indexes(), timeouts); err != nil {
+		return nil, err
+	}
+	return &userRepository{collection: coll, timeouts: timeouts}, nil
+}
+
+// name of the unique index on user emails
+const emailIndexName = "email_1"
+
+// stored key a soft deleted user's email is moved to, so the address can be registered again
+const deletedEmailKey = "deleted_email"
+
+// creates indexes of a collection, satisfied by mongo.IndexView
+type indexCreator interface {
+	CreateOne(ctx context.Context, model mongo.IndexModel, opts ...*options.CreateIndexesOptions) (string, error)
+}
+
+// creates the unique email index the duplicate email check relies on, an existing index is left as is -
+// users stored without an email are left out of the index, and users deleted before emails were
+// released on delete give theirs up first
+func ensureUserIndexes(coll domain.MongoCollection, indexes indexCreator, timeouts OperationTimeouts) error {
+
+	ctx, cancel := timeouts.aggregate(context.Background())        // building the index reads every user
+	defer cancel()
+
+	_, err := coll.UpdateMany(ctx,
+		bson.M{"deleted": true, "email": bson.M{"$exists": true}},
+		bson.M{"$rename": bson.M{"email": deletedEmailKey}},
+	)
+	if err != nil {
+		return fmt.Errorf("releasing the emails of deleted users: %w", err)
+	}
+
+	// partial indexes do not accept $ne, deleted users are kept out by moving their email instead
+	_, err = indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName(emailIndexName).SetUnique(true).
+			SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		return fmt.Errorf("creating the unique email index: %w", err)
+	}
+
+	return nil
+}
+
+// reports whether a duplicate key error comes from the unique email index
+func duplicateEmail(err error) bool {
+
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+	for _, we := range writeErr.WriteErrors {
+		if we.Code != 11000 {
+			continue
+		}
+		// the server names the violated keys in keyPattern, e.g. {"email": 1}
+		if _, lookupErr := we.Raw.LookupErr("keyPattern", "email"); lookupErr == nil {
+			return true
+		}
+	}
+
+	return false
 }
 
 // this is used for testing purposes to inject a mock collection
 func NewUserRepositoryWithCollection(coll domain.MongoCollection) domain.UserRepository {
-	return &userRepository{coll}
+	return &userRepository{collection: coll}
+}
+
+// limits a user filter to users that are not soft deleted
+func activeUsers(filter bson.M) bson.M {
+	filter["deleted"] = bson.M{"$ne": true}        // also matches users stored before soft delete existed
+	return filter
 }
 
 //  register user in to database
-func (userRepo *userRepository) CreateUser(user *domain.User) error {
+func (userRepo *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := userRepo.timeouts.write(ctx)        // set timeout
 	defer cancel()
 
 	// generate new ObjectID if not set
@@ -56,6 +123,9 @@ func (userRepo *userRepository) CreateUser(user *domain.User) error {
 	_, err := userRepo.collection.InsertOne(contx, user)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
+			if duplicateEmail(err) {
+				return domain.ErrEmailExists
+			}
 			return domain.ErrUserExists
 		}
 		return err
@@ -65,7 +135,7 @@ func (userRepo *userRepository) CreateUser(user *domain.User) error {
 }
 
 // find user from database by username
-func (userRepo *userRepository) GetByUsername(username string) (*domain.User, error) {
+func (userRepo *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 
 	// check username
 	if username == "" {
@@ -73,11 +143,35 @@ func (userRepo *userRepository) GetByUsername(username string) (*domain.User, er
 	}
 	
 	var user domain.User
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
 	defer cancel()
 	
 	// find user by username
-	err := userRepo.collection.FindOne(contx, bson.M{"username": username}).Decode(&user)
+	err := userRepo.collection.FindOne(contx, activeUsers(bson.M{"username": username})).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil        // success
+}
+
+// find user from database by email
+func (userRepo *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+
+	// check email
+	if email == "" {
+		return nil, errors.New("email cannot be empty")
+	}
+
+	var user domain.User
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	// find user by email
+	err := userRepo.collection.FindOne(contx, activeUsers(bson.M{"email": email})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -89,14 +183,14 @@ func (userRepo *userRepository) GetByUsername(username string) (*domain.User, er
 }
 
 // find user from database by id
-func (userRepo *userRepository) GetUserById(userID primitive.ObjectID) (*domain.User, error) {
+func (userRepo *userRepository) GetUserById(ctx context.Context, userID primitive.ObjectID) (*domain.User, error) {
 	
 	var user domain.User
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
 	defer cancel()
 	
 	// find user by id
-	err := userRepo.collection.FindOne(contx, bson.M{"_id": userID}).Decode(&user)
+	err := userRepo.collection.FindOne(contx, activeUsers(bson.M{"_id": userID})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -108,9 +202,9 @@ func (userRepo *userRepository) GetUserById(userID primitive.ObjectID) (*domain.
 }
 
 // count users in the database currently
-func (userRepo *userRepository) GetUserCount() (int64, error) {
+func (userRepo *userRepository) GetUserCount(ctx context.Context) (int64, error) {
 	
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
 	defer cancel()
 
 	// count users in user collection currently
@@ -122,14 +216,56 @@ func (userRepo *userRepository) GetUserCount() (int64, error) {
 	return count, nil        // success
 }
 
+func (userRepo *userRepository) GetAdminCount(ctx context.Context) (int64, error) {
+
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	// count users with the admin role
+	count, err := userRepo.collection.CountDocuments(contx, activeUsers(bson.M{"role": "admin"}))
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil        // success
+}
+
+// find one page of users in the database, oldest first and without their password hashes
+func (userRepo *userRepository) GetAllUsers(ctx context.Context, skip, limit int64) ([]domain.User, error) {
+
+	var users []domain.User
+	contx, cancel := userRepo.timeouts.read(ctx)        // set timeout
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).        // stable order across pages
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"password": 0})            // hashes never leave the database
+	cursor, err := userRepo.collection.Find(contx, activeUsers(bson.M{}), opts)      // find a page of active users
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(contx)      // close cursor when done
+
+	if err := cursor.All(contx, &users); err != nil {
+		return nil, err
+	}
+	if users == nil {
+		return []domain.User{}, nil
+	}
+
+	return users, nil        // success
+}
+
 // update user role to admin in database (only admins can perform this operation)
-func (userRepo *userRepository) UpdateRole(id primitive.ObjectID, role string) error {
+func (userRepo *userRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
 	
 	if role == "" {
 		return errors.New("role cannot be empty")
 	}
 
-	contx, cancel := context.WithTimeout(context.Background(), 5*time.Second)        // set timeout
+	contx, cancel := userRepo.timeouts.write(ctx)        // set timeout
 	defer cancel()
 
 	// update user's role to admin
@@ -149,4 +285,87 @@ func (userRepo *userRepository) UpdateRole(id primitive.ObjectID, role string) e
 	}
 
 	return nil        // success
-}
\ No newline at end of file
+}
+
+// replace user's preferences in database
+func (userRepo *userRepository) UpdatePreferences(ctx context.Context, id primitive.ObjectID, prefs domain.Preferences) error {
+
+	contx, cancel := userRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	// overwrite the stored preferences
+	result := userRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"preferences": prefs}},
+	)
+
+	var updated domain.User
+
+	if err := result.Decode(&updated); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil        // success
+}
+
+// replace user's hashed password in database
+func (userRepo *userRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashed string) error {
+
+	if hashed == "" {
+		return errors.New("password cannot be empty")
+	}
+
+	contx, cancel := userRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	// store the new password hash, tokens issued before now stop being valid
+	result := userRepo.collection.FindOneAndUpdate(
+		contx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": hashed, "tokens_valid_after": time.Now()}},
+	)
+
+	var updated domain.User
+
+	if err := result.Decode(&updated); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil        // success
+}
+
+// soft delete user by id, the record stays so tasks can still resolve their owner
+func (userRepo *userRepository) SoftDeleteUser(ctx context.Context, id primitive.ObjectID) error {
+
+	contx, cancel := userRepo.timeouts.write(ctx)        // set timeout
+	defer cancel()
+
+	// tombstone the user, revoke their tokens and release their email for new registrations
+	now := time.Now()
+	result := userRepo.collection.FindOneAndUpdate(
+		contx,
+		activeUsers(bson.M{"_id": id}),
+		bson.M{
+			"$set":    bson.M{"deleted": true, "deleted_at": now, "tokens_valid_after": now},
+			"$rename": bson.M{"email": deletedEmailKey},
+		},
+	)
+
+	var deleted domain.User
+
+	if err := result.Decode(&deleted); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.ErrUserNotFound        // missing or already deleted
+		}
+		return err
+	}
+
+	return nil        // success
+}
@@ -14,6 +14,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // test suite for the UserRepository
@@ -44,7 +45,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_Success() {
         On("InsertOne", mock.Anything, mock.Anything).
         Return(&mongo.InsertOneResult{}, nil)
 
-    err := suite.repo.CreateUser(user)       // call CreateUser method
+    err := suite.repo.CreateUser(context.Background(), user)       // call CreateUser method
     assert.NoError(suite.T(), err)           // assert no error
     assert.NotZero(suite.T(), user.ID)       // assert ID is not zero
 }
@@ -66,10 +67,118 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_Duplicate() {
             WriteErrors: []mongo.WriteError{{Code: 11000}},
         })
 
-    err := suite.repo.CreateUser(user)                          // call CreateUser method
+    err := suite.repo.CreateUser(context.Background(), user)                          // call CreateUser method
     assert.ErrorIs(suite.T(), err, domain.ErrUserExists)        // assert error is ErrUserExists
 }
 
+// duplicate key write error as the server reports it, naming the violated keys
+func duplicateKeyError(keyPattern bson.M, message string) mongo.WriteException {
+	raw, _ := bson.Marshal(bson.M{"code": 11000, "errmsg": message, "keyPattern": keyPattern})
+	return mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 11000, Message: message, Raw: raw}},
+	}
+}
+
+// mocks the index view of the users collection
+type mockIndexes struct {
+	mock.Mock
+}
+
+// mocks CreateOne method of the index view
+func (m *mockIndexes) CreateOne(contx context.Context, model mongo.IndexModel, opts ...*options.CreateIndexesOptions) (string, error) {
+	args := m.Called(contx, model)
+	return args.String(0), args.Error(1)
+}
+
+// tests the unique email index leaves out legacy users without an email and deleted users
+func (suite *UserRepositoryTestSuite) TestEnsureUserIndexes_LegacyUsers() {
+
+	indexes := new(mockIndexes)
+	// deleted users give up their email before the index is built
+	suite.mockCollection.
+		On("UpdateMany", mock.Anything, bson.M{"deleted": true, "email": bson.M{"$exists": true}}, bson.M{"$rename": bson.M{"email": "deleted_email"}}).
+		Return(&mongo.UpdateResult{ModifiedCount: 1}, nil)
+	indexes.
+		On("CreateOne", mock.Anything, mock.MatchedBy(func(model mongo.IndexModel) bool {
+			return *model.Options.Unique && *model.Options.Name == "email_1" &&
+				assert.ObjectsAreEqual(bson.M{"email": bson.M{"$type": "string"}}, model.Options.PartialFilterExpression)      // users without an email are not indexed as null
+		})).
+		Return("email_1", nil)
+
+	err := ensureUserIndexes(suite.mockCollection, indexes, OperationTimeouts{})
+	assert.NoError(suite.T(), err)                   // startup goes on
+	indexes.AssertExpectations(suite.T())
+	suite.mockCollection.AssertExpectations(suite.T())
+}
+
+// tests a failing index build is reported so startup stops
+func (suite *UserRepositoryTestSuite) TestEnsureUserIndexes_Error() {
+
+	indexes := new(mockIndexes)
+	suite.mockCollection.On("UpdateMany", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+	indexes.On("CreateOne", mock.Anything, mock.Anything).Return("", errors.New("E11000 duplicate key error"))
+
+	err := ensureUserIndexes(suite.mockCollection, indexes, OperationTimeouts{})
+	assert.ErrorContains(suite.T(), err, "unique email index")       // error names the index
+}
+
+// tests CreateUser method of the UserRepository for duplicate email
+func (suite *UserRepositoryTestSuite) TestCreateUser_DuplicateEmail() {
+
+	user := &domain.User{Username: "new", Password: "securepass123", Email: "taken@example.com"}
+
+	// mock the InsertOne method of the collection to violate the email index
+	suite.mockCollection.
+		On("InsertOne", mock.Anything, mock.Anything).
+		Return(nil, duplicateKeyError(bson.M{"email": 1}, "E11000 duplicate key error collection: taskmanager.users index: email_1 dup key"))
+
+	err := suite.repo.CreateUser(context.Background(), user)                          // call CreateUser method
+	assert.ErrorIs(suite.T(), err, domain.ErrEmailExists)       // assert error is ErrEmailExists
+}
+
+// tests a duplicate on another index is not taken for a duplicate email because its message mentions one
+func (suite *UserRepositoryTestSuite) TestCreateUser_DuplicateOtherIndex() {
+
+	user := &domain.User{Username: "email", Password: "securepass123", Email: "new@example.com"}
+
+	// mock the InsertOne method of the collection to violate a username index
+	suite.mockCollection.
+		On("InsertOne", mock.Anything, mock.Anything).
+		Return(nil, duplicateKeyError(bson.M{"username": 1}, `E11000 duplicate key error collection: taskmanager.users index: username_1 dup key: { username: "email" }`))
+
+	err := suite.repo.CreateUser(context.Background(), user)                          // call CreateUser method
+	assert.ErrorIs(suite.T(), err, domain.ErrUserExists)        // the key pattern decides, not the text
+}
+
+// tests GetByEmail method of the UserRepository for existing user
+func (suite *UserRepositoryTestSuite) TestGetByEmail_Success() {
+
+	email := "john@example.com"
+	expected := domain.User{Username: "john", Email: email}
+
+	// mock the FindOne method of the collection
+	suite.mockCollection.
+		On("FindOne", mock.Anything, bson.M{"email": email, "deleted": bson.M{"$ne": true}}).
+		Return(&mock_repositories.MockSingleResult{Result: &expected})
+
+	user, err := suite.repo.GetByEmail(context.Background(), email)            // call GetByEmail method
+	assert.NoError(suite.T(), err)                       // assert no error
+	assert.Equal(suite.T(), "john", user.Username)       // assert user matches
+}
+
+// tests GetByEmail method of the UserRepository for non-existing user
+func (suite *UserRepositoryTestSuite) TestGetByEmail_NotFound() {
+
+	// mock the FindOne method of the collection
+	suite.mockCollection.
+		On("FindOne", mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	user, err := suite.repo.GetByEmail(context.Background(), "nobody@example.com")     // call GetByEmail method
+	assert.Nil(suite.T(), user)                                  // assert user is nil
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // assert error is ErrUserNotFound
+}
+
 // tests CreateUser method of the UserRepository for error case
 func (suite *UserRepositoryTestSuite) TestCreateUser_Error() {
     
@@ -85,7 +194,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_Error() {
         On("InsertOne", mock.Anything, mock.Anything).
         Return(nil, errors.New("insert error"))
 
-    err := suite.repo.CreateUser(user)                     // call CreateUser method
+    err := suite.repo.CreateUser(context.Background(), user)                     // call CreateUser method
     assert.EqualError(suite.T(), err, "insert error")      // assert error message matches
 }
 
@@ -104,7 +213,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_ContextTimeout() {
         On("InsertOne", mock.Anything, user).
         Return(nil, context.DeadlineExceeded)
 
-    err := suite.repo.CreateUser(user)                            // call CreateUser method 
+    err := suite.repo.CreateUser(context.Background(), user)                            // call CreateUser method 
     assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)      // assert error is context deadline exceeded
 }
 
@@ -122,10 +231,10 @@ func (suite *UserRepositoryTestSuite) TestGetByUsername_Success() {
 
 	// mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"username": username}).
+        On("FindOne", mock.Anything, bson.M{"username": username, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: nil, Result: &expected})
 
-    user, err := suite.repo.GetByUsername(username)        // call GetByUsername method
+    user, err := suite.repo.GetByUsername(context.Background(), username)        // call GetByUsername method
     assert.NoError(suite.T(), err)                         // assert no error
     assert.Equal(suite.T(), username, user.Username)       // assert username matches
 }
@@ -138,10 +247,10 @@ func (suite *UserRepositoryTestSuite) TestGetByUsername_NotFound() {
 
 	// mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"username": username}).
+        On("FindOne", mock.Anything, bson.M{"username": username, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
 
-    user, err := suite.repo.GetByUsername(username)              // call GetByUsername method
+    user, err := suite.repo.GetByUsername(context.Background(), username)              // call GetByUsername method
     assert.Nil(suite.T(), user)                                  // assert user is nil
     assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // assert error is ErrUserNotFound
 }
@@ -149,7 +258,7 @@ func (suite *UserRepositoryTestSuite) TestGetByUsername_NotFound() {
 // tests GetByUsername method of the UserRepository for empty username
 func (suite *UserRepositoryTestSuite) TestGetByUsername_EmptyUsername() {
     
-    user, err := suite.repo.GetByUsername("")                               // call GetByUsername method
+    user, err := suite.repo.GetByUsername(context.Background(), "")                               // call GetByUsername method
     assert.Nil(suite.T(), user)                                             // assert user is nil
     assert.ErrorContains(suite.T(), err, "username cannot be empty")        // assert error contains message
 }
@@ -163,10 +272,10 @@ func (suite *UserRepositoryTestSuite) TestGetUserById_Success() {
 
     // mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"_id": id}).
+        On("FindOne", mock.Anything, bson.M{"_id": id, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: nil, Result: &expected})
 
-    user, err := suite.repo.GetUserById(id)              // call GetUserById method
+    user, err := suite.repo.GetUserById(context.Background(), id)              // call GetUserById method
     assert.NoError(suite.T(), err)                       // assert no error
     assert.Equal(suite.T(), id, user.ID)                 // assert ID matches
 }
@@ -179,10 +288,10 @@ func (suite *UserRepositoryTestSuite) TestGetUserById_NotFound() {
 
     // mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"_id": id}).
+        On("FindOne", mock.Anything, bson.M{"_id": id, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
 
-    user, err := suite.repo.GetUserById(id)                      // call GetUserById method
+    user, err := suite.repo.GetUserById(context.Background(), id)                      // call GetUserById method
     assert.Nil(suite.T(), user)                                  // assert user is nil
     assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // assert error is ErrUserNotFound
 }
@@ -195,11 +304,24 @@ func (suite *UserRepositoryTestSuite) TestGetUserCount_Success() {
         On("CountDocuments", mock.Anything, bson.M{}).
         Return(int64(42), nil)
 
-    count, err := suite.repo.GetUserCount()         // call GetUserCount method
+    count, err := suite.repo.GetUserCount(context.Background())         // call GetUserCount method
     assert.NoError(suite.T(), err)                  // assert no error
     assert.Equal(suite.T(), int64(42), count)       // assert count matches
 }
 
+// tests GetAdminCount method of the UserRepository counts only admins
+func (suite *UserRepositoryTestSuite) TestGetAdminCount_Success() {
+
+	// mock the CountDocuments method of the collection
+	suite.mockCollection.
+		On("CountDocuments", mock.Anything, bson.M{"role": "admin", "deleted": bson.M{"$ne": true}}).
+		Return(int64(2), nil)
+
+	count, err := suite.repo.GetAdminCount(context.Background())        // call GetAdminCount method
+	assert.NoError(suite.T(), err)                  // assert no error
+	assert.Equal(suite.T(), int64(2), count)        // assert count matches
+}
+
 // tests GetUserCount method of the UserRepository for error case
 func (suite *UserRepositoryTestSuite) TestGetUserCount_Error() {
     
@@ -208,7 +330,7 @@ func (suite *UserRepositoryTestSuite) TestGetUserCount_Error() {
         On("CountDocuments", mock.Anything, bson.M{}).
         Return(int64(0), errors.New("count error"))
 
-    count, err := suite.repo.GetUserCount()               // call GetUserCount method
+    count, err := suite.repo.GetUserCount(context.Background())               // call GetUserCount method
     assert.Equal(suite.T(), int64(0), count)              // assert count is zero
     assert.EqualError(suite.T(), err, "count error")      // assert error message matches
 }
@@ -221,7 +343,7 @@ func (suite *UserRepositoryTestSuite) TestGetUserCount_ZeroUsers() {
         On("CountDocuments", mock.Anything, bson.M{}).
         Return(int64(0), nil)
 
-    count, err := suite.repo.GetUserCount()      // call GetUserCount method
+    count, err := suite.repo.GetUserCount(context.Background())      // call GetUserCount method
     assert.NoError(suite.T(), err)               // assert no error
     assert.Zero(suite.T(), count)                // assert count zero
 }
@@ -234,10 +356,10 @@ func (suite *UserRepositoryTestSuite) TestGetByUsername_Error() {
 
     // mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"username": username}).
+        On("FindOne", mock.Anything, bson.M{"username": username, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: errors.New("find error")})
 
-    user, err := suite.repo.GetByUsername(username)       // call GetByUsername method
+    user, err := suite.repo.GetByUsername(context.Background(), username)       // call GetByUsername method
     assert.Nil(suite.T(), user)                           // assert user is nil
     assert.EqualError(suite.T(), err, "find error")       // assert error message matches
 }
@@ -250,14 +372,131 @@ func (suite *UserRepositoryTestSuite) TestGetUserById_Error() {
 
     // mock the FindOne method of the collection
     suite.mockCollection.
-        On("FindOne", mock.Anything, bson.M{"_id": id}).
+        On("FindOne", mock.Anything, bson.M{"_id": id, "deleted": bson.M{"$ne": true}}).
         Return(&mock_repositories.MockSingleResult{Err: errors.New("find error")})
 
-    user, err := suite.repo.GetUserById(id)               // call GetUserById method
+    user, err := suite.repo.GetUserById(context.Background(), id)               // call GetUserById method
     assert.Nil(suite.T(), user)                           // assert user is nil
     assert.EqualError(suite.T(), err, "find error")       // assert error message matches
 }
 
+// tests GetAllUsers method of the UserRepository
+func (suite *UserRepositoryTestSuite) TestGetAllUsers_Success() {
+
+	// create a cursor with two users
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		domain.User{ID: primitive.NewObjectID(), Username: "alice"},
+		domain.User{ID: primitive.NewObjectID(), Username: "bob"},
+	}, nil, nil)
+
+	// mock the Find method of the collection
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(cursor, nil)
+
+	users, err := suite.repo.GetAllUsers(context.Background(), 0, 20)      // call GetAllUsers method
+	assert.NoError(suite.T(), err)                   // assert no error
+	assert.Len(suite.T(), users, 2)                  // assert both users decoded
+}
+
+// tests GetAllUsers method of the UserRepository pages in a stable order and leaves out passwords
+func (suite *UserRepositoryTestSuite) TestGetAllUsers_Page() {
+
+	cursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+
+	// mock the Find method of the collection with the expected options
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(opts []*options.FindOptions) bool {
+			return len(opts) == 1 && *opts[0].Skip == 40 && *opts[0].Limit == 20 &&
+				assert.ObjectsAreEqual(bson.D{{Key: "_id", Value: 1}}, opts[0].Sort) &&
+				assert.ObjectsAreEqual(bson.M{"password": 0}, opts[0].Projection)
+		})).
+		Return(cursor, nil)
+
+	users, err := suite.repo.GetAllUsers(context.Background(), 40, 20)     // call GetAllUsers method for the third page
+	assert.NoError(suite.T(), err)                   // assert no error
+	assert.Empty(suite.T(), users)                   // assert empty page
+	suite.mockCollection.AssertExpectations(suite.T())     // assert skip, limit and projection were applied
+}
+
+// tests GetAllUsers method of the UserRepository for error case
+func (suite *UserRepositoryTestSuite) TestGetAllUsers_Error() {
+
+	// mock the Find method of the collection to fail
+	suite.mockCollection.
+		On("Find", mock.Anything, bson.M{"deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(nil, errors.New("find error"))
+
+	users, err := suite.repo.GetAllUsers(context.Background(), 0, 20)      // call GetAllUsers method
+	assert.Nil(suite.T(), users)                     // assert users is nil
+	assert.EqualError(suite.T(), err, "find error")  // assert error message matches
+}
+
+// tests UpdatePreferences method of the UserRepository for existing user
+func (suite *UserRepositoryTestSuite) TestUpdatePreferences_Success() {
+
+	// create a new object ID and preferences
+	id := primitive.NewObjectID()
+	prefs := domain.Preferences{DefaultPriority: "high"}
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"preferences": prefs}}).
+		Return(&mock_repositories.MockSingleResult{Err: nil, Result: &domain.User{ID: id, Preferences: prefs}})
+
+	err := suite.repo.UpdatePreferences(context.Background(), id, prefs)       // call UpdatePreferences method
+	assert.NoError(suite.T(), err)                       // assert no error
+}
+
+// tests UpdatePreferences method of the UserRepository for non-existing user
+func (suite *UserRepositoryTestSuite) TestUpdatePreferences_NotFound() {
+
+	// create a new object ID
+	id := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	err := suite.repo.UpdatePreferences(context.Background(), id, domain.Preferences{})       // call UpdatePreferences method
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)              // assert error is ErrUserNotFound
+}
+
+// tests UpdatePassword method of the UserRepository for existing user
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_Success() {
+
+	// create a new object ID
+	id := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			_, stamped := set["tokens_valid_after"].(time.Time)
+			return set["password"] == "newhash" && stamped        // old tokens are revoked with the password
+		})).
+		Return(&mock_repositories.MockSingleResult{Err: nil, Result: &domain.User{ID: id, Password: "newhash"}})
+
+	err := suite.repo.UpdatePassword(context.Background(), id, "newhash")       // call UpdatePassword method
+	assert.NoError(suite.T(), err)                        // assert no error
+}
+
+// tests UpdatePassword method of the UserRepository for non-existing user
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_NotFound() {
+
+	// create a new object ID
+	id := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	err := suite.repo.UpdatePassword(context.Background(), id, "newhash")              // call UpdatePassword method
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // assert error is ErrUserNotFound
+}
+
 // tests UpdateRole method of the UserRepository for existing user
 func (suite *UserRepositoryTestSuite) TestUpdateRole_Success() {
     
@@ -271,7 +510,7 @@ func (suite *UserRepositoryTestSuite) TestUpdateRole_Success() {
         On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}}).
         Return(&mock_repositories.MockSingleResult{Err: nil, Result: &domain.User{ID: id, Role: role}})
 
-    err := suite.repo.UpdateRole(id, role)        // call UpdateRole method
+    err := suite.repo.UpdateRole(context.Background(), id, role)        // call UpdateRole method
 	assert.NoError(suite.T(), err)                // assert no error
     assert.NoError(suite.T(), err)                // assert no error
 }
@@ -289,7 +528,7 @@ func (suite *UserRepositoryTestSuite) TestUpdateRole_NotFound() {
         On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}}).
         Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
 
-    err := suite.repo.UpdateRole(id, role)                       // call UpdateRole method
+    err := suite.repo.UpdateRole(context.Background(), id, role)                       // call UpdateRole method
 	assert.Error(suite.T(), err)                                 // assert error is returned
     assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // assert error is ErrUserNotFound
 }
@@ -307,7 +546,7 @@ func (suite *UserRepositoryTestSuite) TestUpdateRole_Error() {
         On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}}).
         Return(&mock_repositories.MockSingleResult{Err: errors.New("db error")})
 
-    err := suite.repo.UpdateRole(id, role)                       // call UpdateRole method
+    err := suite.repo.UpdateRole(context.Background(), id, role)                       // call UpdateRole method
     assert.Error(suite.T(), err)                                 // assert error is returned
     assert.Equal(suite.T(), err.Error(), "db error")             // assert error message
 }
@@ -315,7 +554,7 @@ func (suite *UserRepositoryTestSuite) TestUpdateRole_Error() {
 // tests UpdateRole method of the UserRepository for empty role
 func (suite *UserRepositoryTestSuite) TestUpdateRole_EmptyRole() {
 
-    err := suite.repo.UpdateRole(primitive.NewObjectID(), "")           // call UpdateRole method 
+    err := suite.repo.UpdateRole(context.Background(), primitive.NewObjectID(), "")           // call UpdateRole method 
     assert.ErrorContains(suite.T(), err, "role cannot be empty")        // assert error contains message
 }
 
@@ -332,11 +571,61 @@ func (suite *UserRepositoryTestSuite) TestUpdateRole_InvalidRole() {
         On("FindOneAndUpdate", mock.Anything, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}}).
         Return(&mock_repositories.MockSingleResult{Err: errors.New("invalid role")})
 
-    err := suite.repo.UpdateRole(id, role)                     // call UpdateRole method
+    err := suite.repo.UpdateRole(context.Background(), id, role)                     // call UpdateRole method
     assert.Error(suite.T(), err)                               // assert error is returned
     assert.Equal(suite.T(), err.Error(), "invalid role")       // assert error message
 }
 
+// tests SoftDeleteUser method of the UserRepository for non-existing or already deleted user
+func (suite *UserRepositoryTestSuite) TestSoftDeleteUser_NotFound() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of the collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: mongo.ErrNoDocuments})
+
+	err := suite.repo.SoftDeleteUser(context.Background(), objID)                // call SoftDeleteUser method
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound) // assert error is ErrUserNotFound
+}
+
+// tests SoftDeleteUser method of the UserRepository tombstones the user instead of removing it
+func (suite *UserRepositoryTestSuite) TestSoftDeleteUser_Success() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of collection
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, bson.M{"_id": objID, "deleted": bson.M{"$ne": true}}, mock.MatchedBy(func(update bson.M) bool {
+			set := update["$set"].(bson.M)
+			return set["deleted"] == true && !set["deleted_at"].(time.Time).IsZero() && set["tokens_valid_after"] == set["deleted_at"] &&
+				assert.ObjectsAreEqual(bson.M{"email": "deleted_email"}, update["$rename"])        // email free to register again
+		})).
+		Return(&mock_repositories.MockSingleResult{Result: &domain.User{ID: objID}})
+
+	err := suite.repo.SoftDeleteUser(context.Background(), objID)       // call SoftDeleteUser method
+	assert.NoError(suite.T(), err)                // assert no error
+	suite.mockCollection.AssertNotCalled(suite.T(), "DeleteOne", mock.Anything, mock.Anything)      // record is kept
+}
+
+// tests SoftDeleteUser method of the UserRepository with a database error
+func (suite *UserRepositoryTestSuite) TestSoftDeleteUser_Error() {
+
+	// create a new object ID
+	objID := primitive.NewObjectID()
+
+	// mock the FindOneAndUpdate method of collection to fail
+	suite.mockCollection.
+		On("FindOneAndUpdate", mock.Anything, mock.Anything, mock.Anything).
+		Return(&mock_repositories.MockSingleResult{Err: errors.New("db down")})
+
+	err := suite.repo.SoftDeleteUser(context.Background(), objID)              // call SoftDeleteUser method
+	assert.EqualError(suite.T(), err, "db down")         // assert error passed through
+}
+
 // suite entry point for running the tests
 func TestUserRepositoryTestSuite(t *testing.T) {
     suite.Run(t, new(UserRepositoryTestSuite))        // run the test suite
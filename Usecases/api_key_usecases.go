@@ -0,0 +1,147 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// raw keys look like tmk_<key id>_<secret>, the id finds the stored hash
+const apiKeyPrefix = "tmk"
+
+// random bytes in the secret part of a key
+const apiKeySecretBytes = 24
+
+// characters of the secret kept in clear text to identify a key in listings
+const apiKeyPrefixLength = 8
+
+type apiKeyUseCase struct {
+	keyRepo     domain.APIKeyRepository
+	userRepo    domain.UserRepository         // finds the active owner of a key
+	pwdService  domain.PasswordService        // hashes secrets like passwords
+	now         func() time.Time              // clock used for expiry
+}
+
+// new api key usecase
+func NewAPIKeyUseCase(keyRepo domain.APIKeyRepository, userRepo domain.UserRepository, pwdServ domain.PasswordService) domain.APIKeyUseCase {
+	return &apiKeyUseCase{keyRepo: keyRepo, userRepo: userRepo, pwdService: pwdServ, now: time.Now}
+}
+
+// mint a key for the admin ownerID, the raw key is returned only here
+func (keyUsc *apiKeyUseCase) CreateAPIKey(ctx context.Context, ownerID, role string, ttl time.Duration) (string, *domain.APIKey, error) {
+
+	// validate input
+	ownerObjID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return "", nil, domain.ErrInvalidUserID
+	}
+	if role != "admin" && role != "user" {
+		return "", nil, errors.New("invalid role")
+	}
+	if ttl < 0 {
+		return "", nil, errors.New("expiry must not be negative")
+	}
+
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", nil, err
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	hashed, err := keyUsc.pwdService.HashPassword(secret)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := keyUsc.now()
+	key := &domain.APIKey{
+		ID:        primitive.NewObjectID(),
+		Hash:      hashed,
+		Prefix:    secret[:apiKeyPrefixLength],
+		Role:      role,
+		OwnerID:   ownerObjID,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		key.ExpiresAt = now.Add(ttl)
+	}
+	if err := keyUsc.keyRepo.CreateAPIKey(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	return apiKeyPrefix + "_" + key.ID.Hex() + "_" + secret, key, nil
+}
+
+// find the key a raw value belongs to, every failure is reported as ErrInvalidAPIKey
+func (keyUsc *apiKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+
+	parts := strings.Split(rawKey, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[2] == "" {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := keyUsc.keyRepo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKey) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err        // database failures are not the client's fault
+	}
+
+	// expired keys fail before the costly hash comparison
+	if !key.ExpiresAt.IsZero() && !keyUsc.now().Before(key.ExpiresAt) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if !keyUsc.pwdService.CheckPassword(key.Hash, parts[2]) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// keys stop working with their owner, deleted users are not found
+	owner, err := keyUsc.userRepo.GetUserById(ctx, key.OwnerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	// a demoted owner's admin keys act as the owner does now
+	if key.Role == "admin" && owner.Role != "admin" {
+		key.Role = owner.Role
+	}
+
+	return key, nil
+}
+
+// list the keys an admin minted, only the prefix of each secret is stored
+func (keyUsc *apiKeyUseCase) ListAPIKeys(ctx context.Context, ownerID string) ([]domain.APIKey, error) {
+
+	// validate input
+	ownerObjID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	return keyUsc.keyRepo.GetAPIKeysByOwner(ctx, ownerObjID)
+}
+
+// revoke a key, requests made with it fail from now on
+func (keyUsc *apiKeyUseCase) RevokeAPIKey(ctx context.Context, keyID string) error {
+
+	// validate input
+	id, err := primitive.ObjectIDFromHex(keyID)
+	if err != nil {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return keyUsc.keyRepo.RevokeAPIKey(ctx, id, keyUsc.now())
+}
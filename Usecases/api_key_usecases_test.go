@@ -0,0 +1,202 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure/mocks"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// test suite for APIKeyUseCase
+type APIKeyUseCaseTestSuite struct {
+	suite.Suite
+	keyRepo      *mock_repositories.MockAPIKeyRepository       // mock api key repository instance
+	userRepo     *mock_repositories.MockUserRepository         // mock user repository instance
+	pwdService   *mock_infrastructure.MockPasswordService      // mock password service instance
+	now          time.Time                                     // fixed clock for the tests
+	usecase      *apiKeyUseCase                                // api key usecase instance being tested
+}
+
+// initializes the test environment before each test
+func (suite *APIKeyUseCaseTestSuite) SetupTest() {
+	suite.keyRepo = new(mock_repositories.MockAPIKeyRepository)          // create new mock api key repository
+	suite.userRepo = new(mock_repositories.MockUserRepository)           // create new mock user repository
+	suite.pwdService = new(mock_infrastructure.MockPasswordService)      // create new mock password service
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.usecase = NewAPIKeyUseCase(suite.keyRepo, suite.userRepo, suite.pwdService).(*apiKeyUseCase)
+	suite.usecase.now = func() time.Time { return suite.now }
+}
+
+// tests a minted key is stored hashed and can be parsed back
+func (suite *APIKeyUseCaseTestSuite) TestCreateAPIKey_Success() {
+
+	owner := primitive.NewObjectID()
+
+	// mock HashPassword and CreateAPIKey
+	suite.pwdService.On("HashPassword", mock.AnythingOfType("string")).Return("hashed", nil)
+	suite.keyRepo.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(key *domain.APIKey) bool {
+		return key.Hash == "hashed" && key.Role == "user" && key.OwnerID == owner
+	})).Return(nil)
+
+	// call the CreateAPIKey method on usecase
+	raw, key, err := suite.usecase.CreateAPIKey(context.Background(), owner.Hex(), "user", 24*time.Hour)
+	assert.NoError(suite.T(), err)                                            // no error expected
+	assert.True(suite.T(), strings.HasPrefix(raw, "tmk_"+key.ID.Hex()+"_"+key.Prefix))   // prefix is the start of the secret
+	assert.Len(suite.T(), key.Prefix, apiKeyPrefixLength)                     // only a short prefix is kept
+	assert.Equal(suite.T(), suite.now.Add(24*time.Hour), key.ExpiresAt)       // expiry from the lifetime
+	suite.pwdService.AssertNotCalled(suite.T(), "HashPassword", raw)          // only the secret is hashed
+}
+
+// tests an invalid role is rejected
+func (suite *APIKeyUseCaseTestSuite) TestCreateAPIKey_InvalidRole() {
+
+	// call the CreateAPIKey method on usecase
+	_, _, err := suite.usecase.CreateAPIKey(context.Background(), primitive.NewObjectID().Hex(), "root", 0)
+	assert.EqualError(suite.T(), err, "invalid role")                          // error message should match
+	suite.keyRepo.AssertNotCalled(suite.T(), "CreateAPIKey", mock.Anything, mock.Anything)    // nothing stored
+}
+
+// tests a valid key authenticates
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_Valid() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), Hash: "hashed", Role: "user", OwnerID: primitive.NewObjectID(), ExpiresAt: suite.now.Add(time.Hour)}
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, key.ID).Return(key, nil)
+	suite.pwdService.On("CheckPassword", "hashed", "secret").Return(true)
+	suite.userRepo.On("GetUserById", mock.Anything, key.OwnerID).Return(&domain.User{ID: key.OwnerID, Role: "admin"}, nil)
+
+	// call the Authenticate method on usecase
+	found, err := suite.usecase.Authenticate(context.Background(), "tmk_" + key.ID.Hex() + "_secret")
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), key, found)             // stored key is returned
+}
+
+// tests a key of a deleted owner is rejected
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_DeletedOwner() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), Hash: "hashed", Role: "user", OwnerID: primitive.NewObjectID()}
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, key.ID).Return(key, nil)
+	suite.pwdService.On("CheckPassword", "hashed", "secret").Return(true)
+	suite.userRepo.On("GetUserById", mock.Anything, key.OwnerID).Return(nil, domain.ErrUserNotFound)
+
+	// call the Authenticate method on usecase
+	_, err := suite.usecase.Authenticate(context.Background(), "tmk_" + key.ID.Hex() + "_secret")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)        // owner gone, key with them
+}
+
+// tests an admin key of a demoted owner only acts as a user
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_DemotedOwner() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), Hash: "hashed", Role: "admin", OwnerID: primitive.NewObjectID()}
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, key.ID).Return(key, nil)
+	suite.pwdService.On("CheckPassword", "hashed", "secret").Return(true)
+	suite.userRepo.On("GetUserById", mock.Anything, key.OwnerID).Return(&domain.User{ID: key.OwnerID, Role: "user"}, nil)
+
+	// call the Authenticate method on usecase
+	found, err := suite.usecase.Authenticate(context.Background(), "tmk_" + key.ID.Hex() + "_secret")
+	assert.NoError(suite.T(), err)                          // key still works
+	assert.Equal(suite.T(), "user", found.Role)             // capped at the owner's current role
+}
+
+// tests an expired key is rejected without checking the secret
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_Expired() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), Hash: "hashed", Role: "user", ExpiresAt: suite.now}
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, key.ID).Return(key, nil)
+
+	// call the Authenticate method on usecase
+	_, err := suite.usecase.Authenticate(context.Background(), "tmk_" + key.ID.Hex() + "_secret")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)                                        // expired at this instant
+	suite.pwdService.AssertNotCalled(suite.T(), "CheckPassword", mock.Anything, mock.Anything)
+}
+
+// tests a wrong secret is rejected
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_WrongSecret() {
+
+	key := &domain.APIKey{ID: primitive.NewObjectID(), Hash: "hashed", Role: "user"}
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, key.ID).Return(key, nil)
+	suite.pwdService.On("CheckPassword", "hashed", "guess").Return(false)
+
+	// call the Authenticate method on usecase
+	_, err := suite.usecase.Authenticate(context.Background(), "tmk_" + key.ID.Hex() + "_guess")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)        // error should be invalid api key
+}
+
+// tests malformed and unknown keys are rejected
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_Unknown() {
+
+	for _, raw := range []string{"", "tmk", "tmk_nothex_secret", "abc_" + primitive.NewObjectID().Hex() + "_secret"} {
+		_, err := suite.usecase.Authenticate(context.Background(), raw)
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey, raw)       // malformed key
+	}
+
+	id := primitive.NewObjectID()
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, id).Return(nil, domain.ErrInvalidAPIKey)
+	_, err := suite.usecase.Authenticate(context.Background(), "tmk_" + id.Hex() + "_secret")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAPIKey)                // unknown key
+}
+
+// tests database failures are not reported as invalid keys
+func (suite *APIKeyUseCaseTestSuite) TestAuthenticate_DatabaseError() {
+
+	id := primitive.NewObjectID()
+	suite.keyRepo.On("GetAPIKeyByID", mock.Anything, id).Return(nil, errors.New("db down"))
+
+	_, err := suite.usecase.Authenticate(context.Background(), "tmk_" + id.Hex() + "_secret")
+	assert.EqualError(suite.T(), err, "db down")        // error passed through
+}
+
+// tests an admin's keys are listed
+func (suite *APIKeyUseCaseTestSuite) TestListAPIKeys_Success() {
+
+	owner := primitive.NewObjectID()
+	keys := []domain.APIKey{{ID: primitive.NewObjectID(), Prefix: "0a1b2c3d", OwnerID: owner}}
+	suite.keyRepo.On("GetAPIKeysByOwner", mock.Anything, owner).Return(keys, nil)
+
+	// call the ListAPIKeys method on usecase
+	listed, err := suite.usecase.ListAPIKeys(context.Background(), owner.Hex())
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), keys, listed)           // keys of the owner
+
+	_, err = suite.usecase.ListAPIKeys(context.Background(), "invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)       // invalid owner id
+}
+
+// tests a key is revoked at the current time
+func (suite *APIKeyUseCaseTestSuite) TestRevokeAPIKey_Success() {
+
+	id := primitive.NewObjectID()
+	suite.keyRepo.On("RevokeAPIKey", mock.Anything, id, suite.now).Return(nil)
+
+	// call the RevokeAPIKey method on usecase
+	err := suite.usecase.RevokeAPIKey(context.Background(), id.Hex())
+	assert.NoError(suite.T(), err)                  // no error expected
+	suite.keyRepo.AssertExpectations(suite.T())     // revoked now
+}
+
+// tests revoking an unknown key fails
+func (suite *APIKeyUseCaseTestSuite) TestRevokeAPIKey_NotFound() {
+
+	id := primitive.NewObjectID()
+	suite.keyRepo.On("RevokeAPIKey", mock.Anything, id, suite.now).Return(domain.ErrAPIKeyNotFound)
+
+	err := suite.usecase.RevokeAPIKey(context.Background(), id.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrAPIKeyNotFound)        // unknown key
+
+	err = suite.usecase.RevokeAPIKey(context.Background(), "invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrAPIKeyNotFound)        // invalid id
+}
+
+// runs the test suite for APIKeyUseCase
+func TestAPIKeyUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyUseCaseTestSuite))
+}
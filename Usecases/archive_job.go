@@ -0,0 +1,54 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"log"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// periodically archives tasks that were completed long ago
+type ArchiveJob struct {
+	taskRepo  domain.TaskRepository
+	after     time.Duration          // how long after completion a task is archived
+	interval  time.Duration          // how often the job runs
+	now       func() time.Time       // clock used for the cutoff
+}
+
+// creates a new archive job, now may be nil to use the real clock
+func NewArchiveJob(repo domain.TaskRepository, after, interval time.Duration, now func() time.Time) *ArchiveJob {
+	if now == nil {
+		now = time.Now        // default to the real clock
+	}
+	return &ArchiveJob{taskRepo: repo, after: after, interval: interval, now: now}
+}
+
+// archives every task completed more than the configured duration ago
+func (job *ArchiveJob) RunOnce() (int64, error) {
+	cutoff := job.now().Add(-job.after)
+	return job.taskRepo.ArchiveCompletedBefore(context.Background(), cutoff)      // no request to follow, the repository timeout applies
+}
+
+// runs the job on every interval until stop is closed
+func (job *ArchiveJob) Start(stop <-chan struct{}) {
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			archived, err := job.RunOnce()
+			if err != nil {
+				log.Printf("task auto-archive failed: %v", err)
+				continue
+			}
+			if archived > 0 {
+				log.Printf("task auto-archive: archived %d tasks", archived)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for ArchiveJob
+type ArchiveJobTestSuite struct {
+	suite.Suite
+	mockRepo  *mock_repositories.MockTaskRepository      // mock task repository instance
+	now       time.Time                                  // fixed clock for the tests
+	job       *ArchiveJob                                // archive job being tested
+}
+
+// intialize the test suite before each test
+func (suite *ArchiveJobTestSuite) SetupTest() {
+	suite.mockRepo = new(mock_repositories.MockTaskRepository)
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.job = NewArchiveJob(suite.mockRepo, 30*24*time.Hour, time.Hour, func() time.Time { return suite.now })
+}
+
+// in-memory task store flipping completed tasks to archived like the repository does
+type archivingRepo struct {
+	*mock_repositories.MockTaskRepository
+	tasks []domain.Task
+}
+
+// archives stored tasks completed before the cutoff
+func (repo *archivingRepo) ArchiveCompletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+
+	var archived int64
+	for i := range repo.tasks {
+		if repo.tasks[i].Status == "completed" && repo.tasks[i].CompletedAt.Before(cutoff) {
+			repo.tasks[i].Status = "archived"
+			archived++
+		}
+	}
+
+	return archived, nil
+}
+
+// tests the job archives with a cutoff of now minus the configured duration
+func (suite *ArchiveJobTestSuite) TestRunOnce_Cutoff() {
+
+	cutoff := suite.now.Add(-30 * 24 * time.Hour)
+	suite.mockRepo.On("ArchiveCompletedBefore", mock.Anything, cutoff).Return(int64(2), nil)
+
+	archived, err := suite.job.RunOnce()
+	assert.NoError(suite.T(), err)                    // no error expected
+	assert.Equal(suite.T(), int64(2), archived)       // repository count is returned
+	suite.mockRepo.AssertExpectations(suite.T())      // verify cutoff
+}
+
+// tests the job flips only old completed tasks to archived
+func (suite *ArchiveJobTestSuite) TestRunOnce_FlipsMatchingTasks() {
+
+	repo := &archivingRepo{tasks: []domain.Task{
+		{Title: "old", Status: "completed", CompletedAt: suite.now.AddDate(0, 0, -45)},
+		{Title: "recent", Status: "completed", CompletedAt: suite.now.AddDate(0, 0, -5)},
+		{Title: "open", Status: "pending"},
+	}}
+	job := NewArchiveJob(repo, 30*24*time.Hour, time.Hour, func() time.Time { return suite.now })
+
+	archived, err := job.RunOnce()
+	assert.NoError(suite.T(), err)                               // no error expected
+	assert.Equal(suite.T(), int64(1), archived)                  // only the old completed task matches
+	assert.Equal(suite.T(), "archived", repo.tasks[0].Status)    // old completed task is archived
+	assert.Equal(suite.T(), "completed", repo.tasks[1].Status)   // recently completed task is kept
+	assert.Equal(suite.T(), "pending", repo.tasks[2].Status)     // open task is untouched
+}
+
+// tests repository errors are returned
+func (suite *ArchiveJobTestSuite) TestRunOnce_Error() {
+
+	suite.mockRepo.On("ArchiveCompletedBefore", mock.Anything, mock.Anything).Return(int64(0), errors.New("update error"))
+
+	_, err := suite.job.RunOnce()
+	assert.EqualError(suite.T(), err, "update error")      // error should be passed through
+}
+
+// runs the test suite for ArchiveJob
+func TestArchiveJobTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveJobTestSuite))
+}
@@ -0,0 +1,59 @@
+package mock_usecases
+
+// imports
+import (
+	"context"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// mocks the APIKeyUseCase interface for testing
+type MockAPIKeyUseCase struct {
+	mock.Mock
+}
+
+// mocks CreateAPIKey method of APIKeyUseCase interface
+func (mckuc *MockAPIKeyUseCase) CreateAPIKey(contx context.Context, ownerID, role string, ttl time.Duration) (string, *domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mckuc.Called(contx, ownerID, role, ttl)
+	var key *domain.APIKey
+	if args.Get(1) != nil {
+		key = args.Get(1).(*domain.APIKey)
+	}
+
+	return args.String(0), key, args.Error(2)
+}
+
+// mocks Authenticate method of APIKeyUseCase interface
+func (mckuc *MockAPIKeyUseCase) Authenticate(contx context.Context, rawKey string) (*domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mckuc.Called(contx, rawKey)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks ListAPIKeys method of APIKeyUseCase interface
+func (mckuc *MockAPIKeyUseCase) ListAPIKeys(contx context.Context, ownerID string) ([]domain.APIKey, error) {
+
+	// call the mocked method and return the result
+	args := mckuc.Called(contx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.APIKey), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks RevokeAPIKey method of APIKeyUseCase interface
+func (mckuc *MockAPIKeyUseCase) RevokeAPIKey(contx context.Context, keyID string) error {
+
+	// call the mocked method and return the result
+	args := mckuc.Called(contx, keyID)
+	return args.Error(0)
+}
@@ -0,0 +1,82 @@
+package mock_usecases
+
+// imports
+import (
+	"context"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// mocks the ProjectUseCase interface for testing
+type MockProjectUseCase struct {
+	mock.Mock
+}
+
+// mocks CreateProject method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) CreateProject(contx context.Context, name, ownerID string) (*domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, name, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks ListProjects method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) ListProjects(contx context.Context, ownerID string) ([]domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetProject method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) GetProject(contx context.Context, projectID, ownerID string) (*domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, projectID, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks RenameProject method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) RenameProject(contx context.Context, projectID, ownerID, name string) (*domain.Project, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, projectID, ownerID, name)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Project), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks DeleteProject method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) DeleteProject(contx context.Context, projectID, ownerID string, cascade bool) (int64, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, projectID, ownerID, cascade)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// mocks ProjectStats method of ProjectUseCase interface
+func (mcpuc *MockProjectUseCase) ProjectStats(contx context.Context, projectID, ownerID string) (*domain.ProjectStats, error) {
+
+	// call the mocked method and return the result
+	args := mcpuc.Called(contx, projectID, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.ProjectStats), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
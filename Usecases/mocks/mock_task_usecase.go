@@ -2,6 +2,8 @@ package mock_usecases
 
 // imports
 import (
+	"context"
+	"time"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,10 +14,10 @@ type MockTaskUseCase struct {
 }
 
 // mocks CreateTask method of TaskUseCase interface
-func (mctuc *MockTaskUseCase) CreateTask(task *domain.Task) (*domain.Task, error) {
+func (mctuc *MockTaskUseCase) CreateTask(contx context.Context, task *domain.Task, ownerID string) (*domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctuc.Called(task)
+	args := mctuc.Called(contx, task, ownerID)
 	var result *domain.Task
 	if args.Get(0) != nil {
 		result = args.Get(0).(*domain.Task)
@@ -25,19 +27,28 @@ func (mctuc *MockTaskUseCase) CreateTask(task *domain.Task) (*domain.Task, error
 }
 
 // mocks DeleteTask method of TaskUseCase interface
-func (mctuc *MockTaskUseCase) DeleteTask(taskID string) error {
+func (mctuc *MockTaskUseCase) DeleteTask(contx context.Context, taskID string) error {
 	
 	// call the mocked method and return the result
-	args := mctuc.Called(taskID)
+	args := mctuc.Called(contx, taskID)
+
+	return args.Error(0)
+}
+
+// mocks RestoreTask method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) RestoreTask(contx context.Context, taskID string) error {
+
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID)
 
 	return args.Error(0)
 }
 
 // mocks GetAllTasks method of TaskUseCase interface
-func (mctuc *MockTaskUseCase) GetAllTasks() ([]domain.Task, error) {
+func (mctuc *MockTaskUseCase) GetAllTasks(contx context.Context) ([]domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctuc.Called()
+	args := mctuc.Called(contx)
 	var result []domain.Task
 	if args.Get(0) != nil {
 		result = args.Get(0).([]domain.Task)
@@ -46,11 +57,115 @@ func (mctuc *MockTaskUseCase) GetAllTasks() ([]domain.Task, error) {
 	return result, args.Error(1)
 }
 
+// mocks GetTasksSorted method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksSorted(contx context.Context, field string, ascending bool) ([]domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, field, ascending)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks GetRecentlyUpdated method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetRecentlyUpdated(contx context.Context, ownerID string, limit int64) ([]domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, ownerID, limit)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks GetUpcomingTasks method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetUpcomingTasks(contx context.Context, ownerID string, days int) ([]domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, ownerID, days)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks GetTasksByOwner method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksByOwner(contx context.Context, ownerID string, page, pageSize int) ([]domain.Task, int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, ownerID, page, pageSize)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Get(1).(int64), args.Error(2)
+}
+
+// mocks GetTasksByUser method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksByUser(contx context.Context, userID string) ([]domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, userID)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks TaskStats method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) TaskStats(contx context.Context) (map[string]int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx)
+	var result map[string]int64
+	if args.Get(0) != nil {
+		result = args.Get(0).(map[string]int64)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks GetStatsByOwner method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetStatsByOwner(contx context.Context, ownerIDs []string) (map[string]map[string]int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, ownerIDs)
+	var result map[string]map[string]int64
+	if args.Get(0) != nil {
+		result = args.Get(0).(map[string]map[string]int64)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks GetTasksGroupedByAssignee method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksGroupedByAssignee(contx context.Context, includeTasks bool) ([]domain.AssigneeTasks, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, includeTasks)
+	var result []domain.AssigneeTasks
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.AssigneeTasks)
+	}
+
+	return result, args.Error(1)
+}
+
 // mocks GetTaskByID method of TaskUseCase interface
-func (mctuc *MockTaskUseCase) GetTaskByID(taskID string) (*domain.Task, error) {
+func (mctuc *MockTaskUseCase) GetTaskByID(contx context.Context, taskID string) (*domain.Task, error) {
 	
 	// call the mocked method and return the result
-	args := mctuc.Called(taskID)
+	args := mctuc.Called(contx, taskID)
 	var result *domain.Task
 	if args.Get(0) != nil {
 		result = args.Get(0).(*domain.Task)
@@ -60,10 +175,31 @@ func (mctuc *MockTaskUseCase) GetTaskByID(taskID string) (*domain.Task, error) {
 }
 
 // mocks UpdateTask method of TaskUseCase interface
-func (mctuc *MockTaskUseCase) UpdateTask(taskID string, task *domain.Task) (*domain.Task, error) {
+func (mctuc *MockTaskUseCase) UpdateTask(contx context.Context, taskID string, task *domain.Task, actorRole string) (*domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID, task, actorRole)
+	var result *domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).(*domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks AssignTask method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) AssignTask(contx context.Context, taskID, userID, actorID, actorRole string) error {
 	
 	// call the mocked method and return the result
-	args := mctuc.Called(taskID, task)
+	args := mctuc.Called(contx, taskID, userID, actorID, actorRole)
+	return args.Error(0)
+}
+
+// mocks UpdateTaskIfMatch method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) UpdateTaskIfMatch(contx context.Context, taskID string, task *domain.Task, etag, actorRole string) (*domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID, task, etag, actorRole)
 	var result *domain.Task
 	if args.Get(0) != nil {
 		result = args.Get(0).(*domain.Task)
@@ -71,3 +207,108 @@ func (mctuc *MockTaskUseCase) UpdateTask(taskID string, task *domain.Task) (*dom
 
 	return result, args.Error(1)
 }
+
+// mocks SearchTasks method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) SearchTasks(contx context.Context, keyword string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, keyword)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetTasksByDueDateRange method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksByDueDateRange(contx context.Context, from, to time.Time) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, from, to)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetDependencies method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetDependencies(contx context.Context, taskID string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks GetOverdueTasks method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetOverdueTasks(contx context.Context, ownerID string) ([]domain.Task, error) {
+
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, ownerID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+// mocks ReassignTasks method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) ReassignTasks(contx context.Context, fromUserID, toUserID string) (int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, fromUserID, toUserID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// mocks GetTasksByProject method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) GetTasksByProject(contx context.Context, projectID, ownerID string) ([]domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, projectID, ownerID)
+	var result []domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).([]domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks MoveTask method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) MoveTask(contx context.Context, taskID, projectID, ownerID string) (*domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID, projectID, ownerID)
+	var result *domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).(*domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks ReopenTask method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) ReopenTask(contx context.Context, taskID, actorID, actorRole string) (*domain.Task, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskID, actorID, actorRole)
+	var result *domain.Task
+	if args.Get(0) != nil {
+		result = args.Get(0).(*domain.Task)
+	}
+
+	return result, args.Error(1)
+}
+
+// mocks CompleteTasks method of TaskUseCase interface
+func (mctuc *MockTaskUseCase) CompleteTasks(contx context.Context, taskIDs []string) (int64, error) {
+	
+	// call the mocked method and return the result
+	args := mctuc.Called(contx, taskIDs)
+
+	return args.Get(0).(int64), args.Error(1)
+}
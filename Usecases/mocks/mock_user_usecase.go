@@ -2,6 +2,8 @@ package mock_usecases
 
 // imports
 import (
+	"context"
+	"time"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,19 +14,19 @@ type MockUserUseCase struct {
 }
 
 // mocks Register method of UserUseCase interface
-func (mcuuc *MockUserUseCase) Register(user *domain.User) error {
+func (mcuuc *MockUserUseCase) Register(contx context.Context, user *domain.User) error {
 	
 	// call the mocked method and return the error if any
-	args := mcuuc.Called(user)
+	args := mcuuc.Called(contx, user)
 	
 	return args.Error(0)
 }
 
 // mocks Login method of UserUseCase interface
-func (mcuuc *MockUserUseCase) Login(credentials *domain.Credentials) (string, *domain.User, error) {
+func (mcuuc *MockUserUseCase) Login(contx context.Context, credentials *domain.Credentials) (string, *domain.User, error) {
 	
 	// call the mocked method and return the results
-	args := mcuuc.Called(credentials)
+	args := mcuuc.Called(contx, credentials)
 
 	var user *domain.User
 	if u := args.Get(1); u != nil {
@@ -34,11 +36,111 @@ func (mcuuc *MockUserUseCase) Login(credentials *domain.Credentials) (string, *d
 	return args.String(0), user, args.Error(2)
 }
 
+// mocks DemoteFromAdmin method of UserUseCase interface
+func (mcuuc *MockUserUseCase) DemoteFromAdmin(contx context.Context, userID string) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID)
+
+	return args.Error(0)
+}
+
+// mocks ListUsers method of UserUseCase interface
+func (mcuuc *MockUserUseCase) ListUsers(contx context.Context, page, pageSize int) ([]domain.User, error) {
+	
+	// call the mocked method and return the results
+	args := mcuuc.Called(contx, page, pageSize)
+
+	var users []domain.User
+	if u := args.Get(0); u != nil {
+		users = u.([]domain.User)
+	}
+
+	return users, args.Error(1)
+}
+
+// mocks DeleteUser method of UserUseCase interface
+func (mcuuc *MockUserUseCase) DeleteUser(contx context.Context, userID string) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID)
+
+	return args.Error(0)
+}
+
 // mocks PromoteToAdmin method of UserUseCase interface
-func (mcuuc *MockUserUseCase) PromoteToAdmin(userID string) error {
+func (mcuuc *MockUserUseCase) PromoteToAdmin(contx context.Context, userID string) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID)
+
+	return args.Error(0)
+}
+
+// mocks GetPreferences method of UserUseCase interface
+func (mcuuc *MockUserUseCase) GetPreferences(contx context.Context, userID string) (*domain.Preferences, error) {
+	
+	// call the mocked method and return the results
+	args := mcuuc.Called(contx, userID)
+
+	var prefs *domain.Preferences
+	if p := args.Get(0); p != nil {
+		prefs = p.(*domain.Preferences)
+	}
+
+	return prefs, args.Error(1)
+}
+
+// mocks UpdatePreferences method of UserUseCase interface
+func (mcuuc *MockUserUseCase) UpdatePreferences(contx context.Context, userID string, prefs *domain.Preferences) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID, prefs)
+
+	return args.Error(0)
+}
+
+// mocks ChangePassword method of UserUseCase interface
+func (mcuuc *MockUserUseCase) ChangePassword(contx context.Context, userID, oldPassword, newPassword string) error {
 	
 	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID, oldPassword, newPassword)
+
+	return args.Error(0)
+}
+
+// mocks IssueRefreshToken method of UserUseCase interface
+func (mcuuc *MockUserUseCase) IssueRefreshToken(userID string) (string, error) {
+	
+	// call the mocked method and return the results
 	args := mcuuc.Called(userID)
 
+	return args.String(0), args.Error(1)
+}
+
+// mocks Refresh method of UserUseCase interface
+func (mcuuc *MockUserUseCase) Refresh(contx context.Context, refreshToken string) (string, error) {
+	
+	// call the mocked method and return the results
+	args := mcuuc.Called(contx, refreshToken)
+
+	return args.String(0), args.Error(1)
+}
+
+// mocks CheckTokenIssuedAt method of UserUseCase interface
+func (mcuuc *MockUserUseCase) CheckTokenIssuedAt(contx context.Context, userID string, issuedAt time.Time) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(contx, userID, issuedAt)
+
+	return args.Error(0)
+}
+
+// mocks ValidatePassword method of UserUseCase interface
+func (mcuuc *MockUserUseCase) ValidatePassword(password string) error {
+	
+	// call the mocked method and return the error if any
+	args := mcuuc.Called(password)
+
 	return args.Error(0)
 }
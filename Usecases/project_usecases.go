@@ -0,0 +1,147 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// upper bound for a project name in characters
+const MaxProjectNameLength = 100
+
+type projectUseCase struct {
+	projectRepo  domain.ProjectRepository
+	taskRepo     domain.TaskRepository        // detaches or deletes the tasks of a deleted project
+}
+
+// new project usecase
+func NewProjectUseCase(projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository) domain.ProjectUseCase {
+	return &projectUseCase{projectRepo: projectRepo, taskRepo: taskRepo}
+}
+
+// trims a project name and checks its length
+func projectName(name string) (string, error) {
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("project name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > MaxProjectNameLength {
+		return "", errors.New("project name must be at most 100 characters")
+	}
+
+	return name, nil
+}
+
+// create a project owned by the user
+func (projectUsc *projectUseCase) CreateProject(ctx context.Context, name, ownerID string) (*domain.Project, error) {
+
+	// validate input
+	ownerObjID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	name, err = projectName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &domain.Project{Name: name, OwnerID: ownerObjID}
+	if err := projectUsc.projectRepo.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// list the projects of the user, an empty owner lists every project
+func (projectUsc *projectUseCase) ListProjects(ctx context.Context, ownerID string) ([]domain.Project, error) {
+
+	var ownerObjID primitive.ObjectID
+	if ownerID != "" {
+		var err error
+		if ownerObjID, err = primitive.ObjectIDFromHex(ownerID); err != nil {
+			return nil, domain.ErrInvalidUserID
+		}
+	}
+
+	return projectUsc.projectRepo.GetProjectsByOwner(ctx, ownerObjID)
+}
+
+// get a project of the user, projects of other users are reported as not found
+func (projectUsc *projectUseCase) GetProject(ctx context.Context, projectID, ownerID string) (*domain.Project, error) {
+
+	// validate input
+	id, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+
+	project, err := projectUsc.projectRepo.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != "" && project.OwnerID.Hex() != ownerID {
+		return nil, domain.ErrProjectNotFound        // do not reveal other users' projects
+	}
+
+	return project, nil
+}
+
+// rename a project of the user
+func (projectUsc *projectUseCase) RenameProject(ctx context.Context, projectID, ownerID, name string) (*domain.Project, error) {
+
+	name, err := projectName(name)
+	if err != nil {
+		return nil, err
+	}
+	project, err := projectUsc.GetProject(ctx, projectID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectUsc.projectRepo.RenameProject(ctx, project.ID, name)
+}
+
+// delete a project of the user, its tasks leave the project or are soft deleted with it when cascading
+func (projectUsc *projectUseCase) DeleteProject(ctx context.Context, projectID, ownerID string, cascade bool) (int64, error) {
+
+	project, err := projectUsc.GetProject(ctx, projectID, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	// tasks first, so a failure leaves the project in place to retry
+	var changed int64
+	if cascade {
+		if changed, err = projectUsc.taskRepo.DeleteTasksByProject(ctx, projectID); err != nil {
+			return 0, err
+		}
+	}
+	detached, err := projectUsc.taskRepo.DetachProject(ctx, projectID)        // also clears tasks deleted earlier
+	if err != nil {
+		return 0, err
+	}
+	if !cascade {
+		changed = detached
+	}
+	if err := projectUsc.projectRepo.DeleteProject(ctx, project.ID); err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// count the tasks of a project of the user per status and priority
+func (projectUsc *projectUseCase) ProjectStats(ctx context.Context, projectID, ownerID string) (*domain.ProjectStats, error) {
+
+	if _, err := projectUsc.GetProject(ctx, projectID, ownerID); err != nil {
+		return nil, err
+	}
+
+	return projectUsc.taskRepo.CountByProject(ctx, projectID)
+}
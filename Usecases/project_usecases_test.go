@@ -0,0 +1,155 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// test suite for ProjectUseCase
+type ProjectUseCaseTestSuite struct {
+	suite.Suite
+	projectRepo  *mock_repositories.MockProjectRepository      // mock project repository instance
+	taskRepo     *mock_repositories.MockTaskRepository         // mock task repository instance
+	usecase      domain.ProjectUseCase                         // project usecase instance being tested
+	owner        primitive.ObjectID                            // owner of the stored project
+	project      *domain.Project                               // stored project
+}
+
+// initializes the test environment before each test
+func (suite *ProjectUseCaseTestSuite) SetupTest() {
+	suite.projectRepo = new(mock_repositories.MockProjectRepository)      // create new mock project repository
+	suite.taskRepo = new(mock_repositories.MockTaskRepository)            // create new mock task repository
+	suite.usecase = NewProjectUseCase(suite.projectRepo, suite.taskRepo)
+	suite.owner = primitive.NewObjectID()
+	suite.project = &domain.Project{ID: primitive.NewObjectID(), Name: "Launch", OwnerID: suite.owner}
+	suite.projectRepo.On("GetProjectByID", mock.Anything, suite.project.ID).Return(suite.project, nil)
+}
+
+// tests a project is created for the user with a trimmed name
+func (suite *ProjectUseCaseTestSuite) TestCreateProject_Success() {
+
+	suite.projectRepo.On("CreateProject", mock.Anything, mock.MatchedBy(func(p *domain.Project) bool {
+		return p.Name == "Launch" && p.OwnerID == suite.owner
+	})).Return(nil)
+
+	project, err := suite.usecase.CreateProject(context.Background(), "  Launch ", suite.owner.Hex())
+	assert.NoError(suite.T(), err)                          // no error expected
+	assert.Equal(suite.T(), "Launch", project.Name)         // name trimmed
+}
+
+// tests invalid project names are rejected before storing
+func (suite *ProjectUseCaseTestSuite) TestCreateProject_InvalidName() {
+
+	_, err := suite.usecase.CreateProject(context.Background(), "   ", suite.owner.Hex())
+	assert.EqualError(suite.T(), err, "project name cannot be empty")                 // blank name
+
+	_, err = suite.usecase.CreateProject(context.Background(), strings.Repeat("ü", MaxProjectNameLength+1), suite.owner.Hex())
+	assert.EqualError(suite.T(), err, "project name must be at most 100 characters")  // name too long
+	suite.projectRepo.AssertNotCalled(suite.T(), "CreateProject", mock.Anything, mock.Anything)
+}
+
+// tests admins list every project and users only their own
+func (suite *ProjectUseCaseTestSuite) TestListProjects_Scope() {
+
+	suite.projectRepo.On("GetProjectsByOwner", mock.Anything, primitive.NilObjectID).Return([]domain.Project{*suite.project, {Name: "Other"}}, nil)
+	suite.projectRepo.On("GetProjectsByOwner", mock.Anything, suite.owner).Return([]domain.Project{*suite.project}, nil)
+
+	all, err := suite.usecase.ListProjects(context.Background(), "")
+	assert.NoError(suite.T(), err)              // no error expected
+	assert.Len(suite.T(), all, 2)               // every project
+
+	own, err := suite.usecase.ListProjects(context.Background(), suite.owner.Hex())
+	assert.NoError(suite.T(), err)              // no error expected
+	assert.Len(suite.T(), own, 1)               // own projects only
+}
+
+// tests projects of other users look like missing ones, admins see them
+func (suite *ProjectUseCaseTestSuite) TestGetProject_OtherOwner() {
+
+	_, err := suite.usecase.GetProject(context.Background(), suite.project.ID.Hex(), primitive.NewObjectID().Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)       // hidden from other users
+
+	project, err := suite.usecase.GetProject(context.Background(), suite.project.ID.Hex(), "")
+	assert.NoError(suite.T(), err)                                  // admins see every project
+	assert.Equal(suite.T(), suite.project, project)
+
+	_, err = suite.usecase.GetProject(context.Background(), "invalid", "")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidProjectID)      // invalid id
+}
+
+// tests renaming is limited to the owner's projects
+func (suite *ProjectUseCaseTestSuite) TestRenameProject() {
+
+	suite.projectRepo.On("RenameProject", mock.Anything, suite.project.ID, "Relaunch").Return(&domain.Project{Name: "Relaunch"}, nil)
+
+	project, err := suite.usecase.RenameProject(context.Background(), suite.project.ID.Hex(), suite.owner.Hex(), "Relaunch")
+	assert.NoError(suite.T(), err)                                  // no error expected
+	assert.Equal(suite.T(), "Relaunch", project.Name)               // renamed
+
+	_, err = suite.usecase.RenameProject(context.Background(), suite.project.ID.Hex(), primitive.NewObjectID().Hex(), "Taken over")
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)       // not the owner
+	suite.projectRepo.AssertNotCalled(suite.T(), "RenameProject", mock.Anything, suite.project.ID, "Taken over")
+}
+
+// tests deleting a project keeps its tasks without the project by default
+func (suite *ProjectUseCaseTestSuite) TestDeleteProject_Orphan() {
+
+	suite.taskRepo.On("DetachProject", mock.Anything, suite.project.ID.Hex()).Return(int64(3), nil)
+	suite.projectRepo.On("DeleteProject", mock.Anything, suite.project.ID).Return(nil)
+
+	changed, err := suite.usecase.DeleteProject(context.Background(), suite.project.ID.Hex(), suite.owner.Hex(), false)
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), int64(3), changed)      // tasks detached
+	suite.taskRepo.AssertNotCalled(suite.T(), "DeleteTasksByProject", mock.Anything, mock.Anything)
+}
+
+// tests cascading deletes soft delete the project's tasks first
+func (suite *ProjectUseCaseTestSuite) TestDeleteProject_Cascade() {
+
+	suite.taskRepo.On("DeleteTasksByProject", mock.Anything, suite.project.ID.Hex()).Return(int64(2), nil)
+	suite.taskRepo.On("DetachProject", mock.Anything, suite.project.ID.Hex()).Return(int64(1), nil)        // a task deleted earlier
+	suite.projectRepo.On("DeleteProject", mock.Anything, suite.project.ID).Return(nil)
+
+	changed, err := suite.usecase.DeleteProject(context.Background(), suite.project.ID.Hex(), "", true)
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), int64(2), changed)      // tasks deleted
+	suite.projectRepo.AssertExpectations(suite.T())
+}
+
+// tests a project of another user is not deleted
+func (suite *ProjectUseCaseTestSuite) TestDeleteProject_OtherOwner() {
+
+	_, err := suite.usecase.DeleteProject(context.Background(), suite.project.ID.Hex(), primitive.NewObjectID().Hex(), true)
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)       // not the owner
+	suite.taskRepo.AssertNotCalled(suite.T(), "DeleteTasksByProject", mock.Anything, mock.Anything)
+	suite.projectRepo.AssertNotCalled(suite.T(), "DeleteProject", mock.Anything, mock.Anything)
+}
+
+// tests the stats of an own project are counted and another user's project is hidden
+func (suite *ProjectUseCaseTestSuite) TestProjectStats() {
+
+	stats := &domain.ProjectStats{ByStatus: map[string]int64{"pending": 2}, ByPriority: map[string]int64{"high": 2}}
+	suite.taskRepo.On("CountByProject", mock.Anything, suite.project.ID.Hex()).Return(stats, nil)
+
+	result, err := suite.usecase.ProjectStats(context.Background(), suite.project.ID.Hex(), suite.owner.Hex())
+	assert.NoError(suite.T(), err)                          // no error expected
+	assert.Equal(suite.T(), stats, result)                  // counts of the project
+
+	_, err = suite.usecase.ProjectStats(context.Background(), suite.project.ID.Hex(), primitive.NewObjectID().Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)       // not the owner
+	suite.taskRepo.AssertNumberOfCalls(suite.T(), "CountByProject", 1)
+}
+
+// runs the test suite for ProjectUseCase
+func TestProjectUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectUseCaseTestSuite))
+}
@@ -0,0 +1,64 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"log"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+)
+
+// periodically deletes tasks that stayed in a status longer than its retention rule allows
+type RetentionJob struct {
+	taskRepo  domain.TaskRepository
+	rules     []domain.RetentionRule      // one status and age per rule
+	interval  time.Duration               // how often the job runs
+	now       func() time.Time            // clock used for the cutoffs
+}
+
+// creates a new retention job, now may be nil to use the real clock
+func NewRetentionJob(repo domain.TaskRepository, rules []domain.RetentionRule, interval time.Duration, now func() time.Time) *RetentionJob {
+	if now == nil {
+		now = time.Now        // default to the real clock
+	}
+	return &RetentionJob{taskRepo: repo, rules: rules, interval: interval, now: now}
+}
+
+// applies every rule once and returns how many tasks were deleted, stopping at the first failing rule
+func (job *RetentionJob) RunOnce() (int64, error) {
+
+	now := job.now()        // one cutoff base for every rule of a run
+	var deleted int64
+	for _, rule := range job.rules {
+		n, err := job.taskRepo.DeleteStatusBefore(context.Background(), rule.Status, now.Add(-rule.After))
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// runs the job on every interval until stop is closed
+func (job *RetentionJob) Start(stop <-chan struct{}) {
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := job.RunOnce()
+			if err != nil {
+				log.Printf("task retention failed: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("task retention: deleted %d tasks", deleted)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package usecases
+
+// imports
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// test suite for RetentionJob
+type RetentionJobTestSuite struct {
+	suite.Suite
+	mockRepo  *mock_repositories.MockTaskRepository      // mock task repository instance
+	now       time.Time                                  // fixed clock for the tests
+	rules     []domain.RetentionRule                     // archived after a year, completed after 90 days
+}
+
+// intialize the test suite before each test
+func (suite *RetentionJobTestSuite) SetupTest() {
+	suite.mockRepo = new(mock_repositories.MockTaskRepository)
+	suite.now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	suite.rules = []domain.RetentionRule{
+		{Status: "archived", After: 365 * 24 * time.Hour},
+		{Status: "completed", After: 90 * 24 * time.Hour},
+	}
+}
+
+// in-memory task store deleting tasks like the repository does
+type retentionRepo struct {
+	*mock_repositories.MockTaskRepository
+	tasks []domain.Task
+}
+
+// deletes stored tasks in the status last changed before the cutoff
+func (repo *retentionRepo) DeleteStatusBefore(ctx context.Context, status string, cutoff time.Time) (int64, error) {
+
+	var kept []domain.Task
+	for _, task := range repo.tasks {
+		if task.Status != status || !task.UpdatedAt.Before(cutoff) {
+			kept = append(kept, task)
+		}
+	}
+	deleted := int64(len(repo.tasks) - len(kept))
+	repo.tasks = kept
+
+	return deleted, nil
+}
+
+// tests every rule runs with its own cutoff and the counts add up
+func (suite *RetentionJobTestSuite) TestRunOnce_Cutoffs() {
+
+	suite.mockRepo.On("DeleteStatusBefore", mock.Anything, "archived", suite.now.Add(-365*24*time.Hour)).Return(int64(2), nil)
+	suite.mockRepo.On("DeleteStatusBefore", mock.Anything, "completed", suite.now.Add(-90*24*time.Hour)).Return(int64(1), nil)
+	job := NewRetentionJob(suite.mockRepo, suite.rules, time.Hour, func() time.Time { return suite.now })
+
+	deleted, err := job.RunOnce()
+	assert.NoError(suite.T(), err)                    // no error expected
+	assert.Equal(suite.T(), int64(3), deleted)        // counts of both rules
+	suite.mockRepo.AssertExpectations(suite.T())      // verify cutoffs
+}
+
+// tests the job deletes only tasks older than their status allows
+func (suite *RetentionJobTestSuite) TestRunOnce_DeletesMatchingTasks() {
+
+	repo := &retentionRepo{tasks: []domain.Task{
+		{Title: "old archived", Status: "archived", UpdatedAt: suite.now.AddDate(-2, 0, 0)},
+		{Title: "young archived", Status: "archived", UpdatedAt: suite.now.AddDate(0, -6, 0)},
+		{Title: "old completed", Status: "completed", UpdatedAt: suite.now.AddDate(0, -4, 0)},
+		{Title: "young completed", Status: "completed", UpdatedAt: suite.now.AddDate(0, 0, -10)},
+		{Title: "old pending", Status: "pending", UpdatedAt: suite.now.AddDate(-3, 0, 0)},
+	}}
+	job := NewRetentionJob(repo, suite.rules, time.Hour, func() time.Time { return suite.now })
+
+	deleted, err := job.RunOnce()
+	assert.NoError(suite.T(), err)                   // no error expected
+	assert.Equal(suite.T(), int64(2), deleted)       // the old archived and completed tasks
+
+	var titles []string
+	for _, task := range repo.tasks {
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(suite.T(), []string{"young archived", "young completed", "old pending"}, titles)      // younger tasks and unruled statuses are spared
+}
+
+// tests a failing rule stops the run and reports what was deleted so far
+func (suite *RetentionJobTestSuite) TestRunOnce_Error() {
+
+	suite.mockRepo.On("DeleteStatusBefore", mock.Anything, "archived", mock.Anything).Return(int64(2), nil)
+	suite.mockRepo.On("DeleteStatusBefore", mock.Anything, "completed", mock.Anything).Return(int64(0), errors.New("delete error"))
+	job := NewRetentionJob(suite.mockRepo, suite.rules, time.Hour, func() time.Time { return suite.now })
+
+	deleted, err := job.RunOnce()
+	assert.EqualError(suite.T(), err, "delete error")      // error should be passed through
+	assert.Equal(suite.T(), int64(2), deleted)             // first rule already applied
+}
+
+// runs the test suite for RetentionJob
+func TestRetentionJobTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionJobTestSuite))
+}
@@ -2,23 +2,418 @@ package usecases
 
 // imports
 import (
+	"context"
 	"errors"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// fields clients may change through UpdateTask by default
+var DefaultUpdatableTaskFields = []string{"title", "description", "due_date", "due_date_tz", "status", "priority", "depends_on", "project_id"}
+
+// priority given to new tasks when neither the task nor the creator's preferences set one
+const DefaultTaskPriority = "medium"
+
+// allowed task priorities
+var validTaskPriorities = map[string]bool{
+	"low":     true,
+	"medium":  true,
+	"high":    true,
+}
+
+// upper bound for the recently updated tasks listing
+const MaxRecentTasks = 100
+
+// upper bound for the upcoming tasks window in days
+const MaxUpcomingDays = 90
+
+// upper bound for the owners of one stats request
+const MaxStatsOwners = 100
+
+// upper bound for the tasks of one bulk complete request
+const MaxBulkTasks = 100
+
+// upper bound for a search keyword in characters
+const MaxSearchQueryLength = 100
+
+// description size limit in bytes when none is configured
+const DefaultMaxDescriptionBytes = 10 * 1024
+
+// direct dependencies of one task when no limit is configured
+const DefaultMaxDependencies = 20
+
+// longest chain of dependencies below a task when no limit is configured
+const DefaultMaxDependencyDepth = 10
+
+// copies a single mutable field from the update request into the sanitized update
+var taskFieldCopiers = map[string]func(dst, src *domain.Task){
+	"title":        func(dst, src *domain.Task) { dst.Title = src.Title },
+	"description":  func(dst, src *domain.Task) { dst.Description = src.Description },
+	"due_date":     func(dst, src *domain.Task) { dst.DueDate = src.DueDate },
+	"due_date_tz":  func(dst, src *domain.Task) { dst.DueDateTZ = src.DueDateTZ },
+	"status":       func(dst, src *domain.Task) { dst.Status = src.Status },
+	"priority":     func(dst, src *domain.Task) { dst.Priority = src.Priority },
+	"depends_on":   func(dst, src *domain.Task) { dst.DependsOn = src.DependsOn },
+	"project_id":   func(dst, src *domain.Task) { dst.ProjectID = src.ProjectID },
+}
+
+// statuses that count as finished for tasks depending on them
+var finishedStatuses = map[string]bool{
+	"completed":  true,
+	"archived":   true,
+}
+
+// statuses a task cannot move to while one of its dependencies is unfinished
+var startedStatuses = map[string]bool{
+	"in_progress":  true,
+	"completed":    true,
+}
+
+// statuses a task may move to from its current status, completed is final unless reopening is enabled
+var statusTransitions = map[string]map[string]bool{
+	"pending":      {"in_progress": true, "completed": true},
+	"in_progress":  {"completed": true, "pending": true},
+	"completed":    {},
+}
+
+// configurable task rules
+type TaskConfig struct {
+	UpdatableFields  []string        // json names of fields UpdateTask may change
+	StrictDueDate    bool            // strict: every due date in an update must be in the future
+	                                 // lenient: an unchanged, already past due date may be sent back
+	Now              func() time.Time // clock used for date windows (defaults to time.Now)
+	MaxDescriptionBytes  int          // largest description in bytes, not runes (0 = DefaultMaxDescriptionBytes)
+	WorkdayDueDates  bool            // reject due dates on a saturday or sunday in the owner's timezone
+	CreateRateLimit  int             // tasks a non-admin may create per window of CreateAttempts (0 = unlimited)
+	CreateAttempts   domain.AttemptStore    // recent creations per user, required when CreateRateLimit is set
+	SelfAssign       bool            // non-admins may assign tasks to themselves, only admins assign to others
+	LockInProgressAssignee  bool     // only admins may take an in_progress task away from its assignee
+	ReopenCompleted  bool            // completed tasks may move back to pending or in_progress
+	LockCompleted    bool            // only admins may change completed tasks, PUT /tasks/:id is admin only so over HTTP this guards reopening
+	MaxDependencies  int             // direct dependencies of one task (0 = DefaultMaxDependencies)
+	MaxDependencyDepth  int          // longest dependency chain below a task, 1 = direct only (0 = DefaultMaxDependencyDepth)
+	Projects         domain.ProjectRepository    // looks up task projects, required to put tasks in a project
+}
+
+// returns the default task rules
+func DefaultTaskConfig() TaskConfig {
+	return TaskConfig{UpdatableFields: DefaultUpdatableTaskFields}
+}
+
 type taskUseCase struct {
-	taskRepo domain.TaskRepository
+	taskRepo   domain.TaskRepository
+	userRepo   domain.UserRepository      // optional - used to read the creator's preferences
+	config     TaskConfig
 }
 
 // creates new TaskUseCase instance
 func NewTaskUseCase(repo domain.TaskRepository) domain.TaskUseCase {
-	return &taskUseCase{taskRepo: repo}
+	return NewTaskUseCaseWithConfig(repo, nil, DefaultTaskConfig())
+}
+
+// creates new TaskUseCase instance with custom task rules, userRepo may be nil to skip user preferences
+func NewTaskUseCaseWithConfig(repo domain.TaskRepository, userRepo domain.UserRepository, config TaskConfig) domain.TaskUseCase {
+	if config.Now == nil {
+		config.Now = time.Now        // default to the real clock
+	}
+	if config.MaxDescriptionBytes <= 0 {
+		config.MaxDescriptionBytes = DefaultMaxDescriptionBytes
+	}
+	if config.MaxDependencies <= 0 {
+		config.MaxDependencies = DefaultMaxDependencies
+	}
+	if config.MaxDependencyDepth <= 0 {
+		config.MaxDependencyDepth = DefaultMaxDependencyDepth
+	}
+	return &taskUseCase{taskRepo: repo, userRepo: userRepo, config: config}
+}
+
+// loads the preferences of a user, unknown users and a missing user repository give empty preferences
+func (taskUsc *taskUseCase) userPreferences(ctx context.Context, userID primitive.ObjectID) (domain.Preferences, error) {
+
+	if taskUsc.userRepo == nil {
+		return domain.Preferences{}, nil
+	}
+
+	user, err := taskUsc.userRepo.GetUserById(ctx, userID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.Preferences{}, nil
+		}
+		return domain.Preferences{}, err
+	}
+
+	return user.Preferences, nil
+}
+
+// picks the priority for a new task without one - creator's preference first, then the global default
+func (taskUsc *taskUseCase) defaultPriority(ctx context.Context, ownerID primitive.ObjectID) (string, error) {
+
+	prefs, err := taskUsc.userPreferences(ctx, ownerID)
+	if err != nil {
+		return "", err
+	}
+	if prefs.DefaultPriority == "" {
+		return DefaultTaskPriority, nil
+	}
+
+	return prefs.DefaultPriority, nil
+}
+
+// returns the owner's preferred timezone, or nil when there is no owner or no preference
+func (taskUsc *taskUseCase) ownerLocation(ctx context.Context, ownerID string) (*time.Location, error) {
+
+	if ownerID == "" {
+		return nil, nil
+	}
+	objID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	prefs, err := taskUsc.userPreferences(ctx, objID)
+	if err != nil || prefs.Timezone == "" {
+		return nil, err
+	}
+
+	return time.LoadLocation(prefs.Timezone)
+}
+
+// rejects weekend due dates when workdays are enforced, judged in the owner's timezone or UTC without one
+func (taskUsc *taskUseCase) checkWorkday(ctx context.Context, dueDate time.Time, ownerID string) error {
+
+	if !taskUsc.config.WorkdayDueDates || dueDate.IsZero() {
+		return nil
+	}
+	loc, err := taskUsc.ownerLocation(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch dueDate.In(loc).Weekday() {
+	case time.Saturday, time.Sunday:
+		return domain.ErrDueDateNotWorkday
+	}
+
+	return nil
+}
+
+// rejects a due date timezone missing from the tz database, the due date itself stays in UTC
+func checkDueDateTZ(tz string) error {
+
+	if tz == "" {
+		return nil
+	}
+	// "Local" would name the server's zone, not the client's
+	if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+		return domain.ErrInvalidTimezone
+	}
+
+	return nil
+}
+
+// key of a user's creations in the attempt store
+func createAttemptKey(ownerID primitive.ObjectID) string {
+	return ownerID.Hex() + "create"
+}
+
+// rejects a creation once a non-admin reached the creation limit, admins and unknown users are not limited
+func (taskUsc *taskUseCase) checkCreateRate(ctx context.Context, ownerID primitive.ObjectID) error {
+
+	if taskUsc.config.CreateRateLimit <= 0 || taskUsc.config.CreateAttempts == nil {
+		return nil
+	}
+	if taskUsc.config.CreateAttempts.Count(createAttemptKey(ownerID)) < taskUsc.config.CreateRateLimit {
+		return nil
+	}
+
+	// only look the user up once they are at the limit
+	if taskUsc.userRepo != nil {
+		user, err := taskUsc.userRepo.GetUserById(ctx, ownerID)
+		if err != nil && err != domain.ErrUserNotFound {
+			return err
+		}
+		if user != nil && user.Role == "admin" {
+			return nil
+		}
+	}
+
+	return domain.ErrCreateRateExceeded
+}
+
+// drops repeated ids, keeping the first occurrence
+func uniqueIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// checks the dependencies are within the configured limits, all exist and none of them leads back
+// to taskID through its own dependencies, taskID is zero for a task not stored yet, which nothing can depend on
+func (taskUsc *taskUseCase) checkDependencies(ctx context.Context, taskID primitive.ObjectID, dependsOn []primitive.ObjectID) error {
+
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	if len(dependsOn) > taskUsc.config.MaxDependencies {
+		return domain.ErrTooManyDependencies
+	}
+	deps, err := taskUsc.taskRepo.GetTasksByIDs(ctx, dependsOn)
+	if err != nil {
+		return err
+	}
+	if len(deps) != len(dependsOn) {
+		return domain.ErrDependencyNotFound
+	}
+
+	// walk the dependency graph one level per query until it ends, reaches taskID or gets too deep,
+	// so a check costs at most MaxDependencyDepth queries
+	for depth := 1; ; depth++ {
+		var next []primitive.ObjectID
+		for _, dep := range deps {
+			if dep.ID == taskID {
+				return domain.ErrDependencyCycle
+			}
+			next = append(next, dep.DependsOn...)
+		}
+		if len(next) == 0 {
+			break
+		}
+		if depth == taskUsc.config.MaxDependencyDepth {
+			return domain.ErrDependencyTooDeep
+		}
+		if deps, err = taskUsc.taskRepo.GetTasksByIDs(ctx, uniqueIDs(next)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rejects starting or completing a task while any of its dependencies is unfinished,
+// deleted dependencies no longer block
+func (taskUsc *taskUseCase) checkUnblocked(ctx context.Context, status string, dependsOn []primitive.ObjectID) error {
+
+	if !startedStatuses[status] || len(dependsOn) == 0 {
+		return nil
+	}
+	deps, err := taskUsc.taskRepo.GetTasksByIDs(ctx, dependsOn)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if !finishedStatuses[dep.Status] {
+			return domain.ErrTaskBlocked
+		}
+	}
+
+	return nil
+}
+
+// rejects a project that does not exist or belongs to another owner than the task, a zero project means none
+func (taskUsc *taskUseCase) checkProject(ctx context.Context, projectID, ownerID primitive.ObjectID) error {
+
+	if projectID.IsZero() {
+		return nil
+	}
+	if taskUsc.config.Projects == nil {
+		return errors.New("task projects require a project repository")
+	}
+	project, err := taskUsc.config.Projects.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project.OwnerID != ownerID {
+		return domain.ErrProjectOwnerMismatch
+	}
+
+	return nil
+}
+
+// returns current when it is already loaded, otherwise loads the task with the given id
+func (taskUsc *taskUseCase) currentTask(ctx context.Context, id string, current *domain.Task) (*domain.Task, error) {
+
+	if current != nil {
+		return current, nil
+	}
+	current, err := taskUsc.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	return current, nil
+}
+
+// rejects moving a task from one status to another unless the transition is allowed,
+// keeping the current status is always allowed
+func (taskUsc *taskUseCase) checkTransition(from, to string) error {
+
+	if from == to || statusTransitions[from][to] {
+		return nil
+	}
+	if from == "completed" && taskUsc.config.ReopenCompleted && (to == "pending" || to == "in_progress") {
+		return nil
+	}
+
+	return domain.ErrInvalidStatusTransition
+}
+
+// statuses a task may be completed from, sorted so the repository filter is stable
+func completableStatuses() []string {
+
+	var statuses []string
+	for from, to := range statusTransitions {
+		if to["completed"] {
+			statuses = append(statuses, from)
+		}
+	}
+	sort.Strings(statuses)
+
+	return statuses
+}
+
+// keeps only the allowlisted fields of an update request, everything else is ignored
+func (taskUsc *taskUseCase) allowedUpdate(task *domain.Task) *domain.Task {
+
+	allowed := &domain.Task{}
+	for _, field := range taskUsc.config.UpdatableFields {
+		copyField, ok := taskFieldCopiers[field]
+		if ok {
+			copyField(allowed, task)
+		}
+	}
+
+	return allowed
 }
 
 // create a task
-func (taskUsc *taskUseCase) CreateTask(task *domain.Task) (*domain.Task, error) {
+func (taskUsc *taskUseCase) CreateTask(ctx context.Context, task *domain.Task, ownerID string) (*domain.Task, error) {
 	
+	// every task belongs to the user creating it
+	ownerObjID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+	task.OwnerID = ownerObjID
+
 	// validate task fields before creation
 	if task.Title == "" {
 		return nil, errors.New("task title cannot be empty")
@@ -26,6 +421,9 @@ func (taskUsc *taskUseCase) CreateTask(task *domain.Task) (*domain.Task, error)
 	if task.Description == "" {
 		return nil, errors.New("task description cannot be empty")
 	}
+	if len(task.Description) > taskUsc.config.MaxDescriptionBytes {
+		return nil, domain.ErrDescriptionTooLong        // len counts bytes, as stored in the document
+	}
 	if task.DueDate.IsZero() {
 		return nil, errors.New("due date cannot be empty")
 	}
@@ -36,6 +434,12 @@ func (taskUsc *taskUseCase) CreateTask(task *domain.Task) (*domain.Task, error)
 	if time.Until(task.DueDate) < 0 {
 		return nil, errors.New("due date must be in the future")
 	}
+	if err := taskUsc.checkWorkday(ctx, task.DueDate, ownerID); err != nil {
+		return nil, err
+	}
+	if err := checkDueDateTZ(task.DueDateTZ); err != nil {
+		return nil, err
+	}
 	// validate status is one of allowed values
 	validStatuses := map[string]bool{
 		"pending":      true,
@@ -45,19 +449,55 @@ func (taskUsc *taskUseCase) CreateTask(task *domain.Task) (*domain.Task, error)
 	if !validStatuses[task.Status] {
 		return nil, errors.New("invalid task status")
 	}
+	if task.Priority == "" {
+		priority, err := taskUsc.defaultPriority(ctx, ownerObjID)      // default priority
+		if err != nil {
+			return nil, err
+		}
+		task.Priority = priority
+	}
+	// validate priority is one of allowed values
+	if !validTaskPriorities[task.Priority] {
+		return nil, domain.ErrInvalidPriority
+	}
+	// a new task can only be created already started when its dependencies are finished
+	if task.DependsOn != nil {
+		task.DependsOn = uniqueIDs(task.DependsOn)
+	}
+	if err := taskUsc.checkDependencies(ctx, primitive.NilObjectID, task.DependsOn); err != nil {
+		return nil, err
+	}
+	if err := taskUsc.checkUnblocked(ctx, task.Status, task.DependsOn); err != nil {
+		return nil, err
+	}
+	if err := taskUsc.checkProject(ctx, task.ProjectID, ownerObjID); err != nil {
+		return nil, err
+	}
+	if err := taskUsc.checkCreateRate(ctx, ownerObjID); err != nil {
+		return nil, err
+	}
+
+	created, err := taskUsc.taskRepo.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	// only stored tasks count towards the creation limit
+	if taskUsc.config.CreateRateLimit > 0 && taskUsc.config.CreateAttempts != nil {
+		taskUsc.config.CreateAttempts.Record(createAttemptKey(ownerObjID))
+	}
 
-	return taskUsc.taskRepo.CreateTask(task)
+	return created, nil
 }
 
 // remove task by its id
-func (taskUsc *taskUseCase) DeleteTask(id string) error {
+func (taskUsc *taskUseCase) DeleteTask(ctx context.Context, id string) error {
 	
 	// validate id field 
 	if id == "" {
 		return errors.New("task ID cannot be empty")
 	}
 	// verify task exists first
-	_, err := taskUsc.taskRepo.GetTaskByID(id)
+	_, err := taskUsc.taskRepo.GetTaskByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrTaskNotFound {
 			return domain.ErrTaskNotFound
@@ -65,13 +505,24 @@ func (taskUsc *taskUseCase) DeleteTask(id string) error {
 		return err
 	}
 
-	return taskUsc.taskRepo.DeleteTask(id)
+	return taskUsc.taskRepo.DeleteTask(ctx, id)
+}
+
+// bring back a soft deleted task
+func (taskUsc *taskUseCase) RestoreTask(ctx context.Context, id string) error {
+
+	// validate id field
+	if id == "" {
+		return errors.New("task ID cannot be empty")
+	}
+
+	return taskUsc.taskRepo.RestoreTask(ctx, id)
 }
 
 // get all tasks 
-func (taskUsc *taskUseCase) GetAllTasks() ([]domain.Task, error) {
+func (taskUsc *taskUseCase) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
 	
-	tasks, err := taskUsc.taskRepo.GetAllTasks()
+	tasks, err := taskUsc.taskRepo.GetAllTasks(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -83,15 +534,334 @@ func (taskUsc *taskUseCase) GetAllTasks() ([]domain.Task, error) {
 	return tasks, nil
 }
 
+// get tasks whose title or description contains the keyword
+func (taskUsc *taskUseCase) SearchTasks(ctx context.Context, keyword string) ([]domain.Task, error) {
+
+	// surrounding spaces are never meant to be part of the keyword
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" || utf8.RuneCountInString(keyword) > MaxSearchQueryLength {
+		return nil, domain.ErrInvalidSearchQuery
+	}
+
+	tasks, err := taskUsc.taskRepo.SearchTasks(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get tasks due within the window, either bound may be zero for an open ended window
+func (taskUsc *taskUseCase) GetTasksByDueDateRange(ctx context.Context, from, to time.Time) ([]domain.Task, error) {
+
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, domain.ErrInvalidDueDateRange
+	}
+
+	tasks, err := taskUsc.taskRepo.GetTasksByDueDateRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get all tasks sorted by an allowed field
+func (taskUsc *taskUseCase) GetTasksSorted(ctx context.Context, field string, ascending bool) ([]domain.Task, error) {
+
+	tasks, err := taskUsc.taskRepo.GetTasksSorted(ctx, field, ascending)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get most recently updated tasks of an owner, or of everyone when ownerID is empty
+func (taskUsc *taskUseCase) GetRecentlyUpdated(ctx context.Context, ownerID string, limit int64) ([]domain.Task, error) {
+
+	// validate limit
+	if limit <= 0 {
+		return nil, domain.ErrInvalidLimit
+	}
+	if limit > MaxRecentTasks {
+		limit = MaxRecentTasks
+	}
+
+	tasks, err := taskUsc.taskRepo.GetRecentlyUpdated(ctx, ownerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get not completed tasks due within the next days of an owner, or of everyone when ownerID is empty
+func (taskUsc *taskUseCase) GetUpcomingTasks(ctx context.Context, ownerID string, days int) ([]domain.Task, error) {
+
+	// validate window size
+	if days <= 0 || days > MaxUpcomingDays {
+		return nil, domain.ErrInvalidDays
+	}
+
+	loc, err := taskUsc.ownerLocation(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := taskUsc.config.Now()
+	to := from.AddDate(0, 0, days)
+	// with a timezone preference the window covers the whole last day in the owner's calendar
+	if loc != nil {
+		last := to.In(loc)
+		to = time.Date(last.Year(), last.Month(), last.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), loc)
+	}
+
+	tasks, err := taskUsc.taskRepo.GetUpcomingTasks(ctx, ownerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get tasks past their due date that are not completed, optionally of one owner
+func (taskUsc *taskUseCase) GetOverdueTasks(ctx context.Context, ownerID string) ([]domain.Task, error) {
+
+	// read the clock per request, a long running server must not compare against its start time
+	tasks, err := taskUsc.taskRepo.GetOverdueTasks(ctx, ownerID, taskUsc.config.Now())
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// get one page of the tasks created by a user and how many they created in total
+func (taskUsc *taskUseCase) GetTasksByOwner(ctx context.Context, ownerID string, page, pageSize int) ([]domain.Task, int64, error) {
+
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(ownerID); err != nil {
+		return nil, 0, domain.ErrInvalidUserID
+	}
+	if page < 1 {
+		return nil, 0, domain.ErrInvalidPage
+	}
+	if pageSize < 1 || pageSize > MaxPageSize {
+		return nil, 0, domain.ErrInvalidPageSize
+	}
+
+	tasks, total, err := taskUsc.taskRepo.GetTasksByOwner(ctx, ownerID, int64(page), int64(pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, total, nil
+	}
+
+	return tasks, total, nil
+}
+
+// get tasks assigned to a user
+func (taskUsc *taskUseCase) GetTasksByUser(ctx context.Context, userID string) ([]domain.Task, error) {
+
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	tasks, err := taskUsc.taskRepo.GetTasksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// count tasks per status for the dashboard
+func (taskUsc *taskUseCase) TaskStats(ctx context.Context) (map[string]int64, error) {
+	return taskUsc.taskRepo.CountByStatus(ctx)
+}
+
+// count tasks per owner and status, owners without tasks get an empty count
+func (taskUsc *taskUseCase) GetStatsByOwner(ctx context.Context, ownerIDs []string) (map[string]map[string]int64, error) {
+
+	// validate input
+	if len(ownerIDs) > MaxStatsOwners {
+		return nil, domain.ErrTooManyOwners
+	}
+	for _, ownerID := range ownerIDs {
+		if _, err := primitive.ObjectIDFromHex(ownerID); err != nil {
+			return nil, domain.ErrInvalidUserID
+		}
+	}
+
+	counts, err := taskUsc.taskRepo.CountByOwnerStatus(ctx, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+	// every requested owner appears in the result
+	stats := make(map[string]map[string]int64, len(ownerIDs))
+	for _, ownerID := range ownerIDs {
+		stats[ownerID] = counts[ownerID]
+		if stats[ownerID] == nil {
+			stats[ownerID] = map[string]int64{}
+		}
+	}
+
+	return stats, nil
+}
+
+// count assigned tasks per assignee, busiest first, optionally with the tasks themselves
+func (taskUsc *taskUseCase) GetTasksGroupedByAssignee(ctx context.Context, includeTasks bool) ([]domain.AssigneeTasks, error) {
+
+	groups, err := taskUsc.taskRepo.GroupByAssignee(ctx, includeTasks)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if groups == nil {
+		return []domain.AssigneeTasks{}, nil
+	}
+
+	return groups, nil
+}
+
+// get the tasks of a project - an empty owner is an admin, who may list any project
+func (taskUsc *taskUseCase) GetTasksByProject(ctx context.Context, projectID, ownerID string) ([]domain.Task, error) {
+
+	// validate input
+	projectObjID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, domain.ErrInvalidProjectID
+	}
+	if ownerID != "" {
+		ownerObjID, err := primitive.ObjectIDFromHex(ownerID)
+		if err != nil {
+			return nil, domain.ErrInvalidUserID
+		}
+		// another user's project looks like a missing one, as in GetProject
+		if err := taskUsc.checkProject(ctx, projectObjID, ownerObjID); err != nil {
+			if err == domain.ErrProjectOwnerMismatch {
+				return nil, domain.ErrProjectNotFound
+			}
+			return nil, err
+		}
+	}
+
+	tasks, err := taskUsc.taskRepo.GetTasksByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if tasks == nil {
+		return []domain.Task{}, nil
+	}
+
+	return tasks, nil
+}
+
+// move a task into a project of the user or, with an empty project, out of its project -
+// an empty owner is an admin acting on any task, whose project must still belong to the task's owner
+func (taskUsc *taskUseCase) MoveTask(ctx context.Context, taskID, projectID, ownerID string) (*domain.Task, error) {
+
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(taskID); err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+	var projectObjID primitive.ObjectID
+	if projectID != "" {
+		var err error
+		if projectObjID, err = primitive.ObjectIDFromHex(projectID); err != nil {
+			return nil, domain.ErrInvalidProjectID
+		}
+	}
+
+	// tasks of other users are reported as not found
+	task, err := taskUsc.currentTask(ctx, taskID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != "" && task.OwnerID.Hex() != ownerID {
+		return nil, domain.ErrTaskNotFound
+	}
+	if err := taskUsc.checkProject(ctx, projectObjID, task.OwnerID); err != nil {
+		return nil, err
+	}
+
+	return taskUsc.taskRepo.SetTaskProject(ctx, taskID, projectObjID)
+}
+
+// move a completed task back to in_progress - its owner may reopen it unless completed tasks are locked to admins
+func (taskUsc *taskUseCase) ReopenTask(ctx context.Context, taskID, actorID, actorRole string) (*domain.Task, error) {
+
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(taskID); err != nil {
+		return nil, domain.ErrInvalidTaskID
+	}
+
+	// tasks of other users are reported as not found
+	task, err := taskUsc.currentTask(ctx, taskID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if actorRole != "admin" {
+		if task.OwnerID.Hex() != actorID {
+			return nil, domain.ErrTaskNotFound
+		}
+		if taskUsc.config.LockCompleted {
+			return nil, domain.ErrTaskCompletedLocked
+		}
+	}
+	if task.Status != "completed" {
+		return nil, domain.ErrTaskNotCompleted
+	}
+	// a dependency reopened in the meantime keeps the task from starting again
+	if err := taskUsc.checkUnblocked(ctx, "in_progress", task.DependsOn); err != nil {
+		return nil, err
+	}
+
+	return taskUsc.taskRepo.ReopenTask(ctx, taskID)
+}
+
 // find task by its id
-func (taskUsc *taskUseCase) GetTaskByID(id string) (*domain.Task, error) {
+func (taskUsc *taskUseCase) GetTaskByID(ctx context.Context, id string) (*domain.Task, error) {
 	
 	// validate id field 
 	if id == "" {
 		return nil, errors.New("task ID cannot be empty")
 	}
 
-	task, err := taskUsc.taskRepo.GetTaskByID(id)
+	task, err := taskUsc.taskRepo.GetTaskByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -102,16 +872,52 @@ func (taskUsc *taskUseCase) GetTaskByID(id string) (*domain.Task, error) {
 	return task, nil
 }
 
-// update task by its id
-func (taskUsc *taskUseCase) UpdateTask(id string, task *domain.Task) (*domain.Task, error) {
+// get the tasks a task depends on
+func (taskUsc *taskUseCase) GetDependencies(ctx context.Context, id string) ([]domain.Task, error) {
+
+	// validate id field
+	if id == "" {
+		return nil, errors.New("task ID cannot be empty")
+	}
+	task, err := taskUsc.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	deps, err := taskUsc.taskRepo.GetTasksByIDs(ctx, task.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+	// return empty slice
+	if deps == nil {
+		return []domain.Task{}, nil
+	}
+
+	return deps, nil
+}
+
+// update task by its id as the acting role
+func (taskUsc *taskUseCase) UpdateTask(ctx context.Context, id string, task *domain.Task, actorRole string) (*domain.Task, error) {
+	return taskUsc.updateTask(ctx, id, task, actorRole, nil)
+}
+
+// updates the task, current is the stored task when the caller already loaded it (nil = not loaded) -
+// every check that needs the stored task shares one lookup
+func (taskUsc *taskUseCase) updateTask(ctx context.Context, id string, task *domain.Task, actorRole string, current *domain.Task) (*domain.Task, error) {
 	
 	// validate id field 
 	if id == "" {
 		return nil, errors.New("task ID cannot be empty")
 	}
+	// drop fields clients are not allowed to change
+	task = taskUsc.allowedUpdate(task)
+	
 	// stop if nothing valid to update
-	if task.Title == "" && task.Description == "" && 
-	   task.DueDate.IsZero() && task.Status == "" {
+	if task.Title == "" && task.Description == "" && task.DependsOn == nil && task.ProjectID.IsZero() &&
+	   task.DueDate.IsZero() && task.DueDateTZ == "" && task.Status == "" && task.Priority == "" {
 		return nil, errors.New("no valid fields provided for update")
 	}
 	// validate status if provided
@@ -125,10 +931,261 @@ func (taskUsc *taskUseCase) UpdateTask(id string, task *domain.Task) (*domain.Ta
 			return nil, errors.New("invalid task status")
 		}
 	}
+	// validate priority if provided
+	if task.Priority != "" && !validTaskPriorities[task.Priority] {
+		return nil, domain.ErrInvalidPriority
+	}
+	// validate description size if provided
+	if len(task.Description) > taskUsc.config.MaxDescriptionBytes {
+		return nil, domain.ErrDescriptionTooLong
+	}
+	// validate due date timezone if provided
+	if err := checkDueDateTZ(task.DueDateTZ); err != nil {
+		return nil, err
+	}
 	// validate due date if provided
 	if !task.DueDate.IsZero() && time.Until(task.DueDate) < 0 {
-		return nil, errors.New("due date must be in the future")
+		if taskUsc.config.StrictDueDate {
+			return nil, errors.New("due date must be in the future")
+		}
+		// lenient mode - allow echoing back the current due date, e.g. when completing an old task
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		if !current.DueDate.Equal(task.DueDate) {
+			return nil, errors.New("due date must be in the future")
+		}
+	}
+	// validate due date falls on a workday if enforced, in the timezone of the task's owner
+	if taskUsc.config.WorkdayDueDates && !task.DueDate.IsZero() {
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		ownerID := ""
+		if !current.OwnerID.IsZero() {
+			ownerID = current.OwnerID.Hex()
+		}
+		if err := taskUsc.checkWorkday(ctx, task.DueDate, ownerID); err != nil {
+			return nil, err
+		}
+	}
+	// completed work stays as it is unless an admin changes it - PUT /tasks/:id is admin only,
+	// so only internal callers acting as a non-admin reach this
+	if taskUsc.config.LockCompleted && actorRole != "admin" {
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		if current.Status == "completed" {
+			return nil, domain.ErrTaskCompletedLocked
+		}
+	}
+	// validate the task may move from its current status to the requested one
+	if task.Status != "" {
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		if err := taskUsc.checkTransition(current.Status, task.Status); err != nil {
+			return nil, err
+		}
+	}
+	// validate the new project belongs to the task's owner
+	if !task.ProjectID.IsZero() {
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		if err := taskUsc.checkProject(ctx, task.ProjectID, current.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+	// validate new dependencies and that the task may move to its new status
+	if task.DependsOn != nil || startedStatuses[task.Status] {
+		var err error
+		if current, err = taskUsc.currentTask(ctx, id, current); err != nil {
+			return nil, err
+		}
+		dependsOn := current.DependsOn
+		if task.DependsOn != nil {
+			task.DependsOn = uniqueIDs(task.DependsOn)
+			if err := taskUsc.checkDependencies(ctx, current.ID, task.DependsOn); err != nil {
+				return nil, err
+			}
+			dependsOn = task.DependsOn
+		}
+		status := task.Status
+		if status == "" {
+			status = current.Status        // new dependencies may block a task already started
+		}
+		if err := taskUsc.checkUnblocked(ctx, status, dependsOn); err != nil {
+			return nil, err
+		}
+	}
+
+	return taskUsc.taskRepo.UpdateTask(ctx, id, task)
+}
+
+// update task only if the client's ETag matches the task's current version ("*" matches any)
+func (taskUsc *taskUseCase) UpdateTaskIfMatch(ctx context.Context, id string, task *domain.Task, etag, actorRole string) (*domain.Task, error) {
+
+	current, err := taskUsc.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrTaskNotFound
+	}
+	if etag != "*" && etag != current.ETag() {
+		return nil, domain.ErrPreconditionFailed        // changed since the client read it
+	}
+
+	return taskUsc.updateTask(ctx, id, task, actorRole, current)      // the checks reuse the task just read
+}
+
+// assign task to an existing user - admins assign to anyone, other users at most to themselves
+func (taskUsc *taskUseCase) AssignTask(ctx context.Context, taskID, userID, actorID, actorRole string) error {
+
+	// validate input
+	if taskID == "" {
+		return errors.New("task ID cannot be empty")
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+	// check the assignment policy before looking anything up
+	if actorRole != "admin" && (!taskUsc.config.SelfAssign || userID != actorID) {
+		return domain.ErrAssignForbidden
+	}
+	if taskUsc.userRepo == nil {
+		return errors.New("task assignment requires a user repository")
+	}
+
+	// verify assignee and task exist first
+	if _, err := taskUsc.userRepo.GetUserById(ctx, userObjID); err != nil {
+		return err
+	}
+	task, err := taskUsc.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	// work already started stays with its assignee until an admin moves it or the status changes
+	if taskUsc.config.LockInProgressAssignee && actorRole != "admin" && task.Status == "in_progress" &&
+		!task.AssignedTo.IsZero() && task.AssignedTo != userObjID {
+		return domain.ErrTaskInProgress
+	}
+
+	// assignment is not a client updatable field, so it bypasses the UpdateTask allowlist
+	_, err = taskUsc.taskRepo.UpdateTask(ctx, taskID, &domain.Task{AssignedTo: userObjID})
+	return err
+}
+
+// complete many tasks at once, e.g. when closing a sprint - tasks that may not move to completed are skipped
+func (taskUsc *taskUseCase) CompleteTasks(ctx context.Context, taskIDs []string) (int64, error) {
+
+	// validate input
+	if len(taskIDs) > MaxBulkTasks {
+		return 0, domain.ErrTooManyTasks
+	}
+	objIDs := make([]primitive.ObjectID, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		objID, err := primitive.ObjectIDFromHex(taskID)
+		if err != nil {
+			return 0, domain.ErrInvalidTaskID
+		}
+		objIDs = append(objIDs, objID)
+	}
+	if len(taskIDs) == 0 {
+		return 0, nil
+	}
+
+	// blocked tasks are skipped like tasks in other statuses, UpdateTask refuses to complete them too
+	unblocked, err := taskUsc.unblockedTaskIDs(ctx, objIDs)
+	if err != nil {
+		return 0, err
 	}
+	if len(unblocked) == 0 {
+		return 0, nil
+	}
+
+	return taskUsc.taskRepo.CompleteTasks(ctx, unblocked, completableStatuses())
+}
+
+// ids of the existing tasks among ids without an unfinished dependency, in the given order -
+// dependencies are judged by their stored status, so one completed in the same call still blocks
+func (taskUsc *taskUseCase) unblockedTaskIDs(ctx context.Context, ids []primitive.ObjectID) ([]string, error) {
+
+	tasks, err := taskUsc.taskRepo.GetTasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]domain.Task, len(tasks))
+	var depIDs []primitive.ObjectID
+	for _, task := range tasks {
+		byID[task.ID] = task
+		depIDs = append(depIDs, task.DependsOn...)
+	}
+
+	// one lookup for the dependencies of every task, deleted dependencies no longer block
+	unfinished := make(map[primitive.ObjectID]bool)
+	if len(depIDs) > 0 {
+		deps, err := taskUsc.taskRepo.GetTasksByIDs(ctx, depIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if !finishedStatuses[dep.Status] {
+				unfinished[dep.ID] = true
+			}
+		}
+	}
+
+	unblocked := make([]string, 0, len(ids))
+	for _, id := range ids {
+		task, ok := byID[id]
+		if !ok {
+			continue
+		}
+		blocked := false
+		for _, dep := range task.DependsOn {
+			if unfinished[dep] {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			unblocked = append(unblocked, id.Hex())
+		}
+	}
+
+	return unblocked, nil
+}
+
+// move every task of a user to another existing user, e.g. before deactivating them
+func (taskUsc *taskUseCase) ReassignTasks(ctx context.Context, fromUserID, toUserID string) (int64, error) {
 
-	return taskUsc.taskRepo.UpdateTask(id, task)
-}
\ No newline at end of file
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(fromUserID); err != nil {
+		return 0, domain.ErrInvalidUserID
+	}
+	toObjID, err := primitive.ObjectIDFromHex(toUserID)
+	if err != nil {
+		return 0, domain.ErrInvalidUserID
+	}
+	if fromUserID == toUserID {
+		return 0, domain.ErrSameOwner
+	}
+	if taskUsc.userRepo == nil {
+		return 0, errors.New("task reassignment requires a user repository")
+	}
+
+	// the new owner must be an active user
+	if _, err := taskUsc.userRepo.GetUserById(ctx, toObjID); err != nil {
+		return 0, err
+	}
+
+	return taskUsc.taskRepo.ReassignOwner(ctx, fromUserID, toUserID)
+}
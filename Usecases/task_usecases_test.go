@@ -2,12 +2,17 @@ package usecases
 
 // imports
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
+	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Repositories/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // test suite for TaskUseCase
@@ -17,6 +22,9 @@ type TaskUseCaseTestSuite struct {
 	taskUsecase  domain.TaskUseCase                         // task usecase instance being tested
 }
 
+// user creating tasks in the tests
+var testOwnerID = primitive.NewObjectID()
+
 // intialize the test suite before each test
 func (suite *TaskUseCaseTestSuite) SetupTest() {
 	suite.mockRepo = new(mock_repositories.MockTaskRepository)      // create new mock repository
@@ -37,16 +45,97 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_Success() {
 
 	// mock CreateTask of the repository to return expected task
 	suite.mockRepo.
-		On("CreateTask", task).        
+		On("CreateTask", mock.Anything, task).        
 		Return(expected, nil)          
 
 	// call the CreateTask method on usecase
-	result, err := suite.taskUsecase.CreateTask(task)
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
 
 	// verify the results
 	assert.NoError(suite.T(), err)                                  // no error expected
 	assert.Equal(suite.T(), expected, result)                       // result should match expected task
-	suite.mockRepo.AssertCalled(suite.T(), "CreateTask", task)      // verify CreateTask was called with correct task
+	assert.Equal(suite.T(), testOwnerID, task.OwnerID)              // task should carry its owner
+	suite.mockRepo.AssertCalled(suite.T(), "CreateTask", mock.Anything, task)      // verify CreateTask was called with correct task
+}
+
+// tests a submitted due date timezone is stored with the task
+func (suite *TaskUseCaseTestSuite) TestCreateTask_DueDateTZStored() {
+
+	// create test task entered in Addis Ababa
+	task := &domain.Task{
+		Title:       "Test",
+		Description: "Test description",
+		DueDate:     time.Now().Add(48 * time.Hour).UTC(),
+		DueDateTZ:   "Africa/Addis_Ababa",
+		Status:      "pending",
+	}
+
+	// mock CreateTask of the repository, checking the timezone is passed on
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, mock.MatchedBy(func(stored *domain.Task) bool {
+			return stored.DueDateTZ == "Africa/Addis_Ababa"
+		})).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                         // no error expected
+	assert.Equal(suite.T(), "Africa/Addis_Ababa", result.DueDateTZ)        // timezone kept
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// tests an unknown due date timezone is rejected
+func (suite *TaskUseCaseTestSuite) TestCreateTask_InvalidDueDateTZ() {
+
+	for _, tz := range []string{"Mars/Olympus_Mons", "Local"} {
+		task := &domain.Task{
+			Title:       "Test",
+			Description: "Test description",
+			DueDate:     time.Now().Add(48 * time.Hour),
+			DueDateTZ:   tz,
+			Status:      "pending",
+		}
+
+		// call the CreateTask method on usecase
+		_, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidTimezone, tz)      // not a client timezone
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+}
+
+// tests the due date timezone can be updated on its own and is validated
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_DueDateTZ() {
+
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, "some-id", &domain.Task{DueDateTZ: "America/New_York"}).
+		Return(&domain.Task{DueDateTZ: "America/New_York"}, nil)
+
+	updated, err := suite.taskUsecase.UpdateTask(context.Background(), "some-id", &domain.Task{DueDateTZ: "America/New_York"}, "admin")
+	assert.NoError(suite.T(), err)                                          // timezone alone is a valid update
+	assert.Equal(suite.T(), "America/New_York", updated.DueDateTZ)
+
+	_, err = suite.taskUsecase.UpdateTask(context.Background(), "some-id", &domain.Task{DueDateTZ: "Nowhere/Special"}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTimezone)               // unknown zone rejected
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "UpdateTask", 1)
+}
+
+// tests task creation with an invalid owner id
+func (suite *TaskUseCaseTestSuite) TestCreateTask_InvalidOwnerID() {
+
+	// create valid test task
+	task := &domain.Task{
+		Title:       "Test",
+		Description: "Test description",
+		DueDate:     time.Now().Add(48 * time.Hour),
+		Status:      "pending",
+	}
+
+	for _, ownerID := range []string{"", "not-an-object-id"} {
+		result, err := suite.taskUsecase.CreateTask(context.Background(), task, ownerID)
+		assert.Nil(suite.T(), result)                                  // result should be nil
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)        // error should be invalid user id
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)      // nothing should be stored
 }
 
 // tests task creation with invalid due date - past date
@@ -62,11 +151,11 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_InvalidDueDate() {
 
 	// mock CreateTask of the repository to return an error
 	suite.mockRepo.
-		On("CreateTask", task).
+		On("CreateTask", mock.Anything, task).
 		Return(nil, domain.ErrInvalidDueDate)
 
 	// call the CreateTask method on usecase
-	result, err := suite.taskUsecase.CreateTask(task)
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
 
 	// verify error response
 	assert.Nil(suite.T(), result)                                             // result should be nil
@@ -85,7 +174,7 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_EmptyTitle() {
     }
 	
 	// call the CreateTask method on usecase
-    result, err := suite.taskUsecase.CreateTask(task)
+    result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
     assert.Nil(suite.T(), result)                                             // result should be nil
     assert.EqualError(suite.T(), err, "task title cannot be empty")           // error message should match expected 
 }
@@ -102,7 +191,7 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_EmptyDescription() {
     }
 
 	// call the CreateTask method on usecase
-    result, err := suite.taskUsecase.CreateTask(task)
+    result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
     assert.Nil(suite.T(), result)                                                // result should be nil
     assert.EqualError(suite.T(), err, "task description cannot be empty")        // error message should match expected 
 }
@@ -119,7 +208,7 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_EmptyDueDate() {
     }
 
 	// call the CreateTask method on usecase
-    result, err := suite.taskUsecase.CreateTask(task)
+    result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
     assert.Nil(suite.T(), result)                                         // result should be nil
     assert.EqualError(suite.T(), err, "due date cannot be empty")         // error message should match expected 
 }  
@@ -139,16 +228,451 @@ func (suite *TaskUseCaseTestSuite) TestCreateTask_EmptyStatusDefaultsPending() {
 
 	// mock CreateTask of the repository to return an expected task and nil
     suite.mockRepo.
-        On("CreateTask", task).
+        On("CreateTask", mock.Anything, task).
         Return(expected, nil)
 
 	// call the CreateTask method on usecase
-    result, err := suite.taskUsecase.CreateTask(task)
+    result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
     assert.NoError(suite.T(), err)                           // should be no error
     assert.Equal(suite.T(), expected, result)                // result should match expected
     assert.Equal(suite.T(), "pending", task.Status)          // task status should match pending 
 }
 
+// tests task creation with empty priority defaults to medium
+func (suite *TaskUseCaseTestSuite) TestCreateTask_EmptyPriorityDefaultsMedium() {
+
+	// create test task without priority
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	// mock CreateTask of the repository to return the task
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, task).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                             // no error expected
+	assert.Equal(suite.T(), "medium", result.Priority)         // priority should default to medium
+}
+
+// tests task creation with a valid priority
+func (suite *TaskUseCaseTestSuite) TestCreateTask_ValidPriority() {
+
+	// create test task with high priority
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		Priority:    "high",
+	}
+
+	// mock CreateTask of the repository to return the task
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, task).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                           // no error expected
+	assert.Equal(suite.T(), "high", result.Priority)         // priority should be kept
+}
+
+// tests task creation with an invalid priority
+func (suite *TaskUseCaseTestSuite) TestCreateTask_InvalidPriority() {
+
+	// create test task with unknown priority
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		Priority:    "urgent",
+	}
+
+	// call the CreateTask method on usecase
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+	assert.Nil(suite.T(), result)                                      // result should be nil
+	assert.EqualError(suite.T(), err, "invalid task priority")         // error message should match expected
+}
+
+// tests task creation without priority uses the creator's preferred default
+func (suite *TaskUseCaseTestSuite) TestCreateTask_UserDefaultPriority() {
+
+	// usecase that can read user preferences
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	// mock GetUserById to return a user preferring high priority
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID, Preferences: domain.Preferences{DefaultPriority: "high"}}, nil)
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, task).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := usecase.CreateTask(context.Background(), task, userID.Hex())
+	assert.NoError(suite.T(), err)                           // no error expected
+	assert.Equal(suite.T(), "high", result.Priority)         // priority should come from the preference
+}
+
+// tests an explicit task priority wins over the creator's preference
+func (suite *TaskUseCaseTestSuite) TestCreateTask_ExplicitPriorityOverridesPreference() {
+
+	// usecase that can read user preferences
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+		Priority:    "low",
+	}
+
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, task).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := usecase.CreateTask(context.Background(), task, primitive.NewObjectID().Hex())
+	assert.NoError(suite.T(), err)                                   // no error expected
+	assert.Equal(suite.T(), "low", result.Priority)                  // explicit priority should be kept
+	userRepo.AssertNotCalled(suite.T(), "GetUserById", mock.Anything, mock.Anything)      // preference not needed
+}
+
+// tests task creation falls back to the global default when the creator has no preference
+func (suite *TaskUseCaseTestSuite) TestCreateTask_NoPreferenceDefaultsMedium() {
+
+	// usecase that can read user preferences
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	userID := primitive.NewObjectID()
+	task := &domain.Task{
+		Title:       "title",
+		Description: "desc",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      "pending",
+	}
+
+	// mock GetUserById to return a user without preferences
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID}, nil)
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, task).
+		Return(task, nil)
+
+	// call the CreateTask method on usecase
+	result, err := usecase.CreateTask(context.Background(), task, userID.Hex())
+	assert.NoError(suite.T(), err)                             // no error expected
+	assert.Equal(suite.T(), "medium", result.Priority)         // priority should fall back to medium
+}
+
+// tests task update with invalid priority
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_InvalidPriority() {
+
+	// call the UpdateTask method on usecase
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), "some-id", &domain.Task{Priority: "urgent"}, "admin")
+	assert.Nil(suite.T(), result)                                      // result should be nil
+	assert.EqualError(suite.T(), err, "invalid task priority")         // error message should match expected
+}
+
+// returns noon UTC of the first given weekday at least a week from now
+func nextWeekday(day time.Weekday) time.Time {
+
+	date := time.Now().UTC().AddDate(0, 0, 7)
+	for date.Weekday() != day {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+}
+
+// tests weekend due dates are rejected only when workdays are enforced
+func (suite *TaskUseCaseTestSuite) TestCreateTask_WorkdayDueDates() {
+
+	newTask := func(due time.Time) *domain.Task {
+		return &domain.Task{Title: "title", Description: "desc", DueDate: due, Status: "pending", Priority: "low"}
+	}
+	enforced := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		WorkdayDueDates: true,
+	})
+
+	// saturday rejected when enforced
+	_, err := enforced.CreateTask(context.Background(), newTask(nextWeekday(time.Saturday)), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrDueDateNotWorkday)       // weekend rejected
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+
+	// monday accepted when enforced, sunday accepted when not
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, mock.AnythingOfType("*domain.Task")).
+		Return(&domain.Task{}, nil)
+	_, err = enforced.CreateTask(context.Background(), newTask(nextWeekday(time.Monday)), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                    // workday accepted
+	_, err = suite.taskUsecase.CreateTask(context.Background(), newTask(nextWeekday(time.Sunday)), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                    // off by default
+}
+
+// tests the weekday is judged in the owner's timezone on update
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_WorkdayOwnerTimezone() {
+
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		WorkdayDueDates: true,
+	})
+	taskID := primitive.NewObjectID().Hex()
+
+	// friday 22:00 UTC is already saturday for an owner in UTC+3
+	friday := nextWeekday(time.Friday).Add(10 * time.Hour)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(&domain.Task{OwnerID: testOwnerID}, nil)
+	userRepo.
+		On("GetUserById", mock.Anything, testOwnerID).
+		Return(&domain.User{ID: testOwnerID, Preferences: domain.Preferences{Timezone: "Africa/Addis_Ababa"}}, nil)
+
+	// call the UpdateTask method on usecase
+	_, err := usecase.UpdateTask(context.Background(), taskID, &domain.Task{DueDate: friday}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrDueDateNotWorkday)       // weekend in the owner's calendar
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a user is limited to the configured creations per window and allowed again once it passed
+func (suite *TaskUseCaseTestSuite) TestCreateTask_RateLimit() {
+
+	// usecase allowing two creations per hour on a controllable clock
+	now := time.Now()
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		CreateRateLimit: 2,
+		CreateAttempts:  infrastructure.NewMemoryAttemptStore(time.Hour, func() time.Time { return now }),
+	})
+	newTask := func() *domain.Task {
+		return &domain.Task{Title: "title", Description: "desc", DueDate: time.Now().Add(48 * time.Hour), Priority: "low"}
+	}
+
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, mock.AnythingOfType("*domain.Task")).
+		Return(&domain.Task{}, nil)
+	userRepo.
+		On("GetUserById", mock.Anything, testOwnerID).
+		Return(&domain.User{ID: testOwnerID, Role: "user"}, nil)
+
+	// two creations reach the limit
+	for i := 0; i < 2; i++ {
+		_, err := usecase.CreateTask(context.Background(), newTask(), testOwnerID.Hex())
+		assert.NoError(suite.T(), err)                                 // within the limit
+	}
+	_, err := usecase.CreateTask(context.Background(), newTask(), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrCreateRateExceeded)      // third creation rejected
+	assert.EqualError(suite.T(), err, "task creation rate exceeded")
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateTask", 2)     // rejected task not stored
+
+	// other users are counted separately
+	_, err = usecase.CreateTask(context.Background(), newTask(), primitive.NewObjectID().Hex())
+	assert.NoError(suite.T(), err)
+
+	// the window passes
+	now = now.Add(time.Hour + time.Second)
+	_, err = usecase.CreateTask(context.Background(), newTask(), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                     // allowed again
+}
+
+// tests admins are not limited
+func (suite *TaskUseCaseTestSuite) TestCreateTask_RateLimitAdminExempt() {
+
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		CreateRateLimit: 1,
+		CreateAttempts:  infrastructure.NewMemoryAttemptStore(time.Hour, nil),
+	})
+
+	suite.mockRepo.
+		On("CreateTask", mock.Anything, mock.AnythingOfType("*domain.Task")).
+		Return(&domain.Task{}, nil)
+	userRepo.
+		On("GetUserById", mock.Anything, testOwnerID).
+		Return(&domain.User{ID: testOwnerID, Role: "admin"}, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := usecase.CreateTask(context.Background(), &domain.Task{Title: "title", Description: "desc", DueDate: time.Now().Add(48 * time.Hour), Priority: "low"}, testOwnerID.Hex())
+		assert.NoError(suite.T(), err)                                 // admin never limited
+	}
+}
+
+// tests the upcoming window starts now and ends the given days later
+func (suite *TaskUseCaseTestSuite) TestGetUpcomingTasks_Window() {
+
+	// usecase with a fixed clock
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		Now:             func() time.Time { return now },
+	})
+
+	// mock GetUpcomingTasks of the repository with the exact window bounds
+	suite.mockRepo.
+		On("GetUpcomingTasks", mock.Anything, "", now, time.Date(2025, 7, 8, 12, 0, 0, 0, time.UTC)).
+		Return(nil, nil)
+
+	// call the GetUpcomingTasks method on usecase
+	tasks, err := usecase.GetUpcomingTasks(context.Background(), "", 7)
+	assert.NoError(suite.T(), err)                       // no error expected
+	assert.NotNil(suite.T(), tasks)                      // result should be an empty slice
+	suite.mockRepo.AssertExpectations(suite.T())         // verify window bounds
+}
+
+// tests overdue tasks are looked up against the clock at request time
+func (suite *TaskUseCaseTestSuite) TestGetOverdueTasks_ClockPerRequest() {
+
+	// usecase with a clock that moves between requests
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		Now:             func() time.Time { return now },
+	})
+
+	suite.mockRepo.On("GetOverdueTasks", mock.Anything, "", now).Return([]domain.Task{{Title: "late", Status: "pending"}}, nil)
+	later := now.Add(time.Hour)
+	suite.mockRepo.On("GetOverdueTasks", mock.Anything, "", later).Return(nil, nil)
+
+	tasks, err := usecase.GetOverdueTasks(context.Background(), "")
+	assert.NoError(suite.T(), err)                       // no error expected
+	assert.Len(suite.T(), tasks, 1)                      // repository result passed through
+
+	now = later
+	tasks, err = usecase.GetOverdueTasks(context.Background(), "")
+	assert.NoError(suite.T(), err)                       // no error expected
+	assert.Equal(suite.T(), []domain.Task{}, tasks)      // nil from the repository becomes an empty slice
+	suite.mockRepo.AssertExpectations(suite.T())         // both clock readings were used
+}
+
+// tests the upcoming window ends with the last day in the owner's timezone
+func (suite *TaskUseCaseTestSuite) TestGetUpcomingTasks_OwnerTimezone() {
+
+	// usecase with a fixed clock that can read user preferences
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		Now:             func() time.Time { return now },
+	})
+
+	// mock GetUserById to return an owner in UTC+3
+	userRepo.
+		On("GetUserById", mock.Anything, testOwnerID).
+		Return(&domain.User{ID: testOwnerID, Preferences: domain.Preferences{Timezone: "Africa/Addis_Ababa"}}, nil)
+	// mock GetUpcomingTasks of the repository with the end of the owner's seventh day
+	loc, _ := time.LoadLocation("Africa/Addis_Ababa")
+	end := time.Date(2025, 7, 8, 23, 59, 59, 999999999, loc)
+	suite.mockRepo.
+		On("GetUpcomingTasks", mock.Anything, testOwnerID.Hex(), now, mock.MatchedBy(func(to time.Time) bool { return to.Equal(end) })).
+		Return([]domain.Task{}, nil)
+
+	// call the GetUpcomingTasks method on usecase
+	_, err := usecase.GetUpcomingTasks(context.Background(), testOwnerID.Hex(), 7)
+	assert.NoError(suite.T(), err)                       // no error expected
+	suite.mockRepo.AssertExpectations(suite.T())         // verify window uses the owner's day
+}
+
+// tests the upcoming window accepts the largest allowed size
+func (suite *TaskUseCaseTestSuite) TestGetUpcomingTasks_MaxDays() {
+
+	// mock GetUpcomingTasks of the repository for any window
+	suite.mockRepo.
+		On("GetUpcomingTasks", mock.Anything, testOwnerID.Hex(), mock.Anything, mock.Anything).
+		Return([]domain.Task{}, nil)
+
+	_, err := suite.taskUsecase.GetUpcomingTasks(context.Background(), testOwnerID.Hex(), MaxUpcomingDays)
+	assert.NoError(suite.T(), err)        // no error expected
+}
+
+// tests the upcoming window rejects sizes outside the allowed range
+func (suite *TaskUseCaseTestSuite) TestGetUpcomingTasks_InvalidDays() {
+
+	for _, days := range []int{0, -1, MaxUpcomingDays + 1} {
+		tasks, err := suite.taskUsecase.GetUpcomingTasks(context.Background(), "", days)
+		assert.Nil(suite.T(), tasks)                                  // result should be nil
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidDays)         // error should be invalid days
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetUpcomingTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests SearchTasks trims the keyword before searching
+func (suite *TaskUseCaseTestSuite) TestSearchTasks_TrimsKeyword() {
+
+	suite.mockRepo.On("SearchTasks", mock.Anything, "report").Return([]domain.Task{{Title: "Weekly report"}}, nil)
+
+	tasks, err := suite.taskUsecase.SearchTasks(context.Background(), "  report ")        // call the SearchTasks method on usecase
+	assert.NoError(suite.T(), err)                                  // should succeed
+	assert.Len(suite.T(), tasks, 1)                                 // repository result passed through
+}
+
+// tests SearchTasks rejects empty and overly long keywords
+func (suite *TaskUseCaseTestSuite) TestSearchTasks_InvalidKeyword() {
+
+	for _, keyword := range []string{"", "   ", strings.Repeat("a", MaxSearchQueryLength+1)} {
+		tasks, err := suite.taskUsecase.SearchTasks(context.Background(), keyword)
+		assert.Nil(suite.T(), tasks)                                      // no result
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidSearchQuery)      // error should be invalid search query
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "SearchTasks", mock.Anything, mock.Anything)
+}
+
+// tests SearchTasks accepts a keyword at the limit counted in characters, not bytes
+func (suite *TaskUseCaseTestSuite) TestSearchTasks_LimitInCharacters() {
+
+	keyword := strings.Repeat("é", MaxSearchQueryLength)        // 200 bytes, 100 characters
+	suite.mockRepo.On("SearchTasks", mock.Anything, keyword).Return(nil, nil)
+
+	tasks, err := suite.taskUsecase.SearchTasks(context.Background(), keyword)
+	assert.NoError(suite.T(), err)                       // should succeed
+	assert.Equal(suite.T(), []domain.Task{}, tasks)      // nil from the repository becomes an empty slice
+}
+
+// tests GetTasksByDueDateRange rejects a window ending before it starts
+func (suite *TaskUseCaseTestSuite) TestGetTasksByDueDateRange_Inverted() {
+
+	from := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	tasks, err := suite.taskUsecase.GetTasksByDueDateRange(context.Background(), from, from.Add(-time.Hour))
+	assert.Nil(suite.T(), tasks)                                       // no result
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidDueDateRange)      // error should be invalid range
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTasksByDueDateRange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests GetTasksByDueDateRange passes an open ended window through
+func (suite *TaskUseCaseTestSuite) TestGetTasksByDueDateRange_OpenEnded() {
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	suite.mockRepo.On("GetTasksByDueDateRange", mock.Anything, from, time.Time{}).Return(nil, nil)
+
+	tasks, err := suite.taskUsecase.GetTasksByDueDateRange(context.Background(), from, time.Time{})
+	assert.NoError(suite.T(), err)                       // should succeed
+	assert.Equal(suite.T(), []domain.Task{}, tasks)      // nil from the repository becomes an empty slice
+}
+
 // tests deletion of a non-existent task
 func (suite *TaskUseCaseTestSuite) TestDeleteTask_NotFound() {
 	
@@ -157,11 +681,11 @@ func (suite *TaskUseCaseTestSuite) TestDeleteTask_NotFound() {
 
 	// mock GetTaskByID of the repository to return an error
 	suite.mockRepo.
-		On("GetTaskByID", id).
+		On("GetTaskByID", mock.Anything, id).
 		Return(nil, domain.ErrTaskNotFound)
 
 	// call the DeleteTask method on usecase
-	err := suite.taskUsecase.DeleteTask(id)
+	err := suite.taskUsecase.DeleteTask(context.Background(), id)
 
 	// verify error response
 	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)      // should return task not found error
@@ -175,26 +699,479 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask_InvalidStatus() {
 	task := &domain.Task{Status: "invalid_status"}      // invalid status
 
 	// call the UpdateTask method on usecase
-	result, err := suite.taskUsecase.UpdateTask(id, task)
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), id, task, "admin")
 
 	// verify error response
 	assert.Nil(suite.T(), result)                                  // result should be nil
 	assert.EqualError(suite.T(), err, "invalid task status")       // error message should match expected
 }
 
+// tests every status change against the transition rules, with and without reopening completed tasks
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_StatusTransitions() {
+
+	cases := []struct {
+		from, to  string
+		reopen    bool
+		allowed   bool
+	}{
+		{"pending", "pending", false, true},
+		{"pending", "in_progress", false, true},
+		{"pending", "completed", false, true},
+		{"in_progress", "in_progress", false, true},
+		{"in_progress", "completed", false, true},
+		{"in_progress", "pending", false, true},
+		{"completed", "completed", false, true},
+		{"completed", "pending", false, false},
+		{"completed", "in_progress", false, false},
+		{"completed", "pending", true, true},
+		{"completed", "in_progress", true, true},
+	}
+
+	for _, tc := range cases {
+		name := tc.from + " -> " + tc.to
+		repo := new(mock_repositories.MockTaskRepository)
+		usecase := NewTaskUseCaseWithConfig(repo, nil, TaskConfig{
+			UpdatableFields: DefaultUpdatableTaskFields,
+			ReopenCompleted: tc.reopen,
+		})
+		update := &domain.Task{Status: tc.to}
+		repo.On("GetTaskByID", mock.Anything, "some-id").Return(&domain.Task{Status: tc.from}, nil)
+		repo.On("UpdateTask", mock.Anything, "some-id", update).Return(&domain.Task{Status: tc.to}, nil)
+
+		result, err := usecase.UpdateTask(context.Background(), "some-id", update, "admin")
+		if tc.allowed {
+			assert.NoError(suite.T(), err, name)                    // transition allowed
+			assert.Equal(suite.T(), tc.to, result.Status, name)     // status changed
+		} else {
+			assert.Nil(suite.T(), result, name)                                          // result should be nil
+			assert.ErrorIs(suite.T(), err, domain.ErrInvalidStatusTransition, name)      // transition rejected
+			repo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)  // nothing stored
+		}
+	}
+}
+
+// tests a locked completed task can only be changed by admins while other tasks stay editable
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_LockCompleted() {
+
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		ReopenCompleted: true,
+		LockCompleted:   true,
+	})
+	done, open := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, done).Return(&domain.Task{Status: "completed"}, nil)
+	suite.mockRepo.On("GetTaskByID", mock.Anything, open).Return(&domain.Task{Status: "pending"}, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, done, mock.Anything).Return(&domain.Task{Status: "pending"}, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, open, mock.Anything).Return(&domain.Task{Title: "Renamed"}, nil)
+
+	result, err := usecase.UpdateTask(context.Background(), done, &domain.Task{Title: "Renamed"}, "user")
+	assert.Nil(suite.T(), result)                                       // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskCompletedLocked)       // user editing a completed task
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, done, mock.Anything)
+
+	result, err = usecase.UpdateTask(context.Background(), done, &domain.Task{Status: "pending"}, "admin")
+	assert.NoError(suite.T(), err)                                      // admin reopening it
+	assert.Equal(suite.T(), "pending", result.Status)
+
+	result, err = usecase.UpdateTask(context.Background(), open, &domain.Task{Title: "Renamed"}, "user")
+	assert.NoError(suite.T(), err)                                      // user editing an open task
+	assert.Equal(suite.T(), "Renamed", result.Title)
+}
+
+// tests completed tasks stay editable by anyone without the lock
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_CompletedUnlockedByDefault() {
+
+	suite.mockRepo.On("UpdateTask", mock.Anything, "some-id", mock.Anything).Return(&domain.Task{Title: "Renamed"}, nil)
+
+	_, err := suite.taskUsecase.UpdateTask(context.Background(), "some-id", &domain.Task{Title: "Renamed"}, "user")
+	assert.NoError(suite.T(), err)                                      // no lock configured
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything, mock.Anything)      // status not even looked up
+}
+
+// tests a status change of a missing task
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_StatusTaskNotFound() {
+
+	suite.mockRepo.On("GetTaskByID", mock.Anything, "some-id").Return(nil, domain.ErrTaskNotFound)
+
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), "some-id", &domain.Task{Status: "pending"}, "admin")
+	assert.Nil(suite.T(), result)                                  // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)         // should return task not found error
+}
+
 // tests DeleteTask with empty id
 func (suite *TaskUseCaseTestSuite) TestDeleteTask_EmptyID() {
 
 	// call the DeleteTask method on usecase
-    err := suite.taskUsecase.DeleteTask("")
+    err := suite.taskUsecase.DeleteTask(context.Background(), "")
     assert.EqualError(suite.T(), err, "task ID cannot be empty")        // error message should match expected
 }
 
+// tests RestoreTask passes the id to the repository
+func (suite *TaskUseCaseTestSuite) TestRestoreTask_Success() {
+
+	id := primitive.NewObjectID().Hex()
+	suite.mockRepo.On("RestoreTask", mock.Anything, id).Return(nil)
+
+	err := suite.taskUsecase.RestoreTask(context.Background(), id)        // call the RestoreTask method on usecase
+	assert.NoError(suite.T(), err)                  // should succeed
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// tests RestoreTask of a non-existent task
+func (suite *TaskUseCaseTestSuite) TestRestoreTask_NotFound() {
+
+	id := primitive.NewObjectID().Hex()
+	suite.mockRepo.On("RestoreTask", mock.Anything, id).Return(domain.ErrTaskNotFound)
+
+	err := suite.taskUsecase.RestoreTask(context.Background(), id)                        // call the RestoreTask method on usecase
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)          // should return task not found error
+}
+
+// tests RestoreTask with empty id
+func (suite *TaskUseCaseTestSuite) TestRestoreTask_EmptyID() {
+
+	err := suite.taskUsecase.RestoreTask(context.Background(), "")
+	assert.EqualError(suite.T(), err, "task ID cannot be empty")        // error message should match expected
+	suite.mockRepo.AssertNotCalled(suite.T(), "RestoreTask", mock.Anything, mock.Anything)
+}
+
+// tests a task cannot start while a dependency is unfinished
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_BlockedByDependency() {
+
+	id := primitive.NewObjectID()
+	dep := primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "pending", DependsOn: []primitive.ObjectID{dep}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{dep}).Return([]domain.Task{{ID: dep, Status: "pending"}}, nil)
+
+	for _, status := range []string{"in_progress", "completed"} {
+		result, err := suite.taskUsecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{Status: status}, "admin")
+		assert.Nil(suite.T(), result)                                  // result should be nil
+		assert.ErrorIs(suite.T(), err, domain.ErrTaskBlocked, status)  // dependency not finished
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a task may complete once every dependency is finished
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_DependenciesFinished() {
+
+	id := primitive.NewObjectID()
+	deps := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	update := &domain.Task{Status: "completed"}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "in_progress", DependsOn: deps}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, deps).Return([]domain.Task{{ID: deps[0], Status: "completed"}, {ID: deps[1], Status: "archived"}}, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, id.Hex(), update).Return(&domain.Task{ID: id, Status: "completed"}, nil)
+
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), id.Hex(), update, "admin")
+	assert.NoError(suite.T(), err)                          // no error expected
+	assert.Equal(suite.T(), "completed", result.Status)     // status changed
+}
+
+// tests dependencies leading back to the task are rejected
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_DependencyCycle() {
+
+	// a -> b -> c -> a once a depends on b
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, a.Hex()).Return(&domain.Task{ID: a, Status: "pending"}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{b}).Return([]domain.Task{{ID: b, DependsOn: []primitive.ObjectID{c}}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{c}).Return([]domain.Task{{ID: c, DependsOn: []primitive.ObjectID{a}}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{a}).Return([]domain.Task{{ID: a, DependsOn: []primitive.ObjectID{b}}}, nil)
+
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), a.Hex(), &domain.Task{DependsOn: []primitive.ObjectID{b}}, "admin")
+	assert.Nil(suite.T(), result)                                    // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrDependencyCycle)        // cycle through c
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a task cannot depend on itself
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_SelfDependency() {
+
+	id := primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "pending"}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{id}).Return([]domain.Task{{ID: id}}, nil)
+
+	_, err := suite.taskUsecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{DependsOn: []primitive.ObjectID{id, id}}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrDependencyCycle)        // repeated ids are checked once
+}
+
+// tests unknown dependency ids are rejected
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_DependencyNotFound() {
+
+	id := primitive.NewObjectID()
+	missing := primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "pending"}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{missing}).Return([]domain.Task{}, nil)
+
+	_, err := suite.taskUsecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{DependsOn: []primitive.ObjectID{missing}}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrDependencyNotFound)     // dependency must exist
+}
+
+// tests the direct dependency limit allows exactly MaxDependencies
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_MaxDependencies() {
+
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, MaxDependencies: 2})
+	id := primitive.NewObjectID()
+	deps := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "pending"}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, deps).Return([]domain.Task{{ID: deps[0]}, {ID: deps[1]}}, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, id.Hex(), mock.Anything).Return(&domain.Task{ID: id, DependsOn: deps}, nil)
+
+	_, err := usecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{DependsOn: deps}, "admin")
+	assert.NoError(suite.T(), err)                                  // at the limit
+
+	tooMany := append(deps, primitive.NewObjectID())
+	_, err = usecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{DependsOn: tooMany}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrTooManyDependencies)   // one over the limit
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTasksByIDs", mock.Anything, tooMany)     // rejected before any lookup
+}
+
+// tests the dependency depth limit allows chains of exactly MaxDependencyDepth
+func (suite *TaskUseCaseTestSuite) TestCreateTask_MaxDependencyDepth() {
+
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, MaxDependencyDepth: 2})
+	newTask := func(dep primitive.ObjectID) *domain.Task {
+		return &domain.Task{Title: "Deploy", Description: "Ship the release", DueDate: time.Now().Add(48 * time.Hour), DependsOn: []primitive.ObjectID{dep}}
+	}
+
+	// a -> b is two levels deep for a new task depending on a, c -> a -> b is three
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{a}).Return([]domain.Task{{ID: a, DependsOn: []primitive.ObjectID{b}}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{b}).Return([]domain.Task{{ID: b}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{c}).Return([]domain.Task{{ID: c, DependsOn: []primitive.ObjectID{a}}}, nil)
+	suite.mockRepo.On("CreateTask", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+	_, err := usecase.CreateTask(context.Background(), newTask(a), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                  // at the limit
+
+	_, err = usecase.CreateTask(context.Background(), newTask(c), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrDependencyTooDeep)     // one level over the limit
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateTask", 1)  // only the first task is stored
+}
+
+// tests a task may only join a project of its own owner
+func (suite *TaskUseCaseTestSuite) TestCreateTask_ProjectOwner() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects})
+	own := &domain.Project{ID: primitive.NewObjectID(), OwnerID: testOwnerID}
+	foreign := &domain.Project{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}
+	projects.On("GetProjectByID", mock.Anything, own.ID).Return(own, nil)
+	projects.On("GetProjectByID", mock.Anything, foreign.ID).Return(foreign, nil)
+	suite.mockRepo.On("CreateTask", mock.Anything, mock.Anything).Return(&domain.Task{}, nil)
+
+	newTask := func(projectID primitive.ObjectID) *domain.Task {
+		return &domain.Task{Title: "Deploy", Description: "Ship the release", DueDate: time.Now().Add(48 * time.Hour), ProjectID: projectID}
+	}
+	_, err := usecase.CreateTask(context.Background(), newTask(own.ID), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                      // own project
+
+	_, err = usecase.CreateTask(context.Background(), newTask(foreign.ID), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectOwnerMismatch)      // another user's project
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateTask", 1)      // only the first task is stored
+}
+
+// tests moving a task into a project checks the project against the stored task's owner
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_ProjectOwner() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects})
+	id := primitive.NewObjectID()
+	foreign := &domain.Project{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, OwnerID: testOwnerID}, nil)
+	projects.On("GetProjectByID", mock.Anything, foreign.ID).Return(foreign, nil)
+
+	result, err := usecase.UpdateTask(context.Background(), id.Hex(), &domain.Task{ProjectID: foreign.ID}, "admin")
+	assert.Nil(suite.T(), result)                                       // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectOwnerMismatch)      // another user's project
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a project's tasks are only listed for its owner, admins list any project
+func (suite *TaskUseCaseTestSuite) TestGetTasksByProject_Owner() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects})
+	own := &domain.Project{ID: primitive.NewObjectID(), OwnerID: testOwnerID}
+	foreign := &domain.Project{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}
+	projects.On("GetProjectByID", mock.Anything, own.ID).Return(own, nil)
+	projects.On("GetProjectByID", mock.Anything, foreign.ID).Return(foreign, nil)
+	suite.mockRepo.On("GetTasksByProject", mock.Anything, own.ID.Hex()).Return([]domain.Task{{Title: "Mine"}}, nil)
+	suite.mockRepo.On("GetTasksByProject", mock.Anything, foreign.ID.Hex()).Return([]domain.Task{{Title: "Theirs"}}, nil)
+
+	tasks, err := usecase.GetTasksByProject(context.Background(), own.ID.Hex(), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                      // own project
+	assert.Len(suite.T(), tasks, 1)
+
+	tasks, err = usecase.GetTasksByProject(context.Background(), foreign.ID.Hex(), testOwnerID.Hex())
+	assert.Nil(suite.T(), tasks)                                        // nothing listed
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectNotFound)           // another user's project is hidden
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTasksByProject", mock.Anything, foreign.ID.Hex())
+
+	tasks, err = usecase.GetTasksByProject(context.Background(), foreign.ID.Hex(), "")
+	assert.NoError(suite.T(), err)                                      // admins list any project
+	assert.Len(suite.T(), tasks, 1)
+}
+
+// tests MoveTask puts an own task into an own project and takes it out again
+func (suite *TaskUseCaseTestSuite) TestMoveTask() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects})
+	id := primitive.NewObjectID()
+	own := &domain.Project{ID: primitive.NewObjectID(), OwnerID: testOwnerID}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, OwnerID: testOwnerID}, nil)
+	projects.On("GetProjectByID", mock.Anything, own.ID).Return(own, nil)
+	suite.mockRepo.On("SetTaskProject", mock.Anything, id.Hex(), own.ID).Return(&domain.Task{ID: id, ProjectID: own.ID}, nil)
+	suite.mockRepo.On("SetTaskProject", mock.Anything, id.Hex(), primitive.NilObjectID).Return(&domain.Task{ID: id}, nil)
+
+	moved, err := usecase.MoveTask(context.Background(), id.Hex(), own.ID.Hex(), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                              // own project
+	assert.Equal(suite.T(), own.ID, moved.ProjectID)            // task is in the project
+
+	moved, err = usecase.MoveTask(context.Background(), id.Hex(), "", testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                              // no project
+	assert.True(suite.T(), moved.ProjectID.IsZero())            // task is out of the project
+	projects.AssertNumberOfCalls(suite.T(), "GetProjectByID", 1)      // no lookup without a project
+}
+
+// tests MoveTask rejects another user's project and hides another user's task
+func (suite *TaskUseCaseTestSuite) TestMoveTask_CrossOwner() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects})
+	id := primitive.NewObjectID()
+	foreign := &domain.Project{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, OwnerID: testOwnerID}, nil)
+	projects.On("GetProjectByID", mock.Anything, foreign.ID).Return(foreign, nil)
+
+	result, err := usecase.MoveTask(context.Background(), id.Hex(), foreign.ID.Hex(), testOwnerID.Hex())
+	assert.Nil(suite.T(), result)                                       // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrProjectOwnerMismatch)      // another user's project
+
+	result, err = usecase.MoveTask(context.Background(), id.Hex(), "", foreign.OwnerID.Hex())
+	assert.Nil(suite.T(), result)                                       // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)              // another user's task
+	suite.mockRepo.AssertNotCalled(suite.T(), "SetTaskProject", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests the owner reopens a completed task
+func (suite *TaskUseCaseTestSuite) TestReopenTask() {
+
+	id := primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, OwnerID: testOwnerID, Status: "completed"}, nil)
+	suite.mockRepo.On("ReopenTask", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, Status: "in_progress"}, nil)
+
+	task, err := suite.taskUsecase.ReopenTask(context.Background(), id.Hex(), testOwnerID.Hex(), "user")
+	assert.NoError(suite.T(), err)                                  // owner may reopen
+	assert.Equal(suite.T(), "in_progress", task.Status)             // back in progress
+	assert.True(suite.T(), task.CompletedAt.IsZero())               // completion time cleared
+}
+
+// tests only completed tasks of the actor are reopened
+func (suite *TaskUseCaseTestSuite) TestReopenTask_Rejected() {
+
+	open, done := primitive.NewObjectID(), primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, open.Hex()).Return(&domain.Task{ID: open, OwnerID: testOwnerID, Status: "pending"}, nil)
+	suite.mockRepo.On("GetTaskByID", mock.Anything, done.Hex()).Return(&domain.Task{ID: done, OwnerID: testOwnerID, Status: "completed"}, nil)
+
+	_, err := suite.taskUsecase.ReopenTask(context.Background(), open.Hex(), testOwnerID.Hex(), "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotCompleted)          // not completed yet
+
+	_, err = suite.taskUsecase.ReopenTask(context.Background(), done.Hex(), primitive.NewObjectID().Hex(), "user")
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)              // another user's task
+
+	locked := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, LockCompleted: true})
+	_, err = locked.ReopenTask(context.Background(), done.Hex(), testOwnerID.Hex(), "user")
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskCompletedLocked)       // completed tasks locked to admins
+	suite.mockRepo.AssertNotCalled(suite.T(), "ReopenTask", mock.Anything, mock.Anything)
+}
+
+// tests bulk completion only asks the repository for statuses that may move to completed
+func (suite *TaskUseCaseTestSuite) TestCompleteTasks() {
+
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	ids := []string{first.Hex(), second.Hex()}
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{first, second}).Return([]domain.Task{{ID: second}, {ID: first}}, nil)
+	suite.mockRepo.On("CompleteTasks", mock.Anything, ids, []string{"in_progress", "pending"}).Return(int64(2), nil)
+
+	completed, err := suite.taskUsecase.CompleteTasks(context.Background(), ids)
+	assert.NoError(suite.T(), err)                       // no error
+	assert.Equal(suite.T(), int64(2), completed)         // modified count passed through
+}
+
+// tests bulk completion skips tasks blocked by unfinished dependencies, as UpdateTask does
+func (suite *TaskUseCaseTestSuite) TestCompleteTasks_SkipsBlocked() {
+
+	blocked, free, running, done := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{blocked, free}).Return([]domain.Task{
+		{ID: blocked, Status: "pending", DependsOn: []primitive.ObjectID{running, done}},
+		{ID: free, Status: "pending", DependsOn: []primitive.ObjectID{done}},
+	}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{running, done, done}).Return([]domain.Task{
+		{ID: running, Status: "in_progress"},
+		{ID: done, Status: "completed"},
+	}, nil)
+	suite.mockRepo.On("CompleteTasks", mock.Anything, []string{free.Hex()}, []string{"in_progress", "pending"}).Return(int64(1), nil)
+
+	completed, err := suite.taskUsecase.CompleteTasks(context.Background(), []string{blocked.Hex(), free.Hex()})
+	assert.NoError(suite.T(), err)                       // no error
+	assert.Equal(suite.T(), int64(1), completed)         // the blocked task is not counted
+	suite.mockRepo.AssertCalled(suite.T(), "CompleteTasks", mock.Anything, []string{free.Hex()}, mock.Anything)      // only the free task is sent
+}
+
+// tests bulk completion validates the ids before touching the repository
+func (suite *TaskUseCaseTestSuite) TestCompleteTasks_Invalid() {
+
+	_, err := suite.taskUsecase.CompleteTasks(context.Background(), []string{"invalid"})
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTaskID)       // invalid task id
+
+	ids := make([]string, MaxBulkTasks+1)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID().Hex()
+	}
+	_, err = suite.taskUsecase.CompleteTasks(context.Background(), ids)
+	assert.ErrorIs(suite.T(), err, domain.ErrTooManyTasks)        // too many ids
+	suite.mockRepo.AssertNotCalled(suite.T(), "CompleteTasks", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a task cannot be created already started while a dependency is unfinished
+func (suite *TaskUseCaseTestSuite) TestCreateTask_BlockedByDependency() {
+
+	dep := primitive.NewObjectID()
+	task := &domain.Task{
+		Title:       "Deploy",
+		Description: "Ship the release",
+		DueDate:     time.Now().Add(48 * time.Hour),
+		Status:      "in_progress",
+		Priority:    "high",
+		DependsOn:   []primitive.ObjectID{dep},
+	}
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{dep}).Return([]domain.Task{{ID: dep, Status: "in_progress"}}, nil)
+
+	result, err := suite.taskUsecase.CreateTask(context.Background(), task, testOwnerID.Hex())
+	assert.Nil(suite.T(), result)                                // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskBlocked)        // dependency still running
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+}
+
+// tests GetDependencies lists the tasks a task depends on
+func (suite *TaskUseCaseTestSuite) TestGetDependencies() {
+
+	id := primitive.NewObjectID()
+	dep := primitive.NewObjectID()
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(&domain.Task{ID: id, DependsOn: []primitive.ObjectID{dep}}, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{dep}).Return([]domain.Task{{ID: dep, Title: "Build"}}, nil)
+
+	deps, err := suite.taskUsecase.GetDependencies(context.Background(), id.Hex())
+	assert.NoError(suite.T(), err)                      // no error expected
+	assert.Len(suite.T(), deps, 1)                      // one dependency
+	assert.Equal(suite.T(), "Build", deps[0].Title)
+}
+
 // tests GetTaskByID with empty id
 func (suite *TaskUseCaseTestSuite) TestGetTaskByID_EmptyID() {
 
 	// call the GetTaskByID method on usecase
-    result, err := suite.taskUsecase.GetTaskByID("")
+    result, err := suite.taskUsecase.GetTaskByID(context.Background(), "")
     assert.Nil(suite.T(), result)                                        // result should be nil
     assert.EqualError(suite.T(), err, "task ID cannot be empty")         // error message should match expected
 }
@@ -207,11 +1184,11 @@ func (suite *TaskUseCaseTestSuite) TestGetTaskByID_NotFound() {
 
 	// mock GetTaskByID of the repository to return an nil and nil
     suite.mockRepo.
-        On("GetTaskByID", id).
+        On("GetTaskByID", mock.Anything, id).
         Return(nil, nil)
 
 	// call the GetTaskByID method on usecase
-    result, err := suite.taskUsecase.GetTaskByID(id)
+    result, err := suite.taskUsecase.GetTaskByID(context.Background(), id)
     assert.Nil(suite.T(), result)                                    // result should be nil
     assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)           // error message should match expected
 }
@@ -221,11 +1198,11 @@ func (suite *TaskUseCaseTestSuite) TestGetAllTasks_RepoReturnsNil() {
     
 	// mock GetTaskByID of the repository to return an nil and nil
 	suite.mockRepo.
-        On("GetAllTasks").
+        On("GetAllTasks", mock.Anything).
         Return(nil, nil)
 
 	// call the GetTaskByID method on usecase
-    result, err := suite.taskUsecase.GetAllTasks()
+    result, err := suite.taskUsecase.GetAllTasks(context.Background())
     assert.NoError(suite.T(), err)                 // no error should exist
     assert.NotNil(suite.T(), result)               // result should not be nil
     assert.Len(suite.T(), result, 0)               // length of result should be 0
@@ -238,7 +1215,7 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask_EmptyID() {
     task := &domain.Task{Title: "title"}
 
 	// call the UpdateTask method on usecase
-    result, err := suite.taskUsecase.UpdateTask("", task)
+    result, err := suite.taskUsecase.UpdateTask(context.Background(), "", task, "admin")
     assert.Nil(suite.T(), result)                                        // result should be nil
     assert.EqualError(suite.T(), err, "task ID cannot be empty")         // error message should match expected
 }
@@ -252,7 +1229,7 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask_NoValidFields() {
     task := &domain.Task{}
 
 	// call the UpdateTask method on usecase
-    result, err := suite.taskUsecase.UpdateTask(id, task)
+    result, err := suite.taskUsecase.UpdateTask(context.Background(), id, task, "admin")
     assert.Nil(suite.T(), result)                                                    // result should be nil
     assert.EqualError(suite.T(), err, "no valid fields provided for update")         // error message should match expected
 }
@@ -265,12 +1242,581 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask_InvalidDueDate() {
 	// test task
     task := &domain.Task{DueDate: time.Now().Add(-1 * time.Hour)}
 
+	// mock GetTaskByID of the repository to return a task with a different due date
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, id).
+		Return(&domain.Task{DueDate: time.Now().Add(-48 * time.Hour)}, nil)
+
 	// call the UpdateTask method on usecase
-    result, err := suite.taskUsecase.UpdateTask(id, task)
+    result, err := suite.taskUsecase.UpdateTask(context.Background(), id, task, "admin")
     assert.Nil(suite.T(), result)                                              // result should be nil
     assert.EqualError(suite.T(), err, "due date must be in the future")        // error message should match expected
 }
 
+// tests UpdateTask in lenient mode accepts an unchanged past due date
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_LenientUnchangedPastDueDate() {
+
+	// test task id
+	id := "some-id"
+	// task whose due date has already passed
+	pastDue := time.Now().Add(-24 * time.Hour)
+	task := &domain.Task{DueDate: pastDue, Status: "completed"}
+
+	// mock GetTaskByID of the repository to return the current task
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, id).
+		Return(&domain.Task{DueDate: pastDue, Status: "pending"}, nil)
+	// mock UpdateTask of the repository to return the completed task
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, id, task).
+		Return(task, nil)
+
+	// call the UpdateTask method on usecase
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), id, task, "admin")
+	assert.NoError(suite.T(), err)                             // no error expected
+	assert.Equal(suite.T(), "completed", result.Status)        // task should be completed
+}
+
+// tests UpdateTask in strict mode rejects any past due date
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_StrictUnchangedPastDueDate() {
+
+	// strict due date policy
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields: DefaultUpdatableTaskFields,
+		StrictDueDate:   true,
+	})
+	task := &domain.Task{DueDate: time.Now().Add(-24 * time.Hour), Status: "completed"}
+
+	// call the UpdateTask method on usecase
+	result, err := usecase.UpdateTask(context.Background(), "some-id", task, "admin")
+	assert.Nil(suite.T(), result)                                              // result should be nil
+	assert.EqualError(suite.T(), err, "due date must be in the future")        // error message should match expected
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything, mock.Anything)    // current task is not needed
+}
+
+// tests UpdateTask ignores fields outside the allowlist
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_IgnoresNonAllowlistedFields() {
+
+	// test task id
+	id := "some-id"
+	// update trying to overwrite the id alongside the title
+	task := &domain.Task{ID: primitive.NewObjectID(), Title: "new title"}
+
+	// mock UpdateTask of the repository to only accept the sanitized update
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, id, mock.MatchedBy(func(t *domain.Task) bool {
+			return t.ID.IsZero() && t.Title == "new title"
+		})).
+		Return(&domain.Task{Title: "new title"}, nil)
+
+	// call the UpdateTask method on usecase
+	result, err := suite.taskUsecase.UpdateTask(context.Background(), id, task, "admin")
+	assert.NoError(suite.T(), err)                        // no error expected
+	assert.Equal(suite.T(), "new title", result.Title)    // title should be updated
+	suite.mockRepo.AssertExpectations(suite.T())          // repository got the sanitized update
+}
+
+// tests UpdateTask with a narrower configured allowlist
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_ConfiguredAllowlist() {
+
+	// only the status may change
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: []string{"status"}})
+
+	// description is not allowlisted so nothing valid remains
+	result, err := usecase.UpdateTask(context.Background(), "some-id", &domain.Task{Description: "new description"}, "admin")
+	assert.Nil(suite.T(), result)                                                    // result should be nil
+	assert.EqualError(suite.T(), err, "no valid fields provided for update")         // error message should match expected
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+
+// tests the description limit counts bytes, so multibyte text hits it before its rune count would
+func (suite *TaskUseCaseTestSuite) TestCreateTask_DescriptionByteLimit() {
+
+	// 10 byte limit
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields:     DefaultUpdatableTaskFields,
+		MaxDescriptionBytes: 10,
+	})
+	newTask := func(description string) *domain.Task {
+		return &domain.Task{Title: "Test", Description: description, DueDate: time.Now().Add(48 * time.Hour)}
+	}
+	suite.mockRepo.On("CreateTask", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(&domain.Task{}, nil)
+
+	// exactly at the limit - five 2 byte runes
+	_, err := usecase.CreateTask(context.Background(), newTask("ééééé"), testOwnerID.Hex())
+	assert.NoError(suite.T(), err)                                                   // 10 bytes accepted
+
+	// one byte over the limit with only six runes
+	_, err = usecase.CreateTask(context.Background(), newTask("ééééé!"), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrDescriptionTooLong)                     // 11 bytes rejected
+
+	// 4 byte runes - three of them are already over the limit
+	_, err = usecase.CreateTask(context.Background(), newTask("😀😀😀"), testOwnerID.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrDescriptionTooLong)                     // 12 bytes in 3 runes rejected
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateTask", 1)                   // only the first task stored
+}
+
+// tests updates are held to the same description limit
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_DescriptionByteLimit() {
+
+	// 10 byte limit
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{
+		UpdatableFields:     DefaultUpdatableTaskFields,
+		MaxDescriptionBytes: 10,
+	})
+
+	// call the UpdateTask method on usecase
+	_, err := usecase.UpdateTask(context.Background(), "some-id", &domain.Task{Description: "ñññññx"}, "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrDescriptionTooLong)                           // 11 bytes rejected
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)   // nothing stored
+}
+
+
+// tests a page of a user's tasks is requested from the repository
+func (suite *TaskUseCaseTestSuite) TestGetTasksByOwner_Success() {
+
+	owner := primitive.NewObjectID().Hex()
+	suite.mockRepo.On("GetTasksByOwner", mock.Anything, owner, int64(2), int64(10)).Return(nil, int64(0), nil)
+
+	// call the GetTasksByOwner method on usecase
+	tasks, total, err := suite.taskUsecase.GetTasksByOwner(context.Background(), owner, 2, 10)
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), []domain.Task{}, tasks) // empty slice instead of nil
+	assert.Zero(suite.T(), total)                   // total passed through
+	suite.mockRepo.AssertExpectations(suite.T())    // page passed through
+}
+
+// tests invalid owner ids and page parameters are rejected
+func (suite *TaskUseCaseTestSuite) TestGetTasksByOwner_InvalidInput() {
+
+	owner := primitive.NewObjectID().Hex()
+
+	_, _, err := suite.taskUsecase.GetTasksByOwner(context.Background(), "invalid", 1, 10)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)       // invalid owner id
+	_, _, err = suite.taskUsecase.GetTasksByOwner(context.Background(), owner, 0, 10)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidPage)         // page starts at 1
+	_, _, err = suite.taskUsecase.GetTasksByOwner(context.Background(), owner, 1, MaxPageSize+1)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidPageSize)     // page size too large
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTasksByOwner", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a user without assigned tasks gets an empty list
+func (suite *TaskUseCaseTestSuite) TestGetTasksByUser_Empty() {
+
+	user := primitive.NewObjectID().Hex()
+	suite.mockRepo.On("GetTasksByUser", mock.Anything, user).Return(nil, nil)
+
+	// call the GetTasksByUser method on usecase
+	tasks, err := suite.taskUsecase.GetTasksByUser(context.Background(), user)
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), []domain.Task{}, tasks) // empty slice instead of nil
+}
+
+// tests the tasks assigned to a user are returned
+func (suite *TaskUseCaseTestSuite) TestGetTasksByUser_Populated() {
+
+	user := primitive.NewObjectID()
+	assigned := []domain.Task{{Title: "first", AssignedTo: user}, {Title: "second", AssignedTo: user}}
+	suite.mockRepo.On("GetTasksByUser", mock.Anything, user.Hex()).Return(assigned, nil)
+
+	// call the GetTasksByUser method on usecase
+	tasks, err := suite.taskUsecase.GetTasksByUser(context.Background(), user.Hex())
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), assigned, tasks)        // repository result passed through
+}
+
+// tests invalid user ids are rejected
+func (suite *TaskUseCaseTestSuite) TestGetTasksByUser_InvalidID() {
+
+	// call the GetTasksByUser method on usecase
+	_, err := suite.taskUsecase.GetTasksByUser(context.Background(), "invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)       // invalid user id
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetTasksByUser", mock.Anything, mock.Anything)
+}
+
+// tests owner stats include requested owners without tasks
+func (suite *TaskUseCaseTestSuite) TestGetStatsByOwner_Success() {
+
+	busy, idle := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+	suite.mockRepo.
+		On("CountByOwnerStatus", mock.Anything, []string{busy, idle}).
+		Return(map[string]map[string]int64{busy: {"pending": 3}}, nil)
+
+	// call the GetStatsByOwner method on usecase
+	stats, err := suite.taskUsecase.GetStatsByOwner(context.Background(), []string{busy, idle})
+	assert.NoError(suite.T(), err)                                      // no error expected
+	assert.Equal(suite.T(), map[string]int64{"pending": 3}, stats[busy]) // counts passed through
+	assert.Equal(suite.T(), map[string]int64{}, stats[idle])             // owner without tasks still listed
+}
+
+// tests invalid and too many owner ids are rejected
+func (suite *TaskUseCaseTestSuite) TestGetStatsByOwner_InvalidInput() {
+
+	_, err := suite.taskUsecase.GetStatsByOwner(context.Background(), []string{"invalid"})
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)       // invalid owner id
+
+	owners := make([]string, MaxStatsOwners+1)
+	for i := range owners {
+		owners[i] = primitive.NewObjectID().Hex()
+	}
+	_, err = suite.taskUsecase.GetStatsByOwner(context.Background(), owners)
+	assert.ErrorIs(suite.T(), err, domain.ErrTooManyOwners)       // batch too large
+	suite.mockRepo.AssertNotCalled(suite.T(), "CountByOwnerStatus", mock.Anything, mock.Anything)
+}
+
+// tests a task is assigned to an existing user
+func (suite *TaskUseCaseTestSuite) TestAssignTask_Success() {
+
+	// usecase that can look up users
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID}, nil)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(&domain.Task{}, nil)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, taskID, &domain.Task{AssignedTo: userID}).
+		Return(&domain.Task{AssignedTo: userID}, nil)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), primitive.NewObjectID().Hex(), "admin")
+	assert.NoError(suite.T(), err)                 // no error expected
+	suite.mockRepo.AssertExpectations(suite.T())   // only the assignee is written
+}
+
+// tests assigning to an unknown user fails without touching the task
+func (suite *TaskUseCaseTestSuite) TestAssignTask_UserNotFound() {
+
+	// usecase that can look up users
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	userID := primitive.NewObjectID()
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(nil, domain.ErrUserNotFound)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), primitive.NewObjectID().Hex(), userID.Hex(), primitive.NewObjectID().Hex(), "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)                           // assignee must exist
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests assigning an unknown task fails
+func (suite *TaskUseCaseTestSuite) TestAssignTask_TaskNotFound() {
+
+	// usecase that can look up users
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID}, nil)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(nil, domain.ErrTaskNotFound)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), primitive.NewObjectID().Hex(), "admin")
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskNotFound)                           // task must exist
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a user may assign a task to themselves when self assignment is enabled
+func (suite *TaskUseCaseTestSuite) TestAssignTask_UserSelfAssign() {
+
+	// usecase allowing self assignment
+	userRepo := new(mock_repositories.MockUserRepository)
+	config := DefaultTaskConfig()
+	config.SelfAssign = true
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, config)
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID}, nil)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(&domain.Task{}, nil)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, taskID, &domain.Task{AssignedTo: userID}).
+		Return(&domain.Task{AssignedTo: userID}, nil)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), userID.Hex(), "user")
+	assert.NoError(suite.T(), err)                 // self assignment allowed
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// tests a user may not assign a task to someone else
+func (suite *TaskUseCaseTestSuite) TestAssignTask_UserAssignOtherForbidden() {
+
+	// usecase allowing self assignment
+	userRepo := new(mock_repositories.MockUserRepository)
+	config := DefaultTaskConfig()
+	config.SelfAssign = true
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, config)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), "user")
+	assert.ErrorIs(suite.T(), err, domain.ErrAssignForbidden)                        // only admins assign to others
+	userRepo.AssertNotCalled(suite.T(), "GetUserById", mock.Anything, mock.Anything)                // nothing looked up
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests a user may not even self assign when self assignment is disabled
+func (suite *TaskUseCaseTestSuite) TestAssignTask_UserSelfAssignDisabled() {
+
+	// usecase with the default policy
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	userID := primitive.NewObjectID().Hex()
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), primitive.NewObjectID().Hex(), userID, userID, "user")
+	assert.ErrorIs(suite.T(), err, domain.ErrAssignForbidden)                        // only admins assign by default
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// builds a usecase allowing self assignment and locking in_progress assignees, with an in_progress task held by holder
+func (suite *TaskUseCaseTestSuite) lockedAssignment(taskID string, holder, assignee primitive.ObjectID) domain.TaskUseCase {
+
+	userRepo := new(mock_repositories.MockUserRepository)
+	config := DefaultTaskConfig()
+	config.SelfAssign = true
+	config.LockInProgressAssignee = true
+
+	userRepo.
+		On("GetUserById", mock.Anything, assignee).
+		Return(&domain.User{ID: assignee}, nil)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(&domain.Task{Status: "in_progress", AssignedTo: holder}, nil)
+
+	return NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, config)
+}
+
+// tests a user cannot take an in_progress task from its assignee
+func (suite *TaskUseCaseTestSuite) TestAssignTask_InProgressUserBlocked() {
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+	usecase := suite.lockedAssignment(taskID, primitive.NewObjectID(), userID)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), userID.Hex(), "user")
+	assert.ErrorIs(suite.T(), err, domain.ErrTaskInProgress)                        // work already started elsewhere
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests an admin may reassign an in_progress task
+func (suite *TaskUseCaseTestSuite) TestAssignTask_InProgressAdminAllowed() {
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+	usecase := suite.lockedAssignment(taskID, primitive.NewObjectID(), userID)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, taskID, &domain.Task{AssignedTo: userID}).
+		Return(&domain.Task{AssignedTo: userID}, nil)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), primitive.NewObjectID().Hex(), "admin")
+	assert.NoError(suite.T(), err)                 // admins override the lock
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// tests the assignee of an in_progress task may assign it to themselves again
+func (suite *TaskUseCaseTestSuite) TestAssignTask_InProgressSameAssignee() {
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+	usecase := suite.lockedAssignment(taskID, userID, userID)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, taskID, &domain.Task{AssignedTo: userID}).
+		Return(&domain.Task{AssignedTo: userID}, nil)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), userID.Hex(), "user")
+	assert.NoError(suite.T(), err)                 // nobody loses the task
+}
+
+// tests an admin may assign a task to another user
+func (suite *TaskUseCaseTestSuite) TestAssignTask_AdminAssignOther() {
+
+	// usecase allowing self assignment
+	userRepo := new(mock_repositories.MockUserRepository)
+	config := DefaultTaskConfig()
+	config.SelfAssign = true
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, config)
+
+	taskID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+
+	userRepo.
+		On("GetUserById", mock.Anything, userID).
+		Return(&domain.User{ID: userID}, nil)
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, taskID).
+		Return(&domain.Task{}, nil)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, taskID, &domain.Task{AssignedTo: userID}).
+		Return(&domain.Task{AssignedTo: userID}, nil)
+
+	// call the AssignTask method on usecase
+	err := usecase.AssignTask(context.Background(), taskID, userID.Hex(), primitive.NewObjectID().Hex(), "admin")
+	assert.NoError(suite.T(), err)                 // admins assign to anyone
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// tests UpdateTaskIfMatch updates a task whose ETag matches
+func (suite *TaskUseCaseTestSuite) TestUpdateTaskIfMatch_Success() {
+
+	id := "some-id"
+	current := &domain.Task{Title: "Old", UpdatedAt: time.Now()}
+	task := &domain.Task{Title: "New"}
+
+	// mock GetTaskByID and UpdateTask of the repository
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, id).
+		Return(current, nil)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, id, task).
+		Return(task, nil)
+
+	// call the UpdateTaskIfMatch method on usecase with the current ETag
+	result, err := suite.taskUsecase.UpdateTaskIfMatch(context.Background(), id, task, current.ETag(), "admin")
+	assert.NoError(suite.T(), err)                          // no error expected
+	assert.Equal(suite.T(), "New", result.Title)            // task should be updated
+}
+
+// tests every check of an update shares one lookup of the stored task, also behind If-Match
+func (suite *TaskUseCaseTestSuite) TestUpdateTask_LoadsCurrentOnce() {
+
+	projects := new(mock_repositories.MockProjectRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, nil, TaskConfig{UpdatableFields: DefaultUpdatableTaskFields, Projects: projects, LockCompleted: true})
+	id := primitive.NewObjectID()
+	dep := primitive.NewObjectID()
+	own := &domain.Project{ID: primitive.NewObjectID(), OwnerID: testOwnerID}
+	current := &domain.Task{ID: id, OwnerID: testOwnerID, Status: "in_progress", DueDate: time.Now().Add(-time.Hour), UpdatedAt: time.Now()}
+	suite.mockRepo.On("GetTaskByID", mock.Anything, id.Hex()).Return(current, nil)
+	suite.mockRepo.On("GetTasksByIDs", mock.Anything, []primitive.ObjectID{dep}).Return([]domain.Task{{ID: dep, Status: "completed"}}, nil)
+	projects.On("GetProjectByID", mock.Anything, own.ID).Return(own, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, id.Hex(), mock.Anything).Return(&domain.Task{ID: id, Status: "completed"}, nil)
+
+	// past due date echoed back, lock, transition, project and dependency checks all need the stored task
+	update := func() *domain.Task {
+		return &domain.Task{DueDate: current.DueDate, Status: "completed", ProjectID: own.ID, DependsOn: []primitive.ObjectID{dep}}
+	}
+	_, err := usecase.UpdateTask(context.Background(), id.Hex(), update(), "user")
+	assert.NoError(suite.T(), err)                                          // no error expected
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "GetTaskByID", 1)         // one lookup for every check
+
+	_, err = usecase.UpdateTaskIfMatch(context.Background(), id.Hex(), update(), current.ETag(), "user")
+	assert.NoError(suite.T(), err)                                          // no error expected
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "GetTaskByID", 2)         // the If-Match read is reused
+}
+
+// tests UpdateTaskIfMatch rejects an ETag of an older version
+func (suite *TaskUseCaseTestSuite) TestUpdateTaskIfMatch_Stale() {
+
+	id := "some-id"
+	read := &domain.Task{UpdatedAt: time.Now().Add(-time.Minute)}        // version the client read
+
+	// mock GetTaskByID of the repository to return a newer version
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, id).
+		Return(&domain.Task{UpdatedAt: time.Now()}, nil)
+
+	// call the UpdateTaskIfMatch method on usecase with the old ETag
+	result, err := suite.taskUsecase.UpdateTaskIfMatch(context.Background(), id, &domain.Task{Title: "New"}, read.ETag(), "admin")
+	assert.Nil(suite.T(), result)                                                      // result should be nil
+	assert.ErrorIs(suite.T(), err, domain.ErrPreconditionFailed)                       // should return precondition failed
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)      // task must not change
+}
+
+// tests UpdateTaskIfMatch accepts the wildcard ETag
+func (suite *TaskUseCaseTestSuite) TestUpdateTaskIfMatch_Wildcard() {
+
+	id := "some-id"
+	task := &domain.Task{Title: "New"}
+
+	// mock GetTaskByID and UpdateTask of the repository
+	suite.mockRepo.
+		On("GetTaskByID", mock.Anything, id).
+		Return(&domain.Task{UpdatedAt: time.Now()}, nil)
+	suite.mockRepo.
+		On("UpdateTask", mock.Anything, id, task).
+		Return(task, nil)
+
+	// call the UpdateTaskIfMatch method on usecase
+	_, err := suite.taskUsecase.UpdateTaskIfMatch(context.Background(), id, task, "*", "admin")
+	assert.NoError(suite.T(), err)          // any existing version matches
+}
+
+// tests a user's tasks move to an existing user
+func (suite *TaskUseCaseTestSuite) TestReassignTasks_Success() {
+
+	// usecase that can look up users
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	from := primitive.NewObjectID().Hex()
+	to := primitive.NewObjectID()
+
+	userRepo.
+		On("GetUserById", mock.Anything, to).
+		Return(&domain.User{ID: to}, nil)
+	suite.mockRepo.
+		On("ReassignOwner", mock.Anything, from, to.Hex()).
+		Return(int64(3), nil)
+
+	// call the ReassignTasks method on usecase
+	moved, err := usecase.ReassignTasks(context.Background(), from, to.Hex())
+	assert.NoError(suite.T(), err)                  // no error expected
+	assert.Equal(suite.T(), int64(3), moved)        // count is passed through
+}
+
+// tests reassigning to an unknown user moves nothing
+func (suite *TaskUseCaseTestSuite) TestReassignTasks_NewOwnerNotFound() {
+
+	// usecase that can look up users
+	userRepo := new(mock_repositories.MockUserRepository)
+	usecase := NewTaskUseCaseWithConfig(suite.mockRepo, userRepo, DefaultTaskConfig())
+
+	to := primitive.NewObjectID()
+	userRepo.
+		On("GetUserById", mock.Anything, to).
+		Return(nil, domain.ErrUserNotFound)
+
+	// call the ReassignTasks method on usecase
+	_, err := usecase.ReassignTasks(context.Background(), primitive.NewObjectID().Hex(), to.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)                               // new owner must exist
+	suite.mockRepo.AssertNotCalled(suite.T(), "ReassignOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// tests reassigning to the same user is rejected
+func (suite *TaskUseCaseTestSuite) TestReassignTasks_SameOwner() {
+
+	id := primitive.NewObjectID().Hex()
+
+	// call the ReassignTasks method on usecase
+	_, err := suite.taskUsecase.ReassignTasks(context.Background(), id, id)
+	assert.ErrorIs(suite.T(), err, domain.ErrSameOwner)       // nothing to move
+}
+
 // runs the test suite for TaskUseCase
 func TestTaskUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(TaskUseCaseTestSuite))        // run the test suite
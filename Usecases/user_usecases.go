@@ -2,25 +2,124 @@ package usecases
 
 // imports
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 
+// shortest password accepted on register and password change when no policy is configured
+const minPasswordLength = 8
+
+// character class a password policy can require
+type passwordClass struct {
+	matches  func(rune) bool
+	reason   string          // message when no character of the class is present
+}
+
+// character classes known to PasswordPolicy.RequiredClasses
+var passwordClasses = map[string]passwordClass{
+	"upper":    {unicode.IsUpper, "password must contain an uppercase letter"},
+	"lower":    {unicode.IsLower, "password must contain a lowercase letter"},
+	"digit":    {unicode.IsDigit, "password must contain a digit"},
+	"special":  {func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }, "password must contain a special character"},
+}
+
+// returns the password rules used without configuration - a minimum length and nothing else
+func DefaultPasswordPolicy() domain.PasswordPolicy {
+	return domain.PasswordPolicy{MinLength: minPasswordLength, RequiredClasses: []string{}}
+}
+
+// largest page size a user may prefer
+const MaxPageSize = 100
+
+// configurable user rules
+type UserConfig struct {
+	SeedAdminUsername    string          // only this username becomes admin on register (empty = first user is admin)
+	PasswordChangeGrace  time.Duration   // how long tokens issued before a password change keep working
+	Now                  func() time.Time   // clock used for the grace period (defaults to time.Now)
+	PasswordPolicy       domain.PasswordPolicy   // rules for new passwords (zero MinLength = DefaultPasswordPolicy length)
+}
+
 type userUseCase struct {
 	userRepo     domain.UserRepository
 	jwtService  domain.JWTService
 	pwdService   domain.PasswordService
+	config       UserConfig
 }
 
 // creates new UserUseCase instance
 func NewUserUseCase(userRepo domain.UserRepository, jwtServ domain.JWTService, pwdServ domain.PasswordService,) domain.UserUseCase {
-	return &userUseCase{ userRepo:userRepo, jwtService:jwtServ, pwdService:pwdServ}
+	return NewUserUseCaseWithConfig(userRepo, jwtServ, pwdServ, UserConfig{})
+}
+
+// creates new UserUseCase instance with custom user rules
+func NewUserUseCaseWithConfig(userRepo domain.UserRepository, jwtServ domain.JWTService, pwdServ domain.PasswordService, config UserConfig) domain.UserUseCase {
+	if config.Now == nil {
+		config.Now = time.Now        // default to the real clock
+	}
+	config.PasswordPolicy = normalizePasswordPolicy(config.PasswordPolicy)
+	return &userUseCase{ userRepo:userRepo, jwtService:jwtServ, pwdService:pwdServ, config:config}
+}
+
+// fills in the default length and drops unknown character classes with a warning
+func normalizePasswordPolicy(policy domain.PasswordPolicy) domain.PasswordPolicy {
+
+	if policy.MinLength <= 0 {
+		policy.MinLength = minPasswordLength
+	}
+	classes := []string{}
+	for _, class := range policy.RequiredClasses {
+		if _, ok := passwordClasses[class]; !ok {
+			log.Printf("warning: unknown password class %q ignored", class)
+			continue
+		}
+		classes = append(classes, class)
+	}
+	policy.RequiredClasses = classes
+
+	return policy
+}
+
+// rejects a password breaking the policy with a WeakPasswordError naming the first unmet rule
+func (userUsc *userUseCase) ValidatePassword(password string) error {
+
+	policy := userUsc.config.PasswordPolicy
+	if len(password) < policy.MinLength {
+		return &domain.WeakPasswordError{Reason: fmt.Sprintf("password must be at least %d characters", policy.MinLength), Policy: policy}
+	}
+	// classes are checked in the configured order
+	for _, name := range policy.RequiredClasses {
+		class := passwordClasses[name]
+		if strings.IndexFunc(password, class.matches) < 0 {
+			return &domain.WeakPasswordError{Reason: class.reason, Policy: policy}
+		}
+	}
+
+	return nil
+}
+
+// trims and lowercases an email address, rejecting malformed ones and display names
+func normalizeEmail(email string) (string, error) {
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", domain.ErrInvalidEmail
+	}
+
+	return email, nil
 }
 
 // register user
-func (userUsc *userUseCase) Register(user *domain.User) error {
+func (userUsc *userUseCase) Register(ctx context.Context, user *domain.User) error {
 	
 	// validate input
 	if user.Username == "" {
@@ -29,17 +128,30 @@ func (userUsc *userUseCase) Register(user *domain.User) error {
 	if user.Password == "" {
 		return errors.New("password cannot be empty")
 	}
-	if len(user.Password) < 8 {
-		return errors.New("password must be at least 8 characters")
+	if err := userUsc.ValidatePassword(user.Password); err != nil {
+		return err
+	}
+	email, err := normalizeEmail(user.Email)
+	if err != nil {
+		return err
 	}
+	user.Email = email
 	// check if user already exists
-	existing, err := userUsc.userRepo.GetByUsername(user.Username)
+	existing, err := userUsc.userRepo.GetByUsername(ctx, user.Username)
 	if err != nil && err != domain.ErrUserNotFound {
 		return err
 	}
 	if existing != nil {
 		return domain.ErrUserExists
 	}
+	// emails are unique too, the unique index still catches concurrent registrations
+	existing, err = userUsc.userRepo.GetByEmail(ctx, user.Email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return err
+	}
+	if existing != nil {
+		return domain.ErrEmailExists
+	}
 
 	// hash password securely 
 	hashed, err := userUsc.pwdService.HashPassword(user.Password)
@@ -51,8 +163,16 @@ func (userUsc *userUseCase) Register(user *domain.User) error {
 	// set default role
 	user.Role = "user"
 
-	// first user becomes admin
-	count, err := userUsc.userRepo.GetUserCount()
+	// seed admin configured - only that username becomes admin
+	if userUsc.config.SeedAdminUsername != "" {
+		if user.Username == userUsc.config.SeedAdminUsername {
+			user.Role = "admin"
+		}
+		return userUsc.userRepo.CreateUser(ctx, user)
+	}
+
+	// otherwise first user becomes admin
+	count, err := userUsc.userRepo.GetUserCount(ctx)
 	if err != nil {
 		return err
 	}
@@ -60,11 +180,11 @@ func (userUsc *userUseCase) Register(user *domain.User) error {
 		user.Role = "admin"
 	}
 
-	return userUsc.userRepo.CreateUser(user)
+	return userUsc.userRepo.CreateUser(ctx, user)
 }
 
 // authenticate user
-func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *domain.User, error) {
+func (userUsc *userUseCase) Login(ctx context.Context, credentials *domain.Credentials) (string, *domain.User, error) {
 	
 	// validate input
 	if credentials.Username == "" || credentials.Password == "" {
@@ -72,7 +192,7 @@ func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *dom
 	}
 
 	// get user from repository
-	user, err := userUsc.userRepo.GetByUsername(credentials.Username)
+	user, err := userUsc.userRepo.GetByUsername(ctx, credentials.Username)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return "", nil, domain.ErrInvalidCredentials
@@ -80,6 +200,11 @@ func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *dom
 		return "", nil, err
 	}
 
+	// deleted users cannot log in, even if a lookup returned them
+	if user.Deleted {
+		return "", nil, domain.ErrInvalidCredentials
+	}
+
 	// verify password
 	if !userUsc.pwdService.CheckPassword(user.Password, credentials.Password) {
 		return "", nil, domain.ErrInvalidCredentials
@@ -101,8 +226,35 @@ func (userUsc *userUseCase) Login(credentials *domain.Credentials) (string, *dom
 	return token, returnUser, nil
 }
 
+// list one page of users with their passwords removed
+func (userUsc *userUseCase) ListUsers(ctx context.Context, page, pageSize int) ([]domain.User, error) {
+
+	// validate input
+	if page < 1 {
+		return nil, domain.ErrInvalidPage
+	}
+	if pageSize < 1 || pageSize > MaxPageSize {
+		return nil, domain.ErrInvalidPageSize
+	}
+
+	users, err := userUsc.userRepo.GetAllUsers(ctx, int64((page-1)*pageSize), int64(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// never hand password hashes out of the usecase layer
+	for i := range users {
+		users[i].Password = ""
+	}
+	if users == nil {
+		return []domain.User{}, nil
+	}
+
+	return users, nil
+}
+
 // promote a user to admin role (only admin can do this)
-func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
+func (userUsc *userUseCase) PromoteToAdmin(ctx context.Context, userID string) error {
 	
 	// validate input
 	if userID == "" {
@@ -114,8 +266,43 @@ func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
 		return domain.ErrInvalidUserID
 	}
 
+	// check if user exists, repository errors are passed on unchanged
+	_, err = userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	// update role
+	return userUsc.userRepo.UpdateRole(ctx, objID, "admin")
+}
+
+// delete user by id
+func (userUsc *userUseCase) DeleteUser(ctx context.Context, userID string) error {
+
+	// validate input
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+
+	return userUsc.userRepo.SoftDeleteUser(ctx, objID)        // keep the record, tasks still reference it
+}
+
+// demote admin back to a regular user
+func (userUsc *userUseCase) DemoteFromAdmin(ctx context.Context, userID string) error {
+
+	// validate input
+	if userID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+
 	// check if user exists
-	_, err = userUsc.userRepo.GetUserById(objID)
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return domain.ErrUserNotFound
@@ -123,6 +310,170 @@ func (userUsc *userUseCase) PromoteToAdmin(userID string) error {
 		return err
 	}
 
+	// keep at least one admin so the admin routes stay reachable
+	if user.Role == "admin" {
+		admins, err := userUsc.userRepo.GetAdminCount(ctx)
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return domain.ErrLastAdmin
+		}
+	}
+
 	// update role
-	return userUsc.userRepo.UpdateRole(objID, "admin")
-}
\ No newline at end of file
+	return userUsc.userRepo.UpdateRole(ctx, objID, "user")
+}
+
+// get the user's preferences
+func (userUsc *userUseCase) GetPreferences(ctx context.Context, userID string) (*domain.Preferences, error) {
+
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user.Preferences, nil
+}
+
+// validate and save the user's preferences
+func (userUsc *userUseCase) UpdatePreferences(ctx context.Context, userID string, prefs *domain.Preferences) error {
+
+	// validate input
+	if userID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+	if prefs.Timezone != "" {
+		if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+			return domain.ErrInvalidTimezone
+		}
+	}
+	if prefs.DefaultPriority != "" && !validTaskPriorities[prefs.DefaultPriority] {
+		return domain.ErrInvalidPriority
+	}
+	if prefs.PageSize < 0 || prefs.PageSize > MaxPageSize {
+		return domain.ErrInvalidPageSize
+	}
+
+	return userUsc.userRepo.UpdatePreferences(ctx, objID, *prefs)
+}
+
+
+// change user's password after verifying the current one
+func (userUsc *userUseCase) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+
+	// validate input
+	objID, err := primitive.ObjectIDFromHex(userID)        // convert string id to ObjectID
+	if err != nil {
+		return domain.ErrInvalidUserID
+	}
+	if oldPassword == "" || newPassword == "" {
+		return errors.New("old and new password are required")
+	}
+	if err := userUsc.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	// get user from repository
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	// verify current password
+	if !userUsc.pwdService.CheckPassword(user.Password, oldPassword) {
+		return domain.ErrInvalidCredentials
+	}
+
+	// hash new password securely
+	hashed, err := userUsc.pwdService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return userUsc.userRepo.UpdatePassword(ctx, objID, hashed)
+}
+
+// create a refresh token for a user who just logged in
+func (userUsc *userUseCase) IssueRefreshToken(userID string) (string, error) {
+
+	// validate input
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return "", domain.ErrInvalidUserID
+	}
+
+	return userUsc.jwtService.GenerateRefreshToken(userID)
+}
+
+// exchange a refresh token for a new access token
+func (userUsc *userUseCase) Refresh(ctx context.Context, refreshToken string) (string, error) {
+
+	// only valid, unexpired refresh tokens are accepted
+	userID, issuedAt, err := userUsc.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return "", domain.ErrUnauthorized
+	}
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", domain.ErrUnauthorized
+	}
+
+	// reload the user so the new token carries the current username and role
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return "", domain.ErrUnauthorized
+		}
+		return "", err
+	}
+	// refresh tokens from before a password change stop working like access tokens do
+	if !userUsc.issuedAfterPasswordChange(user, issuedAt) {
+		return "", domain.ErrUnauthorized
+	}
+
+	return userUsc.jwtService.GenerateToken(user.ID.Hex(), user.Username, user.Role)
+}
+
+// reject a token issued before the user's last password change once the grace period is over
+func (userUsc *userUseCase) CheckTokenIssuedAt(ctx context.Context, userID string, issuedAt time.Time) error {
+
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrUnauthorized
+	}
+
+	user, err := userUsc.userRepo.GetUserById(ctx, objID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrUnauthorized        // tokens of deleted users are not valid
+		}
+		return err
+	}
+	if !userUsc.issuedAfterPasswordChange(user, issuedAt) {
+		return domain.ErrUnauthorized
+	}
+
+	return nil
+}
+
+// reports whether a token issued at the given time survives the user's last password change
+func (userUsc *userUseCase) issuedAfterPasswordChange(user *domain.User, issuedAt time.Time) bool {
+
+	// password never changed, or token issued after the change (iat has second precision)
+	validAfter := user.TokensValidAfter
+	if validAfter.IsZero() || !issuedAt.Before(validAfter.Truncate(time.Second)) {
+		return true
+	}
+
+	// old tokens keep working for the grace period, e.g. for other open sessions
+	return userUsc.config.Now().Before(validAfter.Add(userUsc.config.PasswordChangeGrace))
+}
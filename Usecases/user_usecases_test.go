@@ -2,8 +2,10 @@ package usecases
 
 // imports
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Domain"
 	"github.com/natnael-eyuel-dev/Task-Management-Unit-Test/Infrastructure/mocks"
@@ -40,11 +42,16 @@ func (suite *UserUseCaseTestSuite) TestRegister_SuccessFirstUserBecomesAdmin() {
 	user := &domain.User{
 		Username: "testuser", 
 		Password: "password123",
+		Email:    "testuser@example.com",
 	}
 
 	// mock GetByUsername of the repository to return error 
 	suite.userRepo.
-		On("GetByUsername", user.Username).
+		On("GetByUsername", mock.Anything, user.Username).
+		Return(nil, domain.ErrUserNotFound)
+	// mock GetByEmail of the repository to report the email as free
+	suite.userRepo.
+		On("GetByEmail", mock.Anything, user.Email).
 		Return(nil, domain.ErrUserNotFound)
 	// mock HashPassword of the password service to return hashed password
 	suite.pwdService.
@@ -52,15 +59,15 @@ func (suite *UserUseCaseTestSuite) TestRegister_SuccessFirstUserBecomesAdmin() {
 		Return("hashedpass", nil)
 	// mock GetUserCount of the repository to return 0 - first user
 	suite.userRepo.
-		On("GetUserCount").
+		On("GetUserCount", mock.Anything).
 		Return(int64(0), nil)
 	// mock CreateUser of the repository to return nil - successful creation
 	suite.userRepo.
-		On("CreateUser", mock.AnythingOfType("*domain.User")).
+		On("CreateUser", mock.Anything, mock.AnythingOfType("*domain.User")).
 		Return(nil)
 
 	// call the Register method on usecase
-	err := suite.usecase.Register(user)
+	err := suite.usecase.Register(context.Background(), user)
 
 	// verify results
 	assert.NoError(suite.T(), err)                             // no error expected
@@ -69,18 +76,68 @@ func (suite *UserUseCaseTestSuite) TestRegister_SuccessFirstUserBecomesAdmin() {
 	suite.pwdService.AssertExpectations(suite.T())             // verify password service was called
 }
 
+// tests the configured seed admin username becomes admin regardless of user count
+func (suite *UserUseCaseTestSuite) TestRegister_SeedAdminBecomesAdmin() {
+
+	// usecase with a seed admin configured
+	usecase := NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		SeedAdminUsername: "root",
+	})
+	user := &domain.User{Username: "root", Password: "password123", Email: "root@example.com"}
+
+	// mock repository and password service
+	suite.userRepo.On("GetByUsername", mock.Anything, user.Username).Return(nil, domain.ErrUserNotFound)
+	suite.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(nil, domain.ErrUserNotFound)
+	suite.pwdService.On("HashPassword", user.Password).Return("hashedpass", nil)
+	suite.userRepo.On("CreateUser", mock.Anything, user).Return(nil)
+
+	// call the Register method on usecase
+	err := usecase.Register(context.Background(), user)
+
+	// verify results
+	assert.NoError(suite.T(), err)                                      // no error expected
+	assert.Equal(suite.T(), "admin", user.Role)                         // seed user should be admin
+	suite.userRepo.AssertNotCalled(suite.T(), "GetUserCount", mock.Anything)           // count rule is disabled
+}
+
+// tests the first user is not admin when a different seed admin is configured
+func (suite *UserUseCaseTestSuite) TestRegister_SeedAdminFirstUserStaysUser() {
+
+	// usecase with a seed admin configured
+	usecase := NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		SeedAdminUsername: "root",
+	})
+	user := &domain.User{Username: "first", Password: "password123", Email: "first@example.com"}
+
+	// mock repository and password service
+	suite.userRepo.On("GetByUsername", mock.Anything, user.Username).Return(nil, domain.ErrUserNotFound)
+	suite.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(nil, domain.ErrUserNotFound)
+	suite.pwdService.On("HashPassword", user.Password).Return("hashedpass", nil)
+	suite.userRepo.On("GetUserCount", mock.Anything).Return(int64(0), nil)
+	suite.userRepo.On("CreateUser", mock.Anything, user).Return(nil)
+
+	// call the Register method on usecase
+	err := usecase.Register(context.Background(), user)
+
+	// verify results
+	assert.NoError(suite.T(), err)                                      // no error expected
+	assert.Equal(suite.T(), "user", user.Role)                          // first user should stay a regular user
+	suite.userRepo.AssertNotCalled(suite.T(), "GetUserCount", mock.Anything)           // count rule is disabled
+}
+
 // tests registration with existing username
 func (suite *UserUseCaseTestSuite) TestRegister_AlreadyExists() {
 
 	// mock GetByUsername of the repository to return error
 	suite.userRepo.
-		On("GetByUsername", "testuser").
+		On("GetByUsername", mock.Anything, "testuser").
 		Return(nil, domain.ErrUserExists)
 
 	// call the Register method on usecase
-	err := suite.usecase.Register(&domain.User{
+	err := suite.usecase.Register(context.Background(), &domain.User{
 		Username: "testuser", 
 		Password: "somepass",
+		Email:    "testuser@example.com",
 	})
 
 	// verify error response
@@ -91,13 +148,91 @@ func (suite *UserUseCaseTestSuite) TestRegister_AlreadyExists() {
 func (suite *UserUseCaseTestSuite) TestRegister_InvalidPassword() {
 	
 	// call the Register method with invalid password on usecase
-	err := suite.usecase.Register(&domain.User{
+	err := suite.usecase.Register(context.Background(), &domain.User{
 		Username: "user", 
 		Password: "123",
 	})
 
 	// verify error response
 	assert.ErrorContains(suite.T(), err, "at least 8 characters")      // error should match expected message
+
+	var weak *domain.WeakPasswordError
+	assert.ErrorIs(suite.T(), err, domain.ErrWeakPassword)              // reported as a weak password
+	assert.ErrorAs(suite.T(), err, &weak)                               // carries the policy
+	assert.Equal(suite.T(), 8, weak.Policy.MinLength)                   // policy names the minimum length
+}
+
+// usecase requiring every character class on top of a longer minimum
+func (suite *UserUseCaseTestSuite) strictUsecase() domain.UserUseCase {
+	return NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		PasswordPolicy: domain.PasswordPolicy{MinLength: 10, RequiredClasses: []string{"upper", "digit", "special"}},
+	})
+}
+
+// tests each rule of a stricter policy names itself when unmet
+func (suite *UserUseCaseTestSuite) TestValidatePassword_StrictPolicy() {
+
+	usecase := suite.strictUsecase()
+	cases := map[string]string{
+		"Ab1!":          "at least 10 characters",        // too short
+		"lowercase1!":   "uppercase letter",              // no uppercase
+		"Uppercase!!":   "digit",                         // no digit
+		"Uppercase11":   "special character",             // no special character
+	}
+
+	for password, reason := range cases {
+		err := usecase.ValidatePassword(password)
+		assert.ErrorContains(suite.T(), err, reason, password)              // first unmet rule is named
+		assert.ErrorIs(suite.T(), err, domain.ErrWeakPassword, password)    // reported as a weak password
+	}
+
+	assert.NoError(suite.T(), usecase.ValidatePassword("Uppercase1!"))      // every rule met
+}
+
+// tests the first unmet rule is reported when several are unmet
+func (suite *UserUseCaseTestSuite) TestValidatePassword_FirstUnmetRule() {
+
+	err := suite.strictUsecase().ValidatePassword("alllowercase")
+
+	var weak *domain.WeakPasswordError
+	assert.ErrorAs(suite.T(), err, &weak)
+	assert.Equal(suite.T(), "password must contain an uppercase letter", weak.Reason)        // classes in configured order
+	assert.Equal(suite.T(), []string{"upper", "digit", "special"}, weak.Policy.RequiredClasses)   // policy is carried
+}
+
+// tests the default policy only requires the minimum length
+func (suite *UserUseCaseTestSuite) TestValidatePassword_DefaultPolicy() {
+
+	assert.NoError(suite.T(), suite.usecase.ValidatePassword("alllowercase"))        // no classes by default
+	assert.ErrorContains(suite.T(), suite.usecase.ValidatePassword("short"), "at least 8 characters")
+}
+
+// tests unknown classes in the policy are ignored
+func (suite *UserUseCaseTestSuite) TestValidatePassword_UnknownClass() {
+
+	usecase := NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		PasswordPolicy: domain.PasswordPolicy{RequiredClasses: []string{"emoji", "digit"}},
+	})
+
+	err := usecase.ValidatePassword("password")
+	assert.ErrorContains(suite.T(), err, "digit")                          // known class still enforced
+	assert.NoError(suite.T(), usecase.ValidatePassword("password1"))       // unknown class dropped
+}
+
+// tests registration applies a stricter policy
+func (suite *UserUseCaseTestSuite) TestRegister_StrictPolicy() {
+
+	err := suite.strictUsecase().Register(context.Background(), &domain.User{Username: "user", Password: "longenough1!", Email: "user@example.com"})
+	assert.ErrorContains(suite.T(), err, "uppercase letter")                             // rejected before any lookup
+	suite.userRepo.AssertNotCalled(suite.T(), "GetByUsername", mock.Anything, mock.Anything)
+}
+
+// tests password changes apply a stricter policy
+func (suite *UserUseCaseTestSuite) TestChangePassword_StrictPolicy() {
+
+	err := suite.strictUsecase().ChangePassword(context.Background(), primitive.NewObjectID().Hex(), "OldPassword1!", "NewPassword1")
+	assert.ErrorContains(suite.T(), err, "special character")                            // rejected before any lookup
+	suite.userRepo.AssertNotCalled(suite.T(), "GetUserById", mock.Anything, mock.Anything)
 }
 
 // tests registration with empty username
@@ -110,7 +245,7 @@ func (suite *UserUseCaseTestSuite) TestRegister_EmptyUsername() {
     }
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "username cannot be empty")      // error should match expected message
 }
 
@@ -124,7 +259,7 @@ func (suite *UserUseCaseTestSuite) TestRegister_EmptyPassword() {
     }
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "password cannot be empty")       // error should match expected message
 }
 
@@ -138,10 +273,71 @@ func (suite *UserUseCaseTestSuite) TestRegister_ShortPassword() {
     }
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "password must be at least 8 characters")      // error should match expected message
 }
 
+// tests registration with a malformed email
+func (suite *UserUseCaseTestSuite) TestRegister_InvalidEmail() {
+
+	for _, email := range []string{"", "not-an-email", "John <john@example.com>", "john@"} {
+		err := suite.usecase.Register(context.Background(), &domain.User{Username: "john", Password: "password123", Email: email})
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidEmail, email)       // error should be invalid email
+	}
+	suite.userRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)        // nothing stored
+}
+
+// tests registration stores the email trimmed and lowercased
+func (suite *UserUseCaseTestSuite) TestRegister_NormalizesEmail() {
+
+	user := &domain.User{Username: "john", Password: "password123", Email: "  John@Example.COM "}
+
+	// mock repository and password service
+	suite.userRepo.On("GetByUsername", mock.Anything, user.Username).Return(nil, domain.ErrUserNotFound)
+	suite.userRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(nil, domain.ErrUserNotFound)      // looked up normalized
+	suite.pwdService.On("HashPassword", user.Password).Return("hashedpass", nil)
+	suite.userRepo.On("GetUserCount", mock.Anything).Return(int64(1), nil)
+	suite.userRepo.On("CreateUser", mock.Anything, user).Return(nil)
+
+	// call the Register method on usecase
+	err := suite.usecase.Register(context.Background(), user)
+	assert.NoError(suite.T(), err)                                   // no error expected
+	assert.Equal(suite.T(), "john@example.com", user.Email)          // stored normalized
+}
+
+// tests registration with an email that is already taken
+func (suite *UserUseCaseTestSuite) TestRegister_DuplicateEmail() {
+
+	user := &domain.User{Username: "john", Password: "password123", Email: "john@example.com"}
+
+	// mock repository to find another user with the email
+	suite.userRepo.On("GetByUsername", mock.Anything, user.Username).Return(nil, domain.ErrUserNotFound)
+	suite.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(&domain.User{Username: "other", Email: user.Email}, nil)
+
+	// call the Register method on usecase
+	err := suite.usecase.Register(context.Background(), user)
+	assert.ErrorIs(suite.T(), err, domain.ErrEmailExists)                                    // error should be email exists
+	suite.pwdService.AssertNotCalled(suite.T(), "HashPassword", mock.Anything)               // rejected before hashing
+	suite.userRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)    // nothing stored
+}
+
+// tests registration losing a race for the email to another registration
+func (suite *UserUseCaseTestSuite) TestRegister_DuplicateEmailOnInsert() {
+
+	user := &domain.User{Username: "john", Password: "password123", Email: "john@example.com"}
+
+	// mock repository to report the email unique index violation
+	suite.userRepo.On("GetByUsername", mock.Anything, user.Username).Return(nil, domain.ErrUserNotFound)
+	suite.userRepo.On("GetByEmail", mock.Anything, user.Email).Return(nil, domain.ErrUserNotFound)
+	suite.pwdService.On("HashPassword", user.Password).Return("hashedpass", nil)
+	suite.userRepo.On("GetUserCount", mock.Anything).Return(int64(1), nil)
+	suite.userRepo.On("CreateUser", mock.Anything, user).Return(domain.ErrEmailExists)
+
+	// call the Register method on usecase
+	err := suite.usecase.Register(context.Background(), user)
+	assert.ErrorIs(suite.T(), err, domain.ErrEmailExists)        // error should be email exists
+}
+
 // tests Register when repository returns unexpected error on GetByUsername
 func (suite *UserUseCaseTestSuite) TestRegister_RepoErrorOnGetByUsername() {
     
@@ -149,15 +345,16 @@ func (suite *UserUseCaseTestSuite) TestRegister_RepoErrorOnGetByUsername() {
 	user := &domain.User{
         Username: "user",
         Password: "password123",
+        Email:    "user@example.com",
     }
 	
 	// mock GetByUsername of the repository to return nil and error
     suite.userRepo.
-        On("GetByUsername", user.Username).
+        On("GetByUsername", mock.Anything, user.Username).
         Return(nil, errors.New("db error"))
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "db error")        // error should match expected message
 }
 
@@ -168,11 +365,16 @@ func (suite *UserUseCaseTestSuite) TestRegister_HashPasswordError() {
 	user := &domain.User{
         Username: "user",
         Password: "password123",
+        Email:    "user@example.com",
     }
 
 	// mock GetByUsername of the repository to return and error
     suite.userRepo.
-        On("GetByUsername", user.Username).
+        On("GetByUsername", mock.Anything, user.Username).
+        Return(nil, domain.ErrUserNotFound)
+    // mock GetByEmail of the repository to report the email as free
+    suite.userRepo.
+        On("GetByEmail", mock.Anything, user.Email).
         Return(nil, domain.ErrUserNotFound)
 	// mock HashPassword of the repository to return empty string and error
     suite.pwdService.
@@ -180,11 +382,11 @@ func (suite *UserUseCaseTestSuite) TestRegister_HashPasswordError() {
         Return("", errors.New("hash error"))
 	// mock GetUserCount of the repository to return number and nil
     suite.userRepo.
-        On("GetUserCount").
+        On("GetUserCount", mock.Anything).
         Return(int64(1), nil)
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "hash error")       // error should match expected message
 }
 
@@ -195,11 +397,16 @@ func (suite *UserUseCaseTestSuite) TestRegister_GetUserCountError() {
 	user := &domain.User{
         Username: "user",
         Password: "password123",
+        Email:    "user@example.com",
     }
 
 	// mock GetByUsername of the repository to return nil and error
     suite.userRepo.
-        On("GetByUsername", user.Username).
+        On("GetByUsername", mock.Anything, user.Username).
+        Return(nil, domain.ErrUserNotFound)
+    // mock GetByEmail of the repository to report the email as free
+    suite.userRepo.
+        On("GetByEmail", mock.Anything, user.Email).
         Return(nil, domain.ErrUserNotFound)
 	// mock HashPassword of the repository to return error
     suite.pwdService.
@@ -207,11 +414,11 @@ func (suite *UserUseCaseTestSuite) TestRegister_GetUserCountError() {
         Return("hashedpass", nil)
 	// mock GetUserCount of the repository to return error
     suite.userRepo.
-        On("GetUserCount").
+        On("GetUserCount", mock.Anything).
         Return(int64(0), errors.New("count error"))
 
 	// call the Register method on usecase
-    err := suite.usecase.Register(user)
+    err := suite.usecase.Register(context.Background(), user)
     assert.EqualError(suite.T(), err, "count error")       // error should match expected message
 }
 
@@ -234,7 +441,7 @@ func (suite *UserUseCaseTestSuite) TestLogin_Success() {
 
 	// mock GetByUsername of the repository to return the test user
 	suite.userRepo.
-		On("GetByUsername", credentials.Username).
+		On("GetByUsername", mock.Anything, credentials.Username).
 		Return(user, nil)
 	// mock GetByUsername of the respository to return true
 	suite.pwdService.
@@ -246,7 +453,7 @@ func (suite *UserUseCaseTestSuite) TestLogin_Success() {
 		Return("token123", nil)
 
 	// call the Login method on usecase
-	token, returnUser, err := suite.usecase.Login(credentials)
+	token, returnUser, err := suite.usecase.Login(context.Background(), credentials)
 
 	// verify results
 	assert.NoError(suite.T(), err)                                 // no error expected
@@ -271,19 +478,46 @@ func (suite *UserUseCaseTestSuite) TestLogin_InvalidPassword() {
 
 	// mock GetByUsername of the repository to return the test user
 	suite.userRepo.
-		On("GetByUsername", creds.Username).Return(user, nil)
+		On("GetByUsername", mock.Anything, creds.Username).Return(user, nil)
 	// mock CheckPassword of the password service to return false
 	suite.pwdService.
 		On("CheckPassword", user.Password, creds.Password).
 		Return(false)
 
 	// call the Login method on usecase
-	_, _, err := suite.usecase.Login(creds)
+	_, _, err := suite.usecase.Login(context.Background(), creds)
 
 	// verify error response
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidCredentials)      // error should be invalid credentials
 }
 
+// tests login of a soft deleted user is rejected
+func (suite *UserUseCaseTestSuite) TestLogin_DeletedUser() {
+	
+	// create deleted test user and correct credentials
+	user := &domain.User{
+		Username: "user", 
+		Password: "hashed",
+		Deleted:  true,
+	}
+	creds := &domain.Credentials{
+		Username: "user", 
+		Password: "correct",
+	}
+
+	// mock GetByUsername of the repository to return the deleted user
+	suite.userRepo.
+		On("GetByUsername", mock.Anything, creds.Username).Return(user, nil)
+
+	// call the Login method on usecase
+	_, _, err := suite.usecase.Login(context.Background(), creds)
+
+	// verify error response
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidCredentials)                          // error should be invalid credentials
+	suite.pwdService.AssertNotCalled(suite.T(), "CheckPassword", mock.Anything, mock.Anything)      // password is never checked
+	suite.jwtService.AssertNotCalled(suite.T(), "GenerateToken", mock.Anything, mock.Anything, mock.Anything)     // no token issued
+}
+
 // tests login with non-existent user
 func (suite *UserUseCaseTestSuite) TestLogin_UserNotFound() {
 	
@@ -295,11 +529,11 @@ func (suite *UserUseCaseTestSuite) TestLogin_UserNotFound() {
 	
 	// mock GetByUsername of the repository to return error
 	suite.userRepo.
-		On("GetByUsername", creds.Username).
+		On("GetByUsername", mock.Anything, creds.Username).
 		Return(nil, domain.ErrUserNotFound)
 
 	// call the Login method on usecase
-	_, _, err := suite.usecase.Login(creds)
+	_, _, err := suite.usecase.Login(context.Background(), creds)
 
 	// verify error response
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidCredentials)      // error should be invalid credentials
@@ -315,7 +549,7 @@ func (suite *UserUseCaseTestSuite) TestLogin_EmptyCredentials() {
     }
 
 	// call the Login method on usecase
-    token, user, err := suite.usecase.Login(creds)
+    token, user, err := suite.usecase.Login(context.Background(), creds)
     assert.Empty(suite.T(), token)                            // token should be empty 
     assert.Nil(suite.T(), user)                               // user should be nil
     assert.EqualError(suite.T(), err, "username and password are required")      // error should match expected message
@@ -332,11 +566,11 @@ func (suite *UserUseCaseTestSuite) TestLogin_RepoErrorOnGetByUsername() {
 
 	// mock GetByUsername of the repository to return nil and error
     suite.userRepo.
-        On("GetByUsername", creds.Username).
+        On("GetByUsername", mock.Anything, creds.Username).
         Return(nil, errors.New("db error"))
 
 	// call the Login method on usecase
-    token, user, err := suite.usecase.Login(creds)
+    token, user, err := suite.usecase.Login(context.Background(), creds)
     assert.Empty(suite.T(), token)                       // token should be empty
     assert.Nil(suite.T(), user)                          // user should be nil
     assert.EqualError(suite.T(), err, "db error")        // error should match expected message
@@ -360,7 +594,7 @@ func (suite *UserUseCaseTestSuite) TestLogin_JWTGenerationError() {
 
 	// mock GetByUsername of the repository to return user and error
     suite.userRepo.
-        On("GetByUsername", creds.Username).
+        On("GetByUsername", mock.Anything, creds.Username).
         Return(user, nil)
 	// mock CheckPassword of the repository to return true
     suite.pwdService.
@@ -372,7 +606,7 @@ func (suite *UserUseCaseTestSuite) TestLogin_JWTGenerationError() {
         Return("", errors.New("jwt error"))
 
 	// call the Login method on usecase
-    token, returnUser, err := suite.usecase.Login(creds)
+    token, returnUser, err := suite.usecase.Login(context.Background(), creds)
     assert.Empty(suite.T(), token)                        // token should be empty 
     assert.Nil(suite.T(), returnUser)                     // return user should be nil
     assert.EqualError(suite.T(), err, "jwt error")        // error should match expected message
@@ -386,28 +620,133 @@ func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_Success() {
 	
 	// mock GetUserById of the repository to return a user
 	suite.userRepo.
-		On("GetUserById", id).
+		On("GetUserById", mock.Anything, id).
 		Return(&domain.User{ID: id}, nil)
 	// mock UpdateRole of the repository to return nil - successful promotion
 	suite.userRepo.
-		On("UpdateRole", id, "admin").
+		On("UpdateRole", mock.Anything, id, "admin").
 		Return(nil)
 
 	// call the PromoteToAdmin method on usecase
-	err := suite.usecase.PromoteToAdmin(id.Hex())
+	err := suite.usecase.PromoteToAdmin(context.Background(), id.Hex())
 
 	// verify results
 	assert.NoError(suite.T(), err)      // no error expected
 }
 
+// tests successful demotion of an admin while other admins remain
+func (suite *UserUseCaseTestSuite) TestDemoteFromAdmin_Success() {
+
+	// create test user ID
+	id := primitive.NewObjectID()
+
+	// mock GetUserById, GetAdminCount and UpdateRole of the repository
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(&domain.User{ID: id, Role: "admin"}, nil)
+	suite.userRepo.
+		On("GetAdminCount", mock.Anything).
+		Return(int64(2), nil)
+	suite.userRepo.
+		On("UpdateRole", mock.Anything, id, "user").
+		Return(nil)
+
+	// call the DemoteFromAdmin method on usecase
+	err := suite.usecase.DemoteFromAdmin(context.Background(), id.Hex())
+	assert.NoError(suite.T(), err)                  // no error expected
+	suite.userRepo.AssertExpectations(suite.T())    // role should be updated
+}
+
+// tests the last admin cannot be demoted
+func (suite *UserUseCaseTestSuite) TestDemoteFromAdmin_LastAdmin() {
+
+	// create test user ID
+	id := primitive.NewObjectID()
+
+	// mock GetUserById and GetAdminCount of the repository
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(&domain.User{ID: id, Role: "admin"}, nil)
+	suite.userRepo.
+		On("GetAdminCount", mock.Anything).
+		Return(int64(1), nil)
+
+	// call the DemoteFromAdmin method on usecase
+	err := suite.usecase.DemoteFromAdmin(context.Background(), id.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrLastAdmin)                                       // error should be last admin
+	suite.userRepo.AssertNotCalled(suite.T(), "UpdateRole", mock.Anything, mock.Anything, mock.Anything)      // role should not change
+}
+
+// tests demotion of a user that does not exist
+func (suite *UserUseCaseTestSuite) TestDemoteFromAdmin_UserNotFound() {
+
+	// create test user ID
+	id := primitive.NewObjectID()
+
+	// mock GetUserById of the repository to return error
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(nil, domain.ErrUserNotFound)
+
+	// call the DemoteFromAdmin method on usecase
+	err := suite.usecase.DemoteFromAdmin(context.Background(), id.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // error should be user not found
+}
+
 // tests PromoteToAdmin with empty user ID
 func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_EmptyID() {
     
 	// call the PromoteToAdmin method on usecase
-	err := suite.usecase.PromoteToAdmin("")
+	err := suite.usecase.PromoteToAdmin(context.Background(), "")
     assert.EqualError(suite.T(), err, "user ID cannot be empty")        // error should match expected message
 }
 
+// tests listing users blanks the hashed passwords
+func (suite *UserUseCaseTestSuite) TestListUsers_StripsPasswords() {
+
+	// mock GetAllUsers of the repository to return stored users
+	suite.userRepo.
+		On("GetAllUsers", mock.Anything, int64(20), int64(10)).
+		Return([]domain.User{
+			{ID: primitive.NewObjectID(), Username: "alice", Password: "$2a$10$hash1", Role: "admin"},
+			{ID: primitive.NewObjectID(), Username: "bob", Password: "$2a$10$hash2", Role: "user"},
+		}, nil)
+
+	// call the ListUsers method on usecase
+	users, err := suite.usecase.ListUsers(context.Background(), 3, 10)
+	assert.NoError(suite.T(), err)                // no error expected
+	assert.Len(suite.T(), users, 2)               // every user returned
+	for _, user := range users {
+		assert.Empty(suite.T(), user.Password)    // password should be blanked
+	}
+	assert.Equal(suite.T(), "alice", users[0].Username)       // other fields kept
+}
+
+// tests listing users when the repository fails
+func (suite *UserUseCaseTestSuite) TestListUsers_Error() {
+
+	// mock GetAllUsers of the repository to return error
+	suite.userRepo.
+		On("GetAllUsers", mock.Anything, int64(0), int64(20)).
+		Return(nil, errors.New("find error"))
+
+	// call the ListUsers method on usecase
+	users, err := suite.usecase.ListUsers(context.Background(), 1, 20)
+	assert.Nil(suite.T(), users)                          // result should be nil
+	assert.EqualError(suite.T(), err, "find error")       // error should match expected message
+}
+
+// tests listing users rejects pages out of range before querying
+func (suite *UserUseCaseTestSuite) TestListUsers_InvalidPage() {
+
+	_, err := suite.usecase.ListUsers(context.Background(), 0, 20)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidPage)           // pages start at 1
+
+	_, err = suite.usecase.ListUsers(context.Background(), 1, MaxPageSize+1)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidPageSize)       // page too large
+	suite.userRepo.AssertNotCalled(suite.T(), "GetAllUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // tests promotion with non-existent user
 func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_UserNotFound() {
 	
@@ -416,11 +755,11 @@ func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_UserNotFound() {
 
 	// mock GetUserById of the repository to return error
 	suite.userRepo.
-		On("GetUserById", id).
+		On("GetUserById", mock.Anything, id).
 		Return(nil, domain.ErrUserNotFound)
 
 	// call the PromoteToAdmin method on usecase
-	err := suite.usecase.PromoteToAdmin(id.Hex())
+	err := suite.usecase.PromoteToAdmin(context.Background(), id.Hex())
 
 	// verify error response
 	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // error should be user not found
@@ -431,11 +770,11 @@ func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_InvalidID() {
 
 	// mock GetUserById of the repository to return error
 	suite.userRepo.
-		On("GetUserById", mock.Anything).
+		On("GetUserById", mock.Anything, mock.Anything).
 		Return(nil, domain.ErrInvalidUserID)
 
 	// call the PromoteToAdmin method with invalid ID format
-	err := suite.usecase.PromoteToAdmin("invalid")
+	err := suite.usecase.PromoteToAdmin(context.Background(), "invalid")
 
 	// verify error response
 	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)      // error should be invalid user ID
@@ -449,18 +788,324 @@ func (suite *UserUseCaseTestSuite) TestPromoteToAdmin_UpdateRoleError() {
 
 	// mock GetUserById of the repository to return user and nil
     suite.userRepo.
-        On("GetUserById", id).
+        On("GetUserById", mock.Anything, id).
         Return(&domain.User{ID: id}, nil)
 	// mock UpdateRole of the repository to return error
     suite.userRepo.
-        On("UpdateRole", id, "admin").
+        On("UpdateRole", mock.Anything, id, "admin").
         Return(errors.New("update error"))
 
 	// call the PromoteToAdmin method on usecase
-    err := suite.usecase.PromoteToAdmin(id.Hex())
+    err := suite.usecase.PromoteToAdmin(context.Background(), id.Hex())
     assert.EqualError(suite.T(), err, "update error")       // error should match expected message
 }
 
+// tests saving valid preferences
+func (suite *UserUseCaseTestSuite) TestUpdatePreferences_Success() {
+
+	// mock user id and preferences
+	id := primitive.NewObjectID()
+	prefs := &domain.Preferences{DefaultPriority: "low"}
+
+	// mock UpdatePreferences of the repository to return nil
+	suite.userRepo.
+		On("UpdatePreferences", mock.Anything, id, *prefs).
+		Return(nil)
+
+	// call the UpdatePreferences method on usecase
+	err := suite.usecase.UpdatePreferences(context.Background(), id.Hex(), prefs)
+	assert.NoError(suite.T(), err)        // no error expected
+}
+
+// tests saving preferences with every field set
+func (suite *UserUseCaseTestSuite) TestUpdatePreferences_AllFields() {
+
+	// mock user id and preferences
+	id := primitive.NewObjectID()
+	prefs := &domain.Preferences{Timezone: "Europe/Berlin", DefaultPriority: "high", PageSize: MaxPageSize, Notifications: true}
+
+	// mock UpdatePreferences of the repository to return nil
+	suite.userRepo.
+		On("UpdatePreferences", mock.Anything, id, *prefs).
+		Return(nil)
+
+	// call the UpdatePreferences method on usecase
+	err := suite.usecase.UpdatePreferences(context.Background(), id.Hex(), prefs)
+	assert.NoError(suite.T(), err)        // no error expected
+}
+
+// tests saving preferences with an unknown timezone
+func (suite *UserUseCaseTestSuite) TestUpdatePreferences_InvalidTimezone() {
+
+	// call the UpdatePreferences method with unknown timezone
+	err := suite.usecase.UpdatePreferences(context.Background(), primitive.NewObjectID().Hex(), &domain.Preferences{Timezone: "Mars/Olympus"})
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTimezone)               // error should be invalid timezone
+}
+
+// tests saving preferences with a page size out of range
+func (suite *UserUseCaseTestSuite) TestUpdatePreferences_InvalidPageSize() {
+
+	for _, size := range []int{-1, MaxPageSize + 1} {
+		err := suite.usecase.UpdatePreferences(context.Background(), primitive.NewObjectID().Hex(), &domain.Preferences{PageSize: size})
+		assert.ErrorIs(suite.T(), err, domain.ErrInvalidPageSize)           // error should be invalid page size
+	}
+	suite.userRepo.AssertNotCalled(suite.T(), "UpdatePreferences", mock.Anything, mock.Anything, mock.Anything)      // nothing saved
+}
+
+// tests reading stored preferences
+func (suite *UserUseCaseTestSuite) TestGetPreferences_Success() {
+
+	// mock stored user with preferences
+	id := primitive.NewObjectID()
+	prefs := domain.Preferences{Timezone: "UTC", PageSize: 20}
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(&domain.User{ID: id, Preferences: prefs}, nil)
+
+	// call the GetPreferences method on usecase
+	result, err := suite.usecase.GetPreferences(context.Background(), id.Hex())
+	assert.NoError(suite.T(), err)                // no error expected
+	assert.Equal(suite.T(), &prefs, result)       // stored preferences returned
+}
+
+// tests saving preferences with an unknown priority
+func (suite *UserUseCaseTestSuite) TestUpdatePreferences_InvalidPriority() {
+
+	// call the UpdatePreferences method with unknown priority
+	err := suite.usecase.UpdatePreferences(context.Background(), primitive.NewObjectID().Hex(), &domain.Preferences{DefaultPriority: "urgent"})
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidPriority)               // error should be invalid priority
+	suite.userRepo.AssertNotCalled(suite.T(), "UpdatePreferences", mock.Anything, mock.Anything, mock.Anything)      // nothing saved
+}
+
+// tests successful password change
+func (suite *UserUseCaseTestSuite) TestChangePassword_Success() {
+
+	// mock stored user
+	id := primitive.NewObjectID()
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(&domain.User{ID: id, Password: "oldhash"}, nil)
+	// mock password checks and hashing
+	suite.pwdService.On("CheckPassword", "oldhash", "oldpassword").Return(true)
+	suite.pwdService.On("HashPassword", "newpassword").Return("newhash", nil)
+	// mock UpdatePassword of the repository to store the new hash
+	suite.userRepo.
+		On("UpdatePassword", mock.Anything, id, "newhash").
+		Return(nil)
+
+	// call the ChangePassword method on usecase
+	err := suite.usecase.ChangePassword(context.Background(), id.Hex(), "oldpassword", "newpassword")
+	assert.NoError(suite.T(), err)                      // no error expected
+	suite.userRepo.AssertExpectations(suite.T())        // new hash should be stored
+}
+
+// tests password change with a wrong current password
+func (suite *UserUseCaseTestSuite) TestChangePassword_WrongOldPassword() {
+
+	// mock stored user
+	id := primitive.NewObjectID()
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(&domain.User{ID: id, Password: "oldhash"}, nil)
+	// mock CheckPassword to reject the old password
+	suite.pwdService.On("CheckPassword", "oldhash", "wrongpassword").Return(false)
+
+	// call the ChangePassword method on usecase
+	err := suite.usecase.ChangePassword(context.Background(), id.Hex(), "wrongpassword", "newpassword")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidCredentials)                         // error should be invalid credentials
+	suite.userRepo.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)      // nothing should be stored
+}
+
+// tests password change with a too short new password
+func (suite *UserUseCaseTestSuite) TestChangePassword_ShortNewPassword() {
+
+	// call the ChangePassword method on usecase
+	err := suite.usecase.ChangePassword(context.Background(), primitive.NewObjectID().Hex(), "oldpassword", "short")
+	assert.EqualError(suite.T(), err, "password must be at least 8 characters")         // error should match expected message
+	suite.userRepo.AssertNotCalled(suite.T(), "GetUserById", mock.Anything, mock.Anything)              // user should not be loaded
+}
+
+// tests password change for a user that does not exist
+func (suite *UserUseCaseTestSuite) TestChangePassword_UserNotFound() {
+
+	// mock GetUserById of the repository to return error
+	id := primitive.NewObjectID()
+	suite.userRepo.
+		On("GetUserById", mock.Anything, id).
+		Return(nil, domain.ErrUserNotFound)
+
+	// call the ChangePassword method on usecase
+	err := suite.usecase.ChangePassword(context.Background(), id.Hex(), "oldpassword", "newpassword")
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)       // error should be user not found
+}
+
+// tests password change with an invalid user ID
+func (suite *UserUseCaseTestSuite) TestChangePassword_InvalidID() {
+
+	// call the ChangePassword method with invalid ID format
+	err := suite.usecase.ChangePassword(context.Background(), "invalid", "oldpassword", "newpassword")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)      // error should be invalid user ID
+}
+
+// tests a valid refresh token is exchanged for an access token with the current role
+func (suite *UserUseCaseTestSuite) TestRefresh_Success() {
+
+	// stored user promoted since the refresh token was issued
+	user := &domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: "admin"}
+	suite.jwtService.On("ValidateRefreshToken", "refresh.token").Return(user.ID.Hex(), time.Now(), nil)
+	suite.userRepo.On("GetUserById", mock.Anything, user.ID).Return(user, nil)
+	suite.jwtService.On("GenerateToken", user.ID.Hex(), "testuser", "admin").Return("new.access.token", nil)
+
+	// call the Refresh method on usecase
+	token, err := suite.usecase.Refresh(context.Background(), "refresh.token")
+	assert.NoError(suite.T(), err)                              // no error expected
+	assert.Equal(suite.T(), "new.access.token", token)          // new access token returned
+}
+
+// tests an invalid refresh token is rejected
+func (suite *UserUseCaseTestSuite) TestRefresh_InvalidToken() {
+
+	suite.jwtService.On("ValidateRefreshToken", "access.token").Return("", time.Time{}, errors.New("not a refresh token"))
+
+	// call the Refresh method on usecase
+	_, err := suite.usecase.Refresh(context.Background(), "access.token")
+	assert.ErrorIs(suite.T(), err, domain.ErrUnauthorized)                          // error should be unauthorized
+	suite.jwtService.AssertNotCalled(suite.T(), "GenerateToken", mock.Anything, mock.Anything, mock.Anything)      // no token issued
+}
+
+// tests a refresh token of a deleted user is rejected
+func (suite *UserUseCaseTestSuite) TestRefresh_UserNotFound() {
+
+	id := primitive.NewObjectID()
+	suite.jwtService.On("ValidateRefreshToken", "refresh.token").Return(id.Hex(), time.Now(), nil)
+	suite.userRepo.On("GetUserById", mock.Anything, id).Return(nil, domain.ErrUserNotFound)
+
+	// call the Refresh method on usecase
+	_, err := suite.usecase.Refresh(context.Background(), "refresh.token")
+	assert.ErrorIs(suite.T(), err, domain.ErrUnauthorized)       // error should be unauthorized
+}
+
+// tests a refresh token issued before a password change is rejected once the grace period is over
+func (suite *UserUseCaseTestSuite) TestRefresh_IssuedBeforePasswordChange() {
+
+	changedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	user := &domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: "user", TokensValidAfter: changedAt}
+	suite.jwtService.On("ValidateRefreshToken", "old.refresh.token").Return(user.ID.Hex(), changedAt.Add(-time.Hour), nil)
+	suite.jwtService.On("ValidateRefreshToken", "new.refresh.token").Return(user.ID.Hex(), changedAt.Add(time.Second), nil)
+	suite.userRepo.On("GetUserById", mock.Anything, user.ID).Return(user, nil)
+	suite.jwtService.On("GenerateToken", user.ID.Hex(), "testuser", "user").Return("new.access.token", nil)
+
+	usecase := NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		PasswordChangeGrace: 15 * time.Minute,
+		Now:                 func() time.Time { return changedAt.Add(time.Hour) },
+	})
+
+	// call the Refresh method on usecase
+	_, err := usecase.Refresh(context.Background(), "old.refresh.token")
+	assert.ErrorIs(suite.T(), err, domain.ErrUnauthorized)       // pre-change token rejected after the grace period
+	token, err := usecase.Refresh(context.Background(), "new.refresh.token")
+	assert.NoError(suite.T(), err)                              // post-change token still refreshes
+	assert.Equal(suite.T(), "new.access.token", token)
+	suite.jwtService.AssertNumberOfCalls(suite.T(), "GenerateToken", 1)        // only for the new token
+}
+
+// tests a refresh token is only issued for a valid user id
+func (suite *UserUseCaseTestSuite) TestIssueRefreshToken() {
+
+	id := primitive.NewObjectID().Hex()
+	suite.jwtService.On("GenerateRefreshToken", id).Return("refresh.token", nil)
+
+	token, err := suite.usecase.IssueRefreshToken(id)
+	assert.NoError(suite.T(), err)                              // no error expected
+	assert.Equal(suite.T(), "refresh.token", token)             // token from the jwt service
+
+	_, err = suite.usecase.IssueRefreshToken("invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)      // error should be invalid user ID
+}
+
+// tests tokens issued before a password change are rejected once the grace period is over
+func (suite *UserUseCaseTestSuite) TestCheckTokenIssuedAt() {
+
+	changedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := changedAt.Add(10 * time.Minute)
+	user := &domain.User{ID: primitive.NewObjectID(), TokensValidAfter: changedAt}
+	suite.userRepo.On("GetUserById", mock.Anything, user.ID).Return(user, nil)
+
+	// no grace period
+	usecase := NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		Now: func() time.Time { return now },
+	})
+	assert.ErrorIs(suite.T(), usecase.CheckTokenIssuedAt(context.Background(), user.ID.Hex(), changedAt.Add(-time.Hour)), domain.ErrUnauthorized)     // pre-change token rejected
+	assert.NoError(suite.T(), usecase.CheckTokenIssuedAt(context.Background(), user.ID.Hex(), changedAt.Add(time.Second)))                           // post-change token accepted
+	assert.NoError(suite.T(), usecase.CheckTokenIssuedAt(context.Background(), user.ID.Hex(), changedAt))                                             // issued in the same second
+
+	// pre-change token still inside a 15 minute grace period
+	usecase = NewUserUseCaseWithConfig(suite.userRepo, suite.jwtService, suite.pwdService, UserConfig{
+		PasswordChangeGrace: 15 * time.Minute,
+		Now:                 func() time.Time { return now },
+	})
+	assert.NoError(suite.T(), usecase.CheckTokenIssuedAt(context.Background(), user.ID.Hex(), changedAt.Add(-time.Hour)))       // accepted during grace
+}
+
+// tests tokens of users who never changed their password are accepted
+func (suite *UserUseCaseTestSuite) TestCheckTokenIssuedAt_NeverChanged() {
+
+	user := &domain.User{ID: primitive.NewObjectID()}
+	suite.userRepo.On("GetUserById", mock.Anything, user.ID).Return(user, nil)
+
+	assert.NoError(suite.T(), suite.usecase.CheckTokenIssuedAt(context.Background(), user.ID.Hex(), time.Time{}))       // even without iat
+}
+
+// tests tokens of unknown users are rejected
+func (suite *UserUseCaseTestSuite) TestCheckTokenIssuedAt_UserNotFound() {
+
+	id := primitive.NewObjectID()
+	suite.userRepo.On("GetUserById", mock.Anything, id).Return(nil, domain.ErrUserNotFound)
+
+	assert.ErrorIs(suite.T(), suite.usecase.CheckTokenIssuedAt(context.Background(), id.Hex(), time.Now()), domain.ErrUnauthorized)       // deleted user
+	assert.ErrorIs(suite.T(), suite.usecase.CheckTokenIssuedAt(context.Background(), "invalid", time.Now()), domain.ErrUnauthorized)     // invalid id
+}
+
+// tests successful deletion of a user
+func (suite *UserUseCaseTestSuite) TestDeleteUser_Success() {
+
+	id := primitive.NewObjectID()
+
+	// mock DeleteUser of the repository to succeed
+	suite.userRepo.
+		On("SoftDeleteUser", mock.Anything, id).
+		Return(nil)
+
+	// call the DeleteUser method on usecase
+	err := suite.usecase.DeleteUser(context.Background(), id.Hex())
+	assert.NoError(suite.T(), err)                      // no error expected
+	suite.userRepo.AssertExpectations(suite.T())        // verify mock was called
+}
+
+// tests deletion of a user that does not exist
+func (suite *UserUseCaseTestSuite) TestDeleteUser_NotFound() {
+
+	id := primitive.NewObjectID()
+
+	// mock DeleteUser of the repository to return not found
+	suite.userRepo.
+		On("SoftDeleteUser", mock.Anything, id).
+		Return(domain.ErrUserNotFound)
+
+	// call the DeleteUser method on usecase
+	err := suite.usecase.DeleteUser(context.Background(), id.Hex())
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)      // should return user not found error
+}
+
+// tests deletion with an invalid user id
+func (suite *UserUseCaseTestSuite) TestDeleteUser_InvalidID() {
+
+	// call the DeleteUser method on usecase
+	err := suite.usecase.DeleteUser(context.Background(), "invalid")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserID)                  // should return invalid user id error
+	suite.userRepo.AssertNotCalled(suite.T(), "SoftDeleteUser", mock.Anything, mock.Anything)   // repository should not be called
+}
+
 // runs the test suite for UserUseCase
 func TestUserUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UserUseCaseTestSuite))       // run the test suite